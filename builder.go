@@ -0,0 +1,100 @@
+package mung
+
+import "sync"
+
+// Builder assembles a configuration from multiple goroutines, e.g., to
+// collect search paths from concurrent discovery routines, and then freezes
+// it into a [Config] to be used as any other.
+//
+// Each method is safe for concurrent use. Since items added concurrently are
+// added in no particular order, the order of the subject, prefix, and suffix
+// strings in the frozen configuration is only defined among items added by
+// the same goroutine.
+//
+// The zero value is an empty Builder. A Builder must not be copied after
+// first use.
+type Builder struct {
+	mu     sync.Mutex
+	config Config
+
+	// Items added since the configuration was last updated, which are
+	// collected here so that adding items one at a time takes amortized
+	// constant time.
+	subject []string
+	remove  []string
+	prefix  []string
+	suffix  []string
+}
+
+// NewBuilder returns a new Builder of a configuration with the given options
+// applied.
+func NewBuilder(opts ...Option[Config]) *Builder {
+	return &Builder{config: Make(opts...)}
+}
+
+// Apply applies the given options to the configuration, in order, after any
+// items added so far.
+func (b *Builder) Apply(opts ...Option[Config]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flush()
+	b.config = Wrap(b.config, opts...)
+}
+
+// Add adds subject strings, as if by [WithSubjectItems].
+func (b *Builder) Add(subjects ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subject = append(b.subject, subjects...)
+}
+
+// Remove adds strings to remove, as if by [WithRemoveItems].
+func (b *Builder) Remove(removes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove = append(b.remove, removes...)
+}
+
+// Prefix adds strings to prepend, as if by [WithPrefixItems].
+func (b *Builder) Prefix(prefixes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prefix = append(b.prefix, prefixes...)
+}
+
+// Suffix adds strings to append, as if by [WithSuffixItems].
+func (b *Builder) Suffix(suffixes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.suffix = append(b.suffix, suffixes...)
+}
+
+// Freeze returns a copy of the configuration assembled so far, as if by
+// [Config.Clone], so that it is not affected by later changes to the
+// receiver, which remains usable.
+func (b *Builder) Freeze() Config {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flush()
+
+	return b.config.Clone()
+}
+
+// flush adds the items collected by the receiver to its configuration.
+// The receiver's mutex must be held.
+func (b *Builder) flush() {
+	b.config = Wrap(b.config,
+		When(len(b.subject) > 0, WithSubjectItems(b.subject...)),
+		When(len(b.remove) > 0, WithRemoveItems(b.remove...)),
+		When(len(b.prefix) > 0, WithPrefixItems(b.prefix...)),
+		When(len(b.suffix) > 0, WithSuffixItems(b.suffix...)),
+	)
+
+	b.subject, b.remove, b.prefix, b.suffix = nil, nil, nil, nil
+}
@@ -0,0 +1,103 @@
+package mung
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder(WithDelim(":"), WithSubject([]string{"/usr/bin"}))
+	b.Prefix("/usr/local/bin")
+	b.Add("/bin:/old/bin")
+	b.Remove("/old/bin")
+	b.Suffix("/opt/bin")
+
+	frozen := b.Freeze()
+	if got, want := frozen.String(), "/usr/local/bin:/usr/bin:/bin:/opt/bin"; got != want {
+		t.Errorf("Freeze().String() = %q, want %q", got, want)
+	}
+
+	b.Add("/sbin")
+	b.Apply(WithoutPrefix())
+
+	if got, want := frozen.String(), "/usr/local/bin:/usr/bin:/bin:/opt/bin"; got != want {
+		t.Errorf("Freeze().String() after Add() = %q, want %q", got, want)
+	}
+
+	if got, want := b.Freeze().String(), "/usr/bin:/bin:/sbin:/opt/bin"; got != want {
+		t.Errorf("Freeze().String() = %q, want %q", got, want)
+	}
+}
+
+func TestBuilderConcurrent(t *testing.T) {
+	var (
+		b  Builder
+		wg sync.WaitGroup
+	)
+
+	b.Apply(WithDelim(":"))
+
+	const n = 50
+
+	for i := range n {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			s := strconv.Itoa(i)
+			b.Add("a" + s)
+			b.Prefix("p" + s)
+			b.Suffix("s" + s)
+			b.Remove("a" + strconv.Itoa(i%2))
+			_ = b.Freeze().String()
+		}()
+	}
+
+	wg.Wait()
+
+	config := b.Freeze()
+
+	for name, tt := range map[string]struct {
+		got  []string
+		want int
+	}{
+		"Subject": {config.Subject(), n},
+		"Prefix":  {config.Prefix(), n},
+		"Suffix":  {config.Suffix(), n},
+		"Remove":  {config.Remove(), n},
+	} {
+		if len(tt.got) != tt.want {
+			t.Errorf("%s() has %d items, want %d", name, len(tt.got), tt.want)
+		}
+	}
+
+	got := slices.Collect(config.All())
+	if len(got) != 3*n-2 || slices.Contains(got, "a0") || slices.Contains(got, "a1") {
+		t.Errorf("All() = %v, want %d items without a0 and a1", got, 3*n-2)
+	}
+}
+
+func TestBuilderApplyOrder(t *testing.T) {
+	b := NewBuilder(WithDelim(":"))
+	b.Add("a")
+	b.Remove("b")
+	b.Apply(WithSubject(nil), WithoutRemove())
+	b.Add("b")
+
+	if got, want := b.Freeze().String(), "b"; got != want {
+		t.Errorf("Freeze().String() = %q, want %q", got, want)
+	}
+
+	const n = 100000
+
+	for i := range n {
+		b.Add(strconv.Itoa(i))
+	}
+
+	if got := len(b.Freeze().Subject()); got != n+1 {
+		t.Errorf("len(Freeze().Subject()) = %d, want %d", got, n+1)
+	}
+}
@@ -0,0 +1,148 @@
+package mung
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Clone returns a copy of the receiver that shares no slices or maps with it,
+// so that neither is affected by later changes to the other.
+//
+// Function-valued settings, such as predicate functions, the [FilterCache]
+// set with [WithFilterCache], readers set with [WithSubjectReader], and
+// sequences set with [WithSubjectSeq] and its variants are shared by both
+// copies.
+func (c Config) Clone() Config {
+	c.subject = slices.Clone(c.subject)
+	c.remove = slices.Clone(c.remove)
+	c.removals = slices.Clone(c.removals)
+	c.rewrites = slices.Clone(c.rewrites)
+	c.prefix = slices.Clone(c.prefix)
+	c.suffix = slices.Clone(c.suffix)
+	c.ensure = slices.Clone(c.ensure)
+	c.replace = maps.Clone(c.replace)
+	c.alt = slices.Clone(c.alt)
+	c.stages = slices.Clone(c.stages)
+	c.after = slices.Clone(c.after)
+	c.order = slices.Clone(c.order)
+	c.pins = slices.Clone(c.pins)
+	c.tags = slices.Clone(c.tags)
+
+	return c
+}
+
+// Equal returns true if and only if the receiver and other have equal
+// settings.
+//
+// Function values cannot be compared, so function-valued settings, such as
+// predicate functions and stages, are considered equal if both are set or
+// both are unset. The same applies to strings produced lazily, e.g., with
+// [WithSubjectReader] or [WithRemoveSeq], which are not read by Equal.
+// Delimiter patterns are equal if their source text is equal.
+func (c Config) Equal(other Config) bool {
+	return slices.Equal(c.subject, other.subject) &&
+		sameNil(c.sources.subject, other.sources.subject) &&
+		slices.Equal(c.remove, other.remove) &&
+		sameNil(c.sources.remove, other.sources.remove) &&
+		slices.Equal(c.removalRules(), other.removalRules()) &&
+		slices.EqualFunc(c.rewrites, other.rewrites, sameRewriter) &&
+		slices.Equal(c.prefix, other.prefix) &&
+		sameNil(c.sources.prefix, other.sources.prefix) &&
+		slices.Equal(c.suffix, other.suffix) &&
+		sameNil(c.sources.suffix, other.sources.suffix) &&
+		slices.Equal(c.ensure, other.ensure) &&
+		maps.Equal(c.replace, other.replace) &&
+		c.delim == other.delim && slices.Equal(c.alt, other.alt) &&
+		samePattern(c, other) && c.output == other.output &&
+		c.syntax == other.syntax && sameValue(c.tok, other.tok) &&
+		sameNil(c.quote, other.quote) &&
+		c.dedupe == other.dedupe && c.placing == other.placing &&
+		c.prepend == other.prepend && c.strict == other.strict &&
+		c.fold == other.fold && c.trim == other.trim &&
+		c.clean == other.clean && c.tilde == other.tilde &&
+		c.unicode == other.unicode && c.form == other.form &&
+		c.links == other.links && c.abs == other.abs &&
+		c.windows == other.windows &&
+		c.extended == other.extended && c.wsl == other.wsl &&
+		c.sameFile == other.sameFile && c.exist == other.exist &&
+		len(c.stages) == len(other.stages) && sameStages(c.after, other.after) &&
+		slices.Equal(c.pins, other.pins) &&
+		slices.Equal(c.tags, other.tags) && slices.Equal(c.order, other.order) &&
+		c.filterScope == other.filterScope &&
+		c.removeScope == other.removeScope &&
+		sameValue(c.cache, other.cache) && c.workers == other.workers &&
+		c.timeout == other.timeout && c.timeoutErr == other.timeoutErr &&
+		c.maxItems == other.maxItems && c.truncate == other.truncate &&
+		sameNil(c.equal, other.equal) && sameNil(c.keyFunc, other.keyFunc) &&
+		sameNil(c.expand, other.expand) &&
+		sameNil(c.mapping, other.mapping) &&
+		sameNil(c.priority, other.priority) &&
+		sameNil(c.compare, other.compare) &&
+		sameNil(c.statFn, other.statFn) && sameNil(c.evalFn, other.evalFn) &&
+		sameNil(c.readDir, other.readDir) &&
+		sameNil(c.predicate, other.predicate) &&
+		sameNil(c.predicateErr, other.predicateErr) &&
+		sameNil(c.predicateCtx, other.predicateCtx) &&
+		sameNil(c.batch, other.batch) && sameNil(c.exclude, other.exclude) &&
+		sameNil(c.onDuplicate, other.onDuplicate) &&
+		sameNil(c.onRemove, other.onRemove) &&
+		sameNil(c.onReplace, other.onReplace) && c.logger == other.logger &&
+		c.skipSame == other.skipSame
+}
+
+// samePattern returns true if and only if a and b have no delimiter pattern,
+// or delimiter patterns with equal source text.
+func samePattern(a, b Config) bool {
+	if a.pattern == nil || b.pattern == nil {
+		return a.pattern == b.pattern
+	}
+
+	return a.pattern.String() == b.pattern.String()
+}
+
+// sameValue returns true if and only if a and b are equal, comparing values
+// of types that are not comparable with [reflect.DeepEqual].
+func sameValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) {
+		return false
+	}
+
+	if ta.Comparable() {
+		return a == b
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// removalRules returns the rules of the receiver removing items by pattern.
+func (c Config) removalRules() []Rule {
+	var rules []Rule
+	for _, r := range c.removals {
+		rules = append(rules, r.rules...)
+	}
+
+	return rules
+}
+
+// sameRewriter returns true if and only if a and b replace items by the same
+// rule.
+func sameRewriter(a, b rewriter) bool { return a.rule == b.rule }
+
+// sameStages returns true if and only if a and b insert the same number of
+// stages at each point of the pipeline.
+func sameStages(a, b []stagePoint) bool {
+	return slices.EqualFunc(a, b, func(p, q stagePoint) bool {
+		return p.name == q.name
+	})
+}
+
+// sameNil returns true if and only if a and b are both nil or both non-nil.
+func sameNil[F any](a, b F) bool {
+	return reflect.ValueOf(&a).Elem().IsNil() == reflect.ValueOf(&b).Elem().IsNil()
+}
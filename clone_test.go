@@ -0,0 +1,153 @@
+package mung
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestConfigClone(t *testing.T) {
+	orig := Make(
+		WithSubject([]string{"a"}),
+		WithRemove([]string{"r"}),
+		WithPrefix([]string{"p"}),
+		WithSuffix([]string{"s"}),
+		WithReplaceItem("a", "A"),
+		WithDelims(":", ";"),
+		WithStages(upperStage),
+	)
+	want := Make(
+		WithSubject([]string{"a"}),
+		WithRemove([]string{"r"}),
+		WithPrefix([]string{"p"}),
+		WithSuffix([]string{"s"}),
+		WithReplaceItem("a", "A"),
+		WithDelims(":", ";"),
+		WithStages(upperStage),
+	)
+
+	clone := orig.Clone()
+	if !clone.Equal(orig) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, orig)
+	}
+
+	clone.subject[0] = "x"
+	clone.remove[0] = "x"
+	clone.prefix[0] = "x"
+	clone.suffix[0] = "x"
+	clone.alt[0] = "x"
+	clone.stages[0] = nil
+	clone = Wrap(clone, WithReplaceItem("a", "X"), WithReplaceItem("b", "B"))
+
+	if !orig.Equal(want) {
+		t.Errorf("original after mutating Clone() = %+v, want %+v", orig, want)
+	}
+
+	if orig.stages[0] == nil {
+		t.Errorf("original stage cleared by mutating Clone()")
+	}
+}
+
+func TestConfigEqual(t *testing.T) {
+	pred := func(string) bool { return true }
+	cache := NewFilterCache()
+
+	tests := []struct {
+		name string
+		a, b Config
+		want bool
+	}{
+		{
+			name: "zero",
+			want: true,
+		},
+		{
+			name: "equal",
+			a: Make(WithSubject([]string{"a"}), WithDelim(":"),
+				WithReplaceItem("a", "b"), WithCaseFold()),
+			b: Make(WithSubject([]string{"a"}), WithDelim(":"),
+				WithReplaceItem("a", "b"), WithCaseFold()),
+			want: true,
+		},
+		{
+			name: "nil_and_empty_slices",
+			a:    Config{subject: nil},
+			b:    Config{subject: []string{}},
+			want: true,
+		},
+		{
+			name: "different_subject",
+			a:    Make(WithSubject([]string{"a"})),
+			b:    Make(WithSubject([]string{"b"})),
+		},
+		{
+			name: "different_replace",
+			a:    Make(WithReplaceItem("a", "b")),
+			b:    Make(WithReplaceItem("a", "c")),
+		},
+		{
+			name: "different_option",
+			a:    Make(WithCaseFold()),
+			b:    Config{},
+		},
+		{
+			name: "patterns_same_source",
+			a:    Make(WithDelimPattern(regexp.MustCompile(`[,;]`))),
+			b:    Make(WithDelimPattern(regexp.MustCompile(`[,;]`))),
+			want: true,
+		},
+		{
+			name: "patterns_different_source",
+			a:    Make(WithDelimPattern(regexp.MustCompile(`[,;]`))),
+			b:    Make(WithDelimPattern(regexp.MustCompile(`,`))),
+		},
+		{
+			name: "predicates_set",
+			a:    Make(WithFilter(pred)),
+			b:    Make(WithFilter(func(string) bool { return false })),
+			want: true,
+		},
+		{
+			name: "predicate_unset",
+			a:    Make(WithFilter(pred)),
+			b:    Config{},
+		},
+		{
+			name: "same_cache",
+			a:    Make(WithFilterCache(cache)),
+			b:    Make(WithFilterCache(cache)),
+			want: true,
+		},
+		{
+			name: "different_cache",
+			a:    Make(WithFilterCache(cache)),
+			b:    Make(WithFilterCache(NewFilterCache())),
+		},
+		{
+			name: "tokenizers",
+			a:    Make(WithTokenizer(DelimTokenizer{Delim: ",", Alt: []string{";"}})),
+			b:    Make(WithTokenizer(DelimTokenizer{Delim: ",", Alt: []string{";"}})),
+			want: true,
+		},
+		{
+			name: "different_tokenizers",
+			a:    Make(WithTokenizer(DelimTokenizer{Delim: ","})),
+			b:    Make(WithTokenizer(DelimTokenizer{Delim: ";"})),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+
+			if got := tt.b.Equal(tt.a); got != tt.want {
+				t.Errorf("Equal() reversed = %v, want %v", got, tt.want)
+			}
+
+			if !tt.a.Equal(tt.a.Clone()) {
+				t.Errorf("Equal(Clone()) = false, want true")
+			}
+		})
+	}
+}
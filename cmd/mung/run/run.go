@@ -5,16 +5,19 @@ package run
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
-	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ardnew/mung"
+	"github.com/ardnew/mung/flagutil"
 )
 
 // ExitCode represents a program termination code and implements error.
@@ -60,6 +63,8 @@ var (
 	ExitNoSubjects = ExitCode{Code: 2, Msg: "no subjects provided"}
 	// error expanding subjects (e.g., env lookup)
 	ExitSubjectsError = ExitCode{Code: 3, Msg: "failed to expand subjects"}
+	// error running the filter command (e.g., shell not found)
+	ExitFilterError = ExitCode{Code: 4, Msg: "failed to run filter"}
 )
 
 // Main executes the mung CLI and returns an appropriate exit code.
@@ -67,24 +72,19 @@ var (
 func Main(version string) (string, ExitCode) {
 	flags := flagSet{
 		FlagSet:    flag.NewFlagSet("mung", flag.ContinueOnError),
-		delim:      soloValue{zero: ":", name: "d", desc: "item delimiter"},
-		remove:     multiValue{name: "r", desc: "items to remove"},
-		prefix:     multiValue{name: "p", desc: "items to prefix subject(s)"},
-		suffix:     multiValue{name: "s", desc: "items to suffix subject(s)"},
-		filter:     soloValue{name: "t", desc: "command to filter subject(s)"},
+		values:     flagutil.Flags{Delim: ":"},
 		verbose:    incFlag(0),
 		version:    incFlag(0),
 		cmdVersion: strings.TrimSpace(version),
 	}
 
 	// Define command-line flags
-	flags.Var(&flags.delim, flags.delim.name, flags.delim.desc)
-	flags.Var(&flags.remove, flags.remove.name, flags.remove.desc)
-	flags.Var(&flags.prefix, flags.prefix.name, flags.prefix.desc)
-	flags.Var(&flags.suffix, flags.suffix.name, flags.suffix.desc)
-	flags.Var(&flags.filter, flags.filter.name, flags.filter.desc)
+	flags.values.Register(flags.FlagSet)
+	flags.StringVar(&flags.filter, "t", "", "command to filter subject(s)")
+	flags.IntVar(&flags.jobs, "j", 1, "number of filter commands to run in parallel")
+	flags.DurationVar(&flags.timeout, "T", 0, "time limit for each filter command (e.g., 5s)")
 	flags.BoolVar(&flags.nameref, "n", false, "subjects are env NAME references")
-	flags.Var(&flags.verbose, "v", "enable verbose output (incremental)")
+	flags.Var(&flags.verbose, "v", "enable verbose output (incremental, twice to log each decision)")
 	flags.Var(&flags.version, "V", "print semantic version of cmd (with module if verbose)")
 
 	flags.Usage = flags.usage
@@ -116,36 +116,42 @@ func Main(version string) (string, ExitCode) {
 		return "", ExitSubjectsError.With(err)
 	}
 
-	opts := []mung.Option[mung.Config]{
-		mung.WithSubject(subjects),
-		mung.WithDelim(flags.delim.get()),
-	}
+	opts := append(
+		[]mung.Option[mung.Config]{mung.WithSubject(subjects)},
+		flags.values.Options()...,
+	)
 
-	if remove := flags.remove.get(); len(remove) > 0 {
-		opts = append(opts, mung.WithRemove(remove))
-	}
-	if prefix := flags.prefix.get(); len(prefix) > 0 {
-		opts = append(opts, mung.WithPrefix(prefix))
+	if cmd := strings.TrimSpace(flags.filter); cmd != "" {
+		opts = append(opts,
+			mung.WithFilterErr(flags.makeFilterErr(cmd)),
+			mung.WithParallelFilter(flags.jobs),
+		)
 	}
-	if suffix := flags.suffix.get(); len(suffix) > 0 {
-		opts = append(opts, mung.WithSuffix(suffix))
-	}
-	if cmd := flags.filter.get(); cmd != "" {
-		opts = append(opts, mung.WithFilter(flags.makeFilter(cmd)))
+	if verb > 1 {
+		// Log every munge decision to stderr.
+		handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		})
+		opts = append(opts, mung.WithLogger(slog.New(handler)))
 	}
 
-	seq := mung.Make(opts...).Filtered()
-	out := strings.Join(slices.Collect(seq), flags.delim.get())
+	var items []string
+	for item, err := range mung.Make(opts...).FilteredErr() {
+		if err != nil {
+			return "", ExitFilterError.With(fmt.Errorf("%q: %w", item, err))
+		}
+		items = append(items, item)
+	}
+	out := strings.Join(items, string(flags.values.Delim))
 	return out, ExitOK
 }
 
 type flagSet struct {
 	*flag.FlagSet
-	delim      soloValue
-	remove     multiValue
-	prefix     multiValue
-	suffix     multiValue
-	filter     soloValue
+	values     flagutil.Flags
+	filter     string
+	jobs       int
+	timeout    time.Duration
 	nameref    bool
 	verbose    incFlag
 	version    incFlag
@@ -179,6 +185,10 @@ func (f *flagSet) usage() {
 	fmt.Fprintln(f.Output(), "  The -t flag specifies a command-line to filter subjects.")
 	fmt.Fprintln(f.Output(), "  The line is executed for each subject. A subject passes if")
 	fmt.Fprintln(f.Output(), "  the command exits with status 0; otherwise, it is filtered out.")
+	fmt.Fprintln(f.Output(), "  If the command cannot be run at all, mung exits with an error.")
+	fmt.Fprintln(f.Output(), "  The -j flag runs up to the given number of commands in parallel.")
+	fmt.Fprintln(f.Output(), "  The -T flag kills commands running longer than the given duration;")
+	fmt.Fprintln(f.Output(), "  the corresponding subjects are filtered out.")
 	fmt.Fprintln(f.Output())
 	fmt.Fprintln(f.Output(), "  Subject substitution:")
 	fmt.Fprintln(f.Output(), "    - If '{}' appears in the command-line, it is replaced with")
@@ -212,11 +222,23 @@ func (f *flagSet) subjects() ([]string, error) {
 	return s, nil
 }
 
-func (f *flagSet) makeFilter(cmd string) func(string) bool {
-	return func(subject string) bool {
+// makeFilterErr returns a predicate that runs cmd for each subject.
+// A subject is selected if the command exits with status 0 and filtered out
+// if it exits with any other status. An error is returned only if the command
+// could not be run at all, e.g., if the shell cannot be started.
+func (f *flagSet) makeFilterErr(cmd string) func(string) (bool, error) {
+	return func(subject string) (bool, error) {
 		c := strings.TrimSpace(cmd)
 		if c == "" {
-			return true
+			return true, nil
+		}
+
+		// A command exceeding the time limit is killed and filtered out.
+		ctx := context.Background()
+		if f != nil && f.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, f.timeout)
+			defer cancel()
 		}
 
 		var command *exec.Cmd
@@ -224,16 +246,16 @@ func (f *flagSet) makeFilter(cmd string) func(string) bool {
 		case strings.Contains(c, "{}"):
 			// Replace '{}' with a safely shell-quoted subject and execute.
 			script := strings.ReplaceAll(c, "{}", shQuote(subject))
-			command = exec.Command("sh", "-c", script)
+			command = exec.CommandContext(ctx, "sh", "-c", script)
 
 		case strings.Contains(c, "$1") || strings.Contains(c, "$@"):
 			// Subject is available as $1 (or $@) to the shell.
-			command = exec.Command("sh", "-c", c, "sh", subject)
+			command = exec.CommandContext(ctx, "sh", "-c", c, "sh", subject)
 
 		default:
 			// No subject substitution; pass subject as argument $1,
 			// and append $1 to the command line.
-			command = exec.Command("sh", "-c", c+" "+shQuote(subject), "sh", subject)
+			command = exec.CommandContext(ctx, "sh", "-c", c+" "+shQuote(subject), "sh", subject)
 		}
 
 		// If verbose, capture output and log details to stderr.
@@ -268,38 +290,24 @@ func (f *flagSet) makeFilter(cmd string) func(string) bool {
 			}
 		}
 
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return false, nil
+		}
 		if err != nil {
-			return false
+			return false, err
 		}
-		return true
+		return true, nil
 	}
 }
 
 // shQuote returns a POSIX-shell-escaped version of s using single quotes.
 // It is safe to paste into sh -c command strings.
 func shQuote(s string) string {
-	if s == "" {
-		return "''"
-	}
-	// Close quote, insert escaped single quote, reopen: '"'"'
-	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+	return mung.QuoteShell(s)
 }
 
-type (
-	incFlag   int
-	soloValue struct {
-		solo string
-		zero string
-		name string
-		desc string
-	}
-	multiValue struct {
-		mult []string
-		zero []string
-		name string
-		desc string
-	}
-)
+type incFlag int
 
 func (f *incFlag) Set(value string) error {
 	if f == nil {
@@ -328,69 +336,3 @@ func (f *incFlag) String() string {
 	}
 	return strconv.Itoa(int(*f))
 }
-
-func (v *soloValue) Set(value string) error {
-	if v == nil {
-		return errors.New("uninitialized flag")
-	}
-	if strings.TrimSpace(value) != "" {
-		v.solo = value
-	}
-	return nil
-}
-
-func (v *soloValue) String() string { return v.get() }
-
-func (v *soloValue) isZero() bool {
-	if v == nil {
-		return true
-	}
-	return v.solo == ""
-}
-
-func (v *soloValue) get() string {
-	if v == nil {
-		return ""
-	}
-	if v.solo != "" {
-		return v.solo
-	}
-	return v.zero
-}
-
-func (v *multiValue) Set(value string) error {
-	if v == nil {
-		return errors.New("uninitialized flag")
-	}
-	if v.mult == nil {
-		v.mult = []string{}
-	}
-	if strings.TrimSpace(value) != "" {
-		v.mult = append(v.mult, value)
-	}
-	return nil
-}
-
-func (v *multiValue) String() string {
-	if v == nil {
-		return ""
-	}
-	return fmt.Sprintf("%+v", v.get())
-}
-
-func (v *multiValue) isZero() bool {
-	if v == nil {
-		return true
-	}
-	return len(v.mult) == 0
-}
-
-func (v *multiValue) get() []string {
-	if v == nil {
-		return nil
-	}
-	if len(v.mult) > 0 {
-		return v.mult
-	}
-	return v.zero
-}
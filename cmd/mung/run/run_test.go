@@ -5,6 +5,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/ardnew/mung/flagutil"
 )
 
 func withArgs(args []string, fn func()) {
@@ -57,33 +60,6 @@ func TestNilReceiversAndSetErrors(t *testing.T) {
 		t.Fatalf("nil incFlag.Set should error")
 	}
 
-	// soloValue nil receiver behaviors
-	var sv *soloValue
-	if !sv.isZero() {
-		t.Fatalf("nil soloValue isZero should be true")
-	}
-	if sv.get() != "" {
-		t.Fatalf("nil soloValue get should be empty string")
-	}
-	if err := sv.Set("x"); err == nil {
-		t.Fatalf("nil soloValue.Set should error")
-	}
-
-	// multiValue nil receiver behaviors
-	var mv *multiValue
-	if !mv.isZero() {
-		t.Fatalf("nil multiValue isZero should be true")
-	}
-	if mv.get() != nil {
-		t.Fatalf("nil multiValue get should be nil slice")
-	}
-	if mv.String() != "" {
-		t.Fatalf("nil multiValue String should be empty")
-	}
-	if err := mv.Set("x"); err == nil {
-		t.Fatalf("nil multiValue.Set should error")
-	}
-
 	// flagSet.subjects error when FlagSet is nil
 	var fs flagSet
 	if _, err := fs.subjects(); err == nil {
@@ -195,40 +171,68 @@ func TestMain_NameRefEnv_MissingIsSkipped(t *testing.T) {
 	})
 }
 
-func TestMakeFilter_WithBraces(t *testing.T) {
-	f := (&flagSet{}).makeFilter("[ -n {} ]")
-	if !f("abc") {
-		t.Fatalf("filter returned false for non-empty subject")
+func TestMakeFilterErr_WithBraces(t *testing.T) {
+	f := (&flagSet{}).makeFilterErr("[ -n {} ]")
+	if ok, err := f("abc"); !ok || err != nil {
+		t.Fatalf("filter = (%v, %v), want (true, nil) for non-empty subject", ok, err)
 	}
-	if f("") {
-		t.Fatalf("filter returned true for empty subject")
+	if ok, err := f(""); ok || err != nil {
+		t.Fatalf("filter = (%v, %v), want (false, nil) for empty subject", ok, err)
 	}
 }
 
-func TestMakeFilter_WithDollarArg(t *testing.T) {
-	f := (&flagSet{}).makeFilter("[ -n \"$1\" ]")
-	if !f("abc") {
-		t.Fatalf("$1 filter returned false for non-empty subject")
+func TestMakeFilterErr_WithDollarArg(t *testing.T) {
+	f := (&flagSet{}).makeFilterErr("[ -n \"$1\" ]")
+	if ok, err := f("abc"); !ok || err != nil {
+		t.Fatalf("$1 filter = (%v, %v), want (true, nil) for non-empty subject", ok, err)
 	}
-	if f("") {
-		t.Fatalf("$1 filter returned true for empty subject")
+	if ok, err := f(""); ok || err != nil {
+		t.Fatalf("$1 filter = (%v, %v), want (false, nil) for empty subject", ok, err)
 	}
 }
 
-func TestMakeFilter_DefaultAppend(t *testing.T) {
-	f := (&flagSet{}).makeFilter("test -n")
-	if !f("abc") {
-		t.Fatalf("default-append filter returned false for non-empty subject")
+func TestMakeFilterErr_DefaultAppend(t *testing.T) {
+	f := (&flagSet{}).makeFilterErr("test -n")
+	if ok, err := f("abc"); !ok || err != nil {
+		t.Fatalf("default-append filter = (%v, %v), want (true, nil) for non-empty subject", ok, err)
+	}
+	if ok, err := f(""); ok || err != nil {
+		t.Fatalf("default-append filter = (%v, %v), want (false, nil) for empty subject", ok, err)
 	}
-	if f("") {
-		t.Fatalf("default-append filter returned true for empty subject")
+}
+
+func TestMakeFilterErr_EmptyReturnsTrue(t *testing.T) {
+	f := (&flagSet{}).makeFilterErr("   ")
+	for _, subject := range []string{"", "abc"} {
+		if ok, err := f(subject); !ok || err != nil {
+			t.Fatalf("empty command filter = (%v, %v), want (true, nil)", ok, err)
+		}
 	}
 }
 
-func TestMakeFilter_EmptyReturnsTrue(t *testing.T) {
-	f := (&flagSet{}).makeFilter("   ")
-	if !f("") || !f("abc") {
-		t.Fatalf("empty command filter should always return true")
+func TestMakeFilterErr_StartFailure(t *testing.T) {
+	t.Setenv("PATH", "")
+	f := (&flagSet{}).makeFilterErr("true")
+	if ok, err := f("abc"); ok || err == nil {
+		t.Fatalf("filter = (%v, %v), want (false, error)", ok, err)
+	}
+}
+
+func TestMakeFilterErr_ExitStatus(t *testing.T) {
+	f := (&flagSet{}).makeFilterErr("exit 3")
+	if ok, err := f("abc"); ok || err != nil {
+		t.Fatalf("filter = (%v, %v), want (false, nil)", ok, err)
+	}
+}
+
+func TestMakeFilterErr_Timeout(t *testing.T) {
+	f := (&flagSet{timeout: 50 * time.Millisecond}).makeFilterErr("sleep 5; true")
+	start := time.Now()
+	if ok, err := f("abc"); ok || err != nil {
+		t.Fatalf("filter = (%v, %v), want (false, nil)", ok, err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Fatalf("filter ran for %v, want it killed after the timeout", elapsed)
 	}
 }
 
@@ -284,6 +288,31 @@ func TestMain_FilterIntegration_DefaultAppend(t *testing.T) {
 	})
 }
 
+func TestMain_FilterIntegration_Parallel(t *testing.T) {
+	withArgs([]string{"-j", "4", "-t", "test -n", "-d", ":", "::a:b::c:d"}, func() {
+		out, code := Main("0")
+		if code.Int() != 0 {
+			t.Fatalf("code=%d, want 0", code.Int())
+		}
+		if out != "a:b:c:d" {
+			t.Fatalf("out=%q, want 'a:b:c:d'", out)
+		}
+	})
+}
+
+func TestMain_FilterIntegration_StartFailure(t *testing.T) {
+	t.Setenv("PATH", "")
+	withArgs([]string{"-t", "true", "-d", ":", "a:b"}, func() {
+		out, code := Main("0")
+		if code.Int() != ExitFilterError.Int() {
+			t.Fatalf("code=%d, want %d", code.Int(), ExitFilterError.Int())
+		}
+		if out != "" {
+			t.Fatalf("out=%q, want empty", out)
+		}
+	})
+}
+
 func TestExitCode_ZeroHasEmptyError(t *testing.T) {
 	if ExitOK.Error() != "" {
 		t.Fatalf("ExitOK.Error()=%q, want empty", ExitOK.Error())
@@ -318,51 +347,6 @@ func TestIncFlag(t *testing.T) {
 	}
 }
 
-func TestSoloValue(t *testing.T) {
-	v := soloValue{zero: ":"}
-	if !v.isZero() {
-		t.Fatalf("new soloValue should be zero")
-	}
-	if v.get() != ":" {
-		t.Fatalf("get zero=%q, want ':'", v.get())
-	}
-	if err := v.Set(";"); err != nil {
-		t.Fatalf("Set err=%v", err)
-	}
-	if v.isZero() {
-		t.Fatalf("soloValue should not be zero after Set")
-	}
-	if v.get() != ";" {
-		t.Fatalf("get after Set=%q, want ';'", v.get())
-	}
-}
-
-func TestMultiValue(t *testing.T) {
-	v := multiValue{zero: []string{"Z"}}
-	if !v.isZero() {
-		t.Fatalf("new multiValue should be zero")
-	}
-	if got := v.get(); len(got) != 1 || got[0] != "Z" {
-		t.Fatalf("get zero=%v", got)
-	}
-	if err := v.Set("a"); err != nil {
-		t.Fatalf("Set a err=%v", err)
-	}
-	if err := v.Set("b"); err != nil {
-		t.Fatalf("Set b err=%v", err)
-	}
-	if v.isZero() {
-		t.Fatalf("multiValue should not be zero after Set")
-	}
-	got := v.get()
-	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
-		t.Fatalf("get=%v", got)
-	}
-	if s := v.String(); !strings.Contains(s, "a") || !strings.Contains(s, "b") {
-		t.Fatalf("String()=%q, want contains 'a' and 'b'", s)
-	}
-}
-
 func TestHelpFlag(t *testing.T) {
 	withArgs([]string{"-h"}, func() {
 		out, code := Main("0")
@@ -393,18 +377,11 @@ func TestUsagePrinter(t *testing.T) {
 	// Initialize the flag set properly
 	fs.FlagSet = newFlagSetForTest()
 	fs.cmdVersion = "X"
-	fs.delim = soloValue{zero: ":", name: "d", desc: "item delimiter"}
-	fs.remove = multiValue{name: "r", desc: "items to remove"}
-	fs.prefix = multiValue{name: "p", desc: "items to prefix subject(s)"}
-	fs.suffix = multiValue{name: "s", desc: "items to suffix subject(s)"}
-	fs.filter = soloValue{name: "t", desc: "command to filter subject(s)"}
+	fs.values = flagutil.Flags{Delim: ":"}
 	fs.verbose = incFlag(0)
 	fs.version = incFlag(0)
-	fs.Var(&fs.delim, fs.delim.name, fs.delim.desc)
-	fs.Var(&fs.remove, fs.remove.name, fs.remove.desc)
-	fs.Var(&fs.prefix, fs.prefix.name, fs.prefix.desc)
-	fs.Var(&fs.suffix, fs.suffix.name, fs.suffix.desc)
-	fs.Var(&fs.filter, fs.filter.name, fs.filter.desc)
+	fs.values.Register(fs.FlagSet)
+	fs.StringVar(&fs.filter, "t", "", "command to filter subject(s)")
 	fs.Var(&fs.verbose, "v", "enable verbose")
 	fs.Var(&fs.version, "V", "print version")
 	buf := &strings.Builder{}
@@ -0,0 +1,30 @@
+package mung
+
+import (
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Collation returns a function that compares strings by the conventional
+// order of the given language, for use with [WithSort], e.g., so that
+// localized directory names with accents sort next to their unaccented
+// counterparts instead of after every ASCII name.
+//
+// The options are passed to [collate.New], e.g., [collate.IgnoreCase].
+// Since [WithSort] sorts stably, items that collate equally keep their
+// relative order. The returned function is safe for concurrent use.
+func Collation(tag language.Tag, opts ...collate.Option) func(a, b string) int {
+	var (
+		mu sync.Mutex
+		c  = collate.New(tag, opts...)
+	)
+
+	return func(a, b string) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return c.CompareString(a, b)
+	}
+}
@@ -0,0 +1,73 @@
+package mung
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+func TestCollation(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  language.Tag
+		opts []collate.Option
+		want string
+	}{
+		{
+			name: "english",
+			tag:  language.English,
+			want: "/Ärger:/Bücher:/zeit:/Zeit",
+		},
+		{
+			name: "swedish",
+			tag:  language.Swedish,
+			want: "/Bücher:/zeit:/Zeit:/Ärger",
+		},
+		{
+			name: "ignore_case",
+			tag:  language.English,
+			opts: []collate.Option{collate.IgnoreCase},
+			want: "/Ärger:/Bücher:/Zeit:/zeit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(
+				WithSubjectItems("/Zeit:/Bücher:/zeit:/Ärger"),
+				WithDelim(":"),
+				WithSort(Collation(tt.tag, tt.opts...)),
+			)
+
+			if got := config.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollationConcurrent(t *testing.T) {
+	config := Make(
+		WithSubjectItems("/c:/b:/a"),
+		WithDelim(":"),
+		WithSort(Collation(language.English)),
+	)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if got, want := config.String(), "/a:/b:/c"; got != want {
+				t.Errorf("String() = %q, want %q", got, want)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,88 @@
+package mung
+
+import "slices"
+
+// Munger applies a compiled configuration to many subjects, e.g., in a
+// service that munges thousands of values with the same rules.
+//
+// A Munger is safe for concurrent use by multiple goroutines, provided any
+// functions set with options of the configuration are.
+type Munger struct {
+	config Config
+}
+
+// maxCachedPaths is the number of paths whose file system queries are cached
+// by a [Munger] before the cache is cleared.
+const maxCachedPaths = 1 << 12
+
+// compiled holds the structures derived from the remove, suffix, and replace
+// strings of a configuration, which do not depend on the subject.
+type compiled struct {
+	removePrefix  set[string]
+	removeSubject set[string]
+	removeSuffix  set[string]
+	suffixItems   []string
+	suffix        set[string]
+	replace       func(string) (string, bool)
+}
+
+// Compile returns a Munger that applies the receiver to any subject, building
+// the sets of strings to remove and the replacement rules once instead of
+// each time the munged sequence is realized.
+//
+// Strings produced lazily, e.g., with [WithRemoveSeq], are collected once by
+// Compile, and so are the results of any file system queries needed to
+// compare them. The results of file system queries are shared by each call to
+// [Munger.Apply], but at most 4096 paths are cached at a time.
+// Compile fails with the error returned by [Config.Validate], if any.
+func (c Config) Compile() (Munger, error) {
+	if err := c.Validate(); err != nil {
+		return Munger{}, err
+	}
+
+	c = c.prepareLimit(maxCachedPaths).materializeRules()
+	c.built = c.compile()
+
+	return Munger{config: c}, nil
+}
+
+// Apply returns the munged string of the compiled configuration with subject
+// as its only subject string, as if by [WithSubject] and [Config.String].
+// Any subject strings of the compiled configuration are replaced.
+func (m Munger) Apply(subject string) string {
+	return WithSubject([]string{subject})(m.config).String()
+}
+
+// compile returns the structures derived from the receiver's rules.
+//
+// The strings to remove and the suffix strings are each tokenized and
+// memoized once. Sets are only read once built, so the set of strings to
+// remove is shared by each scope it applies to.
+func (c Config) compile() *compiled {
+	remove, none := c.memoize(c.remove), c.newSet()
+	if len(c.removals) > 0 {
+		remove = removalSet{set: remove, match: c.removalOf}
+	}
+	removed := func(scope Scope) set[string] {
+		if c.removeScope.has(scope) {
+			return remove
+		}
+
+		return none
+	}
+
+	b := &compiled{
+		removePrefix:  removed(ScopePrefix),
+		removeSubject: removed(ScopeSubject),
+		removeSuffix:  removed(ScopeSuffix),
+		suffixItems:   slices.Collect(c.split(c.suffix)),
+		replace:       c.replacer(),
+	}
+
+	// Suffix items are only moved out of the subject under MoveToSuffix.
+	if c.dedupe != dedupeNone && c.placing == MoveToSuffix {
+		b.suffix = c.memoizeSeq(slices.Values(b.suffixItems))
+	}
+
+	return b
+}
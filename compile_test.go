@@ -0,0 +1,204 @@
+package mung
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConfigCompile(t *testing.T) {
+	subjects := []string{
+		"",
+		"/usr/bin:/bin",
+		"/old/bin:/usr/bin:/opt/bin:/bin:/usr/bin",
+		"/BIN:/sbin:/opt/bin",
+	}
+
+	tests := []struct {
+		name string
+		opts []Option[Config]
+	}{
+		{
+			name: "rules",
+			opts: []Option[Config]{
+				WithPrefixItems("/usr/local/bin"),
+				WithSuffixItems("/opt/bin"),
+				WithRemoveItems("/old/bin"),
+				WithReplaceItem("/bin", "/sbin"),
+			},
+		},
+		{
+			name: "keep_subject_position",
+			opts: []Option[Config]{
+				WithSuffixItems("/opt/bin"),
+				WithPositionPolicy(KeepSubjectPosition),
+			},
+		},
+		{
+			name: "allow_duplicates",
+			opts: []Option[Config]{
+				WithSuffixItems("/opt/bin"), WithAllowDuplicates(),
+			},
+		},
+		{
+			name: "case_fold_remove_scope",
+			opts: []Option[Config]{
+				WithCaseFold(),
+				WithPrefixItems("/sbin"),
+				WithRemoveItems("/bin", "/sbin"),
+				WithRemoveScope(ScopeSubject),
+			},
+		},
+		{
+			name: "lazy",
+			opts: []Option[Config]{
+				WithRemoveSeq(slices.Values([]string{"/usr/bin"})),
+				WithSuffixSeq(slices.Values([]string{"/bin"})),
+			},
+		},
+		{
+			name: "equal",
+			opts: []Option[Config]{
+				WithEqual(strings.EqualFold),
+				WithRemoveItems("/bin"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(append([]Option[Config]{WithDelim(":")}, tt.opts...)...)
+
+			m, err := config.Compile()
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			for _, subject := range subjects {
+				want := WithSubject([]string{subject})(config).String()
+				if got := m.Apply(subject); got != want {
+					t.Errorf("Apply(%q) = %q, want %q", subject, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigCompileInvalid(t *testing.T) {
+	config := Make(WithReplace(map[string]string{"a": "b", "b": "a"}))
+
+	if _, err := config.Compile(); !errors.Is(err, ErrReplaceCycle) {
+		t.Errorf("Compile() error = %v, want %v", err, ErrReplaceCycle)
+	}
+}
+
+func TestMungerConcurrent(t *testing.T) {
+	m, err := Make(
+		WithDelim(":"), WithRemoveItems("b"), WithReplaceItem("c", "x"),
+	).Compile()
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				if got, want := m.Apply("a:b:c:a"), "a:x"; got != want {
+					t.Errorf("Apply() = %q, want %q", got, want)
+
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestMungerStatCache(t *testing.T) {
+	var calls atomic.Int32
+
+	m, err := Make(
+		WithDelim(":"), WithExistingOnly(),
+		WithStatFunc(func(string) (fs.FileInfo, error) {
+			calls.Add(1)
+
+			return nil, fs.ErrNotExist
+		}),
+	).Compile()
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	for range 3 {
+		if got := m.Apply("/a:/b"); got != "" {
+			t.Errorf("Apply() = %q, want empty", got)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("stat called %d times, want 2", got)
+	}
+}
+
+// countingTokenizer is a [DelimTokenizer] that counts the strings split.
+type countingTokenizer struct {
+	DelimTokenizer
+	splits map[string]int
+}
+
+func (t countingTokenizer) Split(s string) iter.Seq[string] {
+	t.splits[s]++
+
+	return t.DelimTokenizer.Split(s)
+}
+
+func TestConfigSeqSplitsOnce(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+	}{
+		{name: "default"},
+		{name: "prepend_skip", opts: []Option[Config]{WithPrependPolicy(PrependSkip)}},
+		{name: "keep_last", opts: []Option[Config]{
+			WithDedupeKeepLast(), WithPositionPolicy(KeepSubjectPosition),
+		}},
+		{name: "remove_scope", opts: []Option[Config]{WithRemoveScope(ScopeSubject)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok := countingTokenizer{DelimTokenizer{Delim: ":"}, map[string]int{}}
+			config := Make(append([]Option[Config]{
+				WithTokenizer(tok),
+				WithSubject([]string{"a:b:c"}),
+				WithPrefixItems("p:a"),
+				WithSuffixItems("s:b"),
+				WithRemoveItems("c:x"),
+			}, tt.opts...)...)
+
+			_ = config.String()
+
+			for s, n := range tok.splits {
+				if n != 1 {
+					t.Errorf("Split(%q) called %d times, want 1", s, n)
+				}
+			}
+
+			if len(tok.splits) != 4 {
+				t.Errorf("Split() called with %v, want 4 strings", tok.splits)
+			}
+		})
+	}
+}
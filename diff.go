@@ -0,0 +1,97 @@
+package mung
+
+import (
+	"slices"
+	"sort"
+)
+
+// Diff returns the differences between the munged sequences of from and to,
+// e.g., to show the effect of a munge or compare two PATH values.
+//
+// Items are compared exactly, as in [Union], and each is reported once:
+//   - added holds the items of to that are not in from, in the order of to.
+//   - removed holds the items of from that are not in to, in the order of
+//     from.
+//   - moved holds the items of both whose relative order changed, in the order
+//     of to. The fewest items are reported as moved, such that the remaining
+//     items common to both are in the same relative order.
+//
+// Both sequences are realized with [Config.Filtered].
+func Diff(from, to Config) (added, removed, moved []string) {
+	return diff(distinct(from), distinct(to))
+}
+
+// distinct returns the distinct items of the munged sequence of c, in order.
+func distinct(c Config) []string {
+	return slices.Collect(Union(c.Filtered(), nil))
+}
+
+// diff is like [Diff] but compares the distinct items a and b.
+func diff(a, b []string) (added, removed, moved []string) {
+	added = slices.Collect(Subtract(slices.Values(b), slices.Values(a)))
+	removed = slices.Collect(Subtract(slices.Values(a), slices.Values(b)))
+
+	// The items common to both that are not moved are those of the longest
+	// subsequence in which their positions in from are increasing.
+	index := make(map[string]int, len(a))
+	for i, s := range a {
+		index[s] = i
+	}
+
+	var common []string
+
+	for _, s := range b {
+		if _, ok := index[s]; ok {
+			common = append(common, s)
+		}
+	}
+
+	kept := Set[string]{}
+	for _, i := range increasing(common, func(s string) int { return index[s] }) {
+		kept.Add(common[i])
+	}
+
+	for _, s := range common {
+		if !kept.Contains(s) {
+			moved = append(moved, s)
+		}
+	}
+
+	return added, removed, moved
+}
+
+// increasing returns the indices of the items of a longest subsequence of
+// items whose keys are strictly increasing.
+func increasing(items []string, key func(string) int) []int {
+	// tails[k] is the index of the item ending the best subsequence of length
+	// k+1 found so far, and prev links each item to its predecessor.
+	var tails []int
+
+	prev := make([]int, len(items))
+
+	for i, s := range items {
+		k := sort.Search(len(tails), func(j int) bool {
+			return key(items[tails[j]]) >= key(s)
+		})
+
+		prev[i] = -1
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	seq := make([]int, len(tails))
+	if len(tails) > 0 {
+		for k, i := len(tails)-1, tails[len(tails)-1]; k >= 0; k-- {
+			seq[k], i = i, prev[i]
+		}
+	}
+
+	return seq
+}
@@ -0,0 +1,94 @@
+package mung
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		added    []string
+		removed  []string
+		moved    []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "equal",
+			from: "a:b:c",
+			to:   "a:b:c",
+		},
+		{
+			name:  "added",
+			from:  "a:b",
+			to:    "x:a:b:y",
+			added: []string{"x", "y"},
+		},
+		{
+			name:    "removed",
+			from:    "a:b:c",
+			to:      "b",
+			removed: []string{"a", "c"},
+		},
+		{
+			name:  "moved_to_front",
+			from:  "a:b:c:d",
+			to:    "d:a:b:c",
+			moved: []string{"d"},
+		},
+		{
+			name:  "moved_to_back",
+			from:  "a:b:c:d",
+			to:    "b:c:d:a",
+			moved: []string{"a"},
+		},
+		{
+			name:  "swapped",
+			from:  "a:b",
+			to:    "b:a",
+			moved: []string{"b"},
+		},
+		{
+			name:  "reversed",
+			from:  "a:b:c",
+			to:    "c:b:a",
+			moved: []string{"c", "b"},
+		},
+		{
+			name:    "mixed",
+			from:    "a:b:c:d:e",
+			to:      "x:e:a:c:b",
+			added:   []string{"x"},
+			removed: []string{"d"},
+			moved:   []string{"e", "c"},
+		},
+		{
+			name:  "duplicates",
+			from:  "a:b:a",
+			to:    "b:a:b",
+			moved: []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := Make(WithSubject([]string{tt.from}), WithDelim(":"),
+				WithAllowDuplicates())
+			to := Make(WithSubject([]string{tt.to}), WithDelim(":"),
+				WithAllowDuplicates())
+
+			added, removed, moved := Diff(from, to)
+			if !slicesEqual(added, tt.added) {
+				t.Errorf("Diff() added = %v, want %v", added, tt.added)
+			}
+
+			if !slicesEqual(removed, tt.removed) {
+				t.Errorf("Diff() removed = %v, want %v", removed, tt.removed)
+			}
+
+			if !slicesEqual(moved, tt.moved) {
+				t.Errorf("Diff() moved = %v, want %v", moved, tt.moved)
+			}
+		})
+	}
+}
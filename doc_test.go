@@ -9,7 +9,14 @@ package mung
 
 import (
 	"fmt"
+	"io/fs"
+	"iter"
+	"regexp"
+	"slices"
 	"strings"
+	"testing/fstest"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // ExampleVersion demonstrates how to get the version of the mung package.
@@ -50,6 +57,28 @@ func ExampleWrap() {
 	// Output: /usr/local/bin:/usr/bin:/opt/bin
 }
 
+// ExampleTryMake demonstrates creating a Config with options that may fail.
+func ExampleTryMake() {
+	config, err := TryMake(
+		Try(WithSubject([]string{"a, b;c"})),
+		WithDelimRegexp(`[,;]\s*`),
+		Try(WithDelim(":")),
+	)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	fmt.Println(config.String())
+
+	_, err = TryMake(WithDelimRegexp(`[,;`))
+	fmt.Println(err)
+	// Output:
+	// a:b:c
+	// delimiter pattern: error parsing regexp: missing closing ]: `[,;`
+}
+
 // ExampleWithSubject demonstrates setting the subject strings.
 func ExampleWithSubject() {
 	config := Make(
@@ -251,6 +280,268 @@ func ExampleWithFilter() {
 	// Output: /usr/bin:/bin
 }
 
+// ExampleWithFilterAnd demonstrates narrowing the selection with multiple
+// predicate functions.
+func ExampleWithFilterAnd() {
+	config := Make(
+		WithSubject([]string{"/usr/local/bin:/usr/bin:/bin:/opt/bin"}),
+		WithDelim(":"),
+		WithFilter(func(s string) bool {
+			return strings.HasPrefix(s, "/usr")
+		}),
+		WithFilterAnd(FilterNot(func(s string) bool {
+			return strings.Contains(s, "local")
+		})),
+	)
+
+	fmt.Println(config.String())
+	// Output: /usr/bin
+}
+
+// ExampleWithFilterScope demonstrates applying a predicate function to the
+// subject only, leaving prefix items unfiltered.
+func ExampleWithFilterScope() {
+	config := Make(
+		WithSubject([]string{"/usr/local/bin:/usr/bin"}),
+		WithPrefix([]string{"/opt/local/bin"}),
+		WithDelim(":"),
+		WithFilter(func(s string) bool {
+			return !strings.Contains(s, "local")
+		}),
+		WithFilterScope(ScopeSubject),
+	)
+
+	fmt.Println(config.String())
+	// Output: /opt/local/bin:/usr/bin
+}
+
+// ExampleWithMap demonstrates rewriting every element with a function.
+func ExampleWithMap() {
+	config := Make(
+		WithSubject([]string{"/usr/bin:/bin"}),
+		WithDelim(":"),
+		WithMap(func(s string) string { return "/sysroot" + s }),
+	)
+
+	fmt.Println(config.String())
+	// Output: /sysroot/usr/bin:/sysroot/bin
+}
+
+// ExampleWithStages demonstrates appending a stage to the munged sequence.
+func ExampleWithStages() {
+	sorted := func(items iter.Seq[string]) iter.Seq[string] {
+		return slices.Values(slices.Sorted(items))
+	}
+
+	config := Make(
+		WithSubject([]string{"/usr/local/bin:/bin:/usr/bin:/bin"}),
+		WithDelim(":"),
+		WithStages(sorted),
+	)
+
+	fmt.Println(config.String())
+	// Output: /bin:/usr/bin:/usr/local/bin
+}
+
+// ExampleWithDedupeKeepLast demonstrates keeping the final occurrence of
+// repeated elements.
+func ExampleWithDedupeKeepLast() {
+	config := Make(
+		WithSubject([]string{"/usr/bin:/opt/bin:/bin:/usr/bin"}),
+		WithDelim(":"),
+		WithDedupeKeepLast(),
+	)
+
+	fmt.Println(config.String())
+	// Output: /opt/bin:/bin:/usr/bin
+}
+
+// ExampleWithAllowDuplicates demonstrates preserving repeated elements.
+func ExampleWithAllowDuplicates() {
+	config := Make(
+		WithSubject([]string{"-I include -I vendor/include"}),
+		WithDelim(" "),
+		WithAllowDuplicates(),
+	)
+
+	fmt.Println(config.String())
+	// Output: -I include -I vendor/include
+}
+
+// ExampleWithCaseFold demonstrates case-insensitive comparison of elements.
+func ExampleWithCaseFold() {
+	config := Make(
+		WithSubject([]string{`C:\Windows;C:\Tools;c:\windows`}),
+		WithDelim(";"),
+		WithRemoveItems(`c:\tools`),
+		WithCaseFold(),
+	)
+
+	fmt.Println(config.String())
+	// Output: C:\Windows
+}
+
+// ExampleWithEqual demonstrates a custom comparison ignoring trailing slashes.
+func ExampleWithEqual() {
+	config := Make(
+		WithSubject([]string{"/usr/local/bin/:/usr/bin:/usr/local/bin"}),
+		WithDelim(":"),
+		WithEqual(func(a, b string) bool {
+			return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+		}),
+	)
+
+	fmt.Println(config.String())
+	// Output: /usr/local/bin/:/usr/bin
+}
+
+// ExampleWithTrimSpace demonstrates removing stray white space from items.
+func ExampleWithTrimSpace() {
+	config := Make(
+		WithSubject([]string{" /usr/bin : /bin:/usr/bin "}),
+		WithDelim(":"),
+		WithTrimSpace(),
+	)
+
+	fmt.Printf("%q\n", config.String())
+	// Output: "/usr/bin:/bin"
+}
+
+// ExampleWithWindowsPaths demonstrates normalizing Windows path names.
+func ExampleWithWindowsPaths() {
+	config := Make(
+		WithSubject([]string{`c:/Windows/;C:\Windows;C:\Windows\System32`}),
+		WithDelim(";"),
+		WithWindowsPaths(),
+	)
+
+	fmt.Println(config.String())
+	// Output: C:\Windows;C:\Windows\System32
+}
+
+// ExampleWindowsToWSL demonstrates converting between Windows and WSL path
+// names.
+func ExampleWindowsToWSL() {
+	fmt.Println(WindowsToWSL(`C:\Users\me`))
+	fmt.Println(WSLToWindows("/mnt/c/Users/me"))
+	// Output:
+	// /mnt/c/Users/me
+	// C:\Users\me
+}
+
+// ExampleWithNormalizeUnicode demonstrates eliminating duplicates that differ
+// only in their Unicode normal form.
+func ExampleWithNormalizeUnicode() {
+	config := Make(
+		WithSubject([]string{"/Users/jose\u0301/bin:/Users/jos\u00e9/bin"}),
+		WithDelim(":"),
+		WithRewriteUnicode(norm.NFC),
+	)
+
+	fmt.Printf("%+q\n", config.String())
+	// Output: "/Users/jos\u00e9/bin"
+}
+
+// ExampleWithCleanPaths demonstrates collapsing equivalent path names.
+func ExampleWithCleanPaths() {
+	config := Make(
+		WithSubject([]string{"/usr/local/bin/:/usr//bin:/usr/local/../bin"}),
+		WithDelim(":"),
+		WithCleanPaths(),
+	)
+
+	fmt.Println(config.String())
+	// Output: /usr/local/bin:/usr/bin
+}
+
+// ExampleWithFS demonstrates filtering elements against an in-memory
+// file system.
+func ExampleWithFS() {
+	fsys := fstest.MapFS{
+		"usr/bin":       {Mode: fs.ModeDir | 0o755},
+		"usr/local/bin": {Mode: fs.ModeDir | 0o755},
+	}
+
+	config := Make(
+		WithSubject([]string{"/usr/local/bin:/opt/bin:/usr/bin"}),
+		WithDelim(":"),
+		WithFS(fsys),
+		WithExistingDirsOnly(),
+	)
+
+	fmt.Println(config.String())
+	// Output: /usr/local/bin:/usr/bin
+}
+
+// ExampleWithEscape demonstrates elements containing escaped delimiters.
+func ExampleWithEscape() {
+	config := Make(
+		WithSubject([]string{`/opt/a\:b/bin:/usr/bin`}),
+		WithDelim(":"),
+		WithEscape(),
+	)
+
+	for s := range config.All() {
+		fmt.Println(s)
+	}
+
+	fmt.Println(config.String())
+	// Output:
+	// /opt/a:b/bin
+	// /usr/bin
+	// /opt/a\:b/bin:/usr/bin
+}
+
+// ExampleWithDelims demonstrates normalizing a list with mixed delimiters.
+func ExampleWithDelims() {
+	config := Make(
+		WithSubject([]string{"/usr/local/bin;/usr/bin:/bin"}),
+		WithDelims(":", ";"),
+	)
+
+	fmt.Println(config.String())
+	// Output: /usr/local/bin:/usr/bin:/bin
+}
+
+// ExampleWithOutputDelim demonstrates printing one element per line.
+func ExampleWithOutputDelim() {
+	config := Make(
+		WithSubject([]string{"/usr/local/bin:/usr/bin:/bin"}),
+		WithDelim(":"),
+		WithOutputDelim("\n"),
+	)
+
+	fmt.Println(config.String())
+	// Output:
+	// /usr/local/bin
+	// /usr/bin
+	// /bin
+}
+
+// ExampleWithDelimPattern demonstrates splitting on a regular expression.
+func ExampleWithDelimPattern() {
+	config := Make(
+		WithSubject([]string{"localhost, .example.com;10.0.0.0/8"}),
+		WithDelim(","),
+		WithDelimPattern(regexp.MustCompile(`[,;]\s*`)),
+	)
+
+	fmt.Println(config.String())
+	// Output: localhost,.example.com,10.0.0.0/8
+}
+
+// ExampleSubtract demonstrates finding the items added to a PATH-like value.
+func ExampleSubtract() {
+	before := Make(
+		WithSubject([]string{"/usr/bin:/bin"}),
+		WithDelim(":"),
+	)
+	after := Wrap(before, WithPrefixItems("/opt/bin"), WithSuffixItems("/sbin"))
+
+	fmt.Println(slices.Collect(Subtract(after.Filtered(), before.Filtered())))
+	// Output: [/opt/bin /sbin]
+}
+
 // ExampleConfig_String demonstrates the String method.
 func ExampleConfig_String() {
 	config := Make(
@@ -376,3 +667,166 @@ func ExampleConfig_complex() {
 	fmt.Println(config.String())
 	// Output: /usr/local/bin:/usr/bin:/sbin:/opt/bin
 }
+
+// ExampleConfig_Plan demonstrates previewing the operations of a
+// configuration before applying it.
+func ExampleConfig_Plan() {
+	plan := Make(
+		WithSubject([]string{"/usr/bin:/bin:/usr/local/old"}),
+		WithDelim(":"),
+		WithPrefix([]string{"/usr/local/bin"}),
+		WithRemove([]string{"/usr/local/old"}),
+		WithReplace(map[string]string{"/bin": "/sbin"}),
+	).Plan()
+
+	for _, step := range plan.Steps {
+		if step.Op == OpReplace {
+			fmt.Println(step.Op, step.Item, "->", step.Replacement)
+		} else {
+			fmt.Println(step.Op, step.Item)
+		}
+	}
+
+	fmt.Println(plan.Apply())
+	// Output:
+	// insert /usr/local/bin
+	// keep /usr/bin
+	// replace /bin -> /sbin
+	// remove /usr/local/old
+	// /usr/local/bin:/usr/bin:/sbin
+}
+
+// ExampleConfig_Explain demonstrates finding where each munged item came from.
+func ExampleConfig_Explain() {
+	config := Make(
+		WithSubject([]string{"/usr/bin:/bin"}),
+		WithDelim(":"),
+		WithPrefix([]string{"/usr/local/bin"}),
+		WithReplace(map[string]string{"/bin": "/sbin"}),
+	)
+
+	for item, origin := range config.Explain() {
+		switch {
+		case origin.Replaced:
+			fmt.Println(item, "replaced", origin.Item)
+		case origin.Source == ScopePrefix:
+			fmt.Println(item, "prepended")
+		default:
+			fmt.Println(item, "kept")
+		}
+	}
+	// Output:
+	// /usr/local/bin prepended
+	// /usr/bin kept
+	// /sbin replaced /bin
+}
+
+// ExampleWithRules demonstrates expressing a policy as data.
+func ExampleWithRules() {
+	rules := []Rule{
+		{Action: ActionPrepend, Value: "/usr/local/bin"},
+		{Action: ActionRemove, Match: MatchPrefix, Value: "/opt/old/"},
+		{Action: ActionEnsure, Value: "/bin"},
+	}
+
+	config, err := TryMake(
+		Try(
+			WithSubject([]string{"/usr/bin:/opt/old/bin:/opt/old/sbin"}),
+			WithDelim(":"),
+		),
+		WithRules(rules...),
+	)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	fmt.Println(config.String())
+	// Output: /usr/local/bin:/usr/bin:/bin
+}
+
+// ExampleParseRule demonstrates applying rules written in the compact syntax.
+func ExampleParseRule() {
+	opts := []Option[Config]{
+		WithSubject([]string{"/usr/bin:/bin:/opt/old/bin"}),
+		WithDelim(":"),
+	}
+
+	for _, rule := range []string{"^/usr/local/bin", "d|/opt/old/|p", "s|/bin|/sbin|"} {
+		opt, err := ParseRule(rule)
+		if err != nil {
+			fmt.Println(err)
+
+			return
+		}
+
+		opts = append(opts, opt)
+	}
+
+	fmt.Println(Make(opts...).String())
+	// Output: /usr/local/bin:/usr/bin:/sbin
+}
+
+// ExamplePrepend demonstrates the one-call helpers.
+func ExamplePrepend() {
+	path := "/usr/bin:/bin:/usr/bin"
+
+	path = Prepend(path, "/usr/local/bin", ":")
+	path = Remove(path, "/bin", ":")
+
+	fmt.Println(path)
+	fmt.Println(Has(path, "/usr/local/bin", ":"))
+	// Output:
+	// /usr/local/bin:/usr/bin
+	// true
+}
+
+// ExampleEnviron demonstrates munging the environment of a child process.
+func ExampleEnviron() {
+	env := ParseEnviron([]string{
+		"HOME=/home/me",
+		"PATH=/usr/bin:/bin:/usr/bin",
+		"LD_LIBRARY_PATH=/opt/old/lib:/usr/lib",
+	})
+
+	env.Munge("PATH", WithDelim(":"), WithPrefixItems("$HOME/bin"),
+		WithExpandEnvFunc(env.Getenv))
+	env.Munge("LD_LIBRARY_PATH", WithDelim(":"), WithRemoveItems("/opt/old/lib"))
+
+	for _, kv := range env.Environ() {
+		fmt.Println(kv)
+	}
+	// Output:
+	// HOME=/home/me
+	// PATH=/home/me/bin:/usr/bin:/bin
+	// LD_LIBRARY_PATH=/usr/lib
+}
+
+// ExampleUniq demonstrates the sequence utilities on their own.
+func ExampleUniq() {
+	for s := range Uniq(SplitSeq(":", "/usr/bin::/bin", "/usr/bin:/sbin")) {
+		fmt.Println(s)
+	}
+
+	seen := Set[int]{}
+	fmt.Println(seen.Seen(1), seen.Seen(1), Reverse([]int{1, 2, 3}))
+	// Output:
+	// /usr/bin
+	// /bin
+	// /sbin
+	// false true [3 2 1]
+}
+
+func ExampleWithGroups() {
+	local, _ := Matcher(MatchGlob, "/home/*/.local/*")
+	usrLocal, _ := Matcher(MatchPrefix, "/usr/local/")
+
+	config := Make(
+		WithSubjectItems("/usr/bin:/usr/local/bin:/home/me/.local/bin:/bin"),
+		WithDelim(":"),
+		WithGroups(local, usrLocal),
+	)
+	fmt.Println(config)
+	// Output: /home/me/.local/bin:/usr/local/bin:/usr/bin:/bin
+}
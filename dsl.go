@@ -0,0 +1,310 @@
+package mung
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseRule returns an option that applies the rule described by the compact
+// text s, e.g., to accept a handful of rules on a command line or in an
+// environment variable.
+//
+// The first character of s identifies the action of the rule:
+//
+//	^value             prepend value
+//	$value             append value
+//	+value             append value unless it is already yielded (ensure)
+//	-value             remove items equal to value
+//	d|pattern|flags    remove items matching pattern
+//	s|pattern|to|flags replace items matching pattern with to
+//
+// The delimiter following d or s may be any character not contained in the
+// pattern or replacement, e.g., "s#/usr#/opt#p".
+// The optional flags select how pattern matches items: g ([MatchGlob]),
+// r ([MatchRegex]), or p ([MatchPrefix]). By default, items must equal the
+// pattern. See [Rule] for the semantics of each action and match kind.
+func ParseRule(s string) (Option[Config], error) {
+	rule, err := parseRule(s)
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := rule.option()
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", s, err)
+	}
+
+	return opt, nil
+}
+
+// parseRule returns the [Rule] described by the compact text s, without
+// validating that it can be compiled into options.
+func parseRule(s string) (Rule, error) {
+	if s == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	var rule Rule
+
+	switch op, value := s[0], s[1:]; op {
+	case '^':
+		rule = Rule{Action: ActionPrepend, Value: value}
+
+	case '$':
+		rule = Rule{Action: ActionAppend, Value: value}
+
+	case '+':
+		rule = Rule{Action: ActionEnsure, Value: value}
+
+	case '-':
+		rule = Rule{Action: ActionRemove, Value: value}
+
+	case 'd', 's':
+		fields := 2
+		if op == 's' {
+			rule.Action, fields = ActionReplace, 3
+		} else {
+			rule.Action = ActionRemove
+		}
+
+		if value == "" {
+			return Rule{}, fmt.Errorf("%w: %q: missing delimiter", ErrInvalidRule, s)
+		}
+
+		// The delimiter may be any single character, including multibyte runes.
+		_, size := utf8.DecodeRuneInString(value)
+		delim := value[:size]
+
+		parts := strings.Split(value[len(delim):], delim)
+		if len(parts) != fields {
+			return Rule{}, fmt.Errorf("%w: %q: want %d fields delimited by %q",
+				ErrInvalidRule, s, fields, delim)
+		}
+
+		rule.Value = parts[0]
+		if op == 's' {
+			rule.Replacement = parts[1]
+		}
+
+		match, err := parseMatch(parts[fields-1])
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %q: %w", ErrInvalidRule, s, err)
+		}
+
+		rule.Match = match
+
+	default:
+		return Rule{}, fmt.Errorf("%w: %q: unknown action %q",
+			ErrInvalidRule, s, op)
+	}
+
+	return rule, nil
+}
+
+// parseMatch returns the match kind selected by the flags of a compact rule.
+func parseMatch(flags string) (Match, error) {
+	switch flags {
+	case "":
+		return MatchExact, nil
+	case "g":
+		return MatchGlob, nil
+	case "r":
+		return MatchRegex, nil
+	case "p":
+		return MatchPrefix, nil
+	default:
+		return MatchExact, fmt.Errorf("unknown flags %q", flags)
+	}
+}
+
+// ruleDelims holds the delimiters tried, in order, when formatting a rule that
+// has a pattern.
+const ruleDelims = "|#,;!@%~"
+
+// text returns the compact text describing the receiver, which must be valid.
+func (r Rule) text() (string, error) {
+	switch r.Action {
+	case ActionPrepend:
+		return "^" + r.Value, nil
+	case ActionAppend:
+		return "$" + r.Value, nil
+	case ActionEnsure:
+		return "+" + r.Value, nil
+	}
+
+	if r.Action == ActionRemove && r.Match == MatchExact {
+		return "-" + r.Value, nil
+	}
+
+	flags := map[Match]string{MatchGlob: "g", MatchRegex: "r", MatchPrefix: "p"}
+
+	for _, d := range ruleDelims {
+		delim := string(d)
+		if strings.Contains(r.Value+r.Replacement, delim) {
+			continue
+		}
+
+		if r.Action == ActionRemove {
+			return "d" + delim + r.Value + delim + flags[r.Match], nil
+		}
+
+		return "s" + delim + r.Value + delim + r.Replacement + delim +
+			flags[r.Match], nil
+	}
+
+	return "", fmt.Errorf("%w: %q: no delimiter in %q", ErrInvalidRule,
+		r.Value, ruleDelims)
+}
+
+// ErrUnencodable is wrapped by errors reporting a configuration with settings
+// that cannot be encoded by [Config.MarshalText].
+var ErrUnencodable = errors.New("setting cannot be encoded as rules")
+
+// MarshalText implements [encoding.TextMarshaler], encoding the receiver's
+// prefix, suffix, ensured, remove, and replacement strings, and the rules of
+// [WithRules], as compact rules separated by spaces, e.g., to embed a
+// configuration in a flag value or environment variable. See [ParseRule] for
+// the syntax of each rule.
+//
+// Rules containing spaces or unprintable characters are quoted as Go string
+// literals. MarshalText fails with an error wrapping [ErrUnencodable] if any
+// other setting is set, e.g., the subject strings, delimiters, or predicate
+// functions, so that decoding the text with [Config.UnmarshalText] into the
+// zero Config always yields a configuration equivalent to the receiver. Use
+// [Config.UnmarshalText] with a receiver holding the other settings to
+// restore a configuration with both.
+func (c Config) MarshalText() ([]byte, error) {
+	c = c.materializeRules()
+
+	if rest := c.withoutRules(); !rest.Equal(Config{}) {
+		return nil, fmt.Errorf("%w: settings other than rules are set",
+			ErrUnencodable)
+	}
+
+	rules := make([]Rule, 0,
+		len(c.prefix)+len(c.suffix)+len(c.ensure)+len(c.remove)+len(c.replace))
+
+	for _, s := range c.prefix {
+		rules = append(rules, Rule{Action: ActionPrepend, Value: s})
+	}
+
+	for _, s := range c.suffix {
+		rules = append(rules, Rule{Action: ActionAppend, Value: s})
+	}
+
+	for _, s := range c.ensure {
+		rules = append(rules, Rule{Action: ActionEnsure, Value: s})
+	}
+
+	for _, s := range c.remove {
+		rules = append(rules, Rule{Action: ActionRemove, Value: s})
+	}
+
+	rules = append(rules, c.removalRules()...)
+
+	for _, from := range slices.Sorted(maps.Keys(c.replace)) {
+		rules = append(rules, Rule{
+			Action: ActionReplace, Value: from, Replacement: c.replace[from],
+		})
+	}
+
+	for _, r := range c.rewrites {
+		rules = append(rules, r.rule)
+	}
+
+	fields := make([]string, 0, len(rules))
+
+	for _, rule := range rules {
+		if rule.Value == "" {
+			// Empty strings have no effect.
+			continue
+		}
+
+		text, err := rule.text()
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.ContainsFunc(text, func(r rune) bool {
+			return unicode.IsSpace(r) || !unicode.IsPrint(r)
+		}) {
+			text = strconv.Quote(text)
+		}
+
+		fields = append(fields, text)
+	}
+
+	return []byte(strings.Join(fields, " ")), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], replacing the
+// receiver's prefix, suffix, ensured, remove, and replacement strings, and
+// the rules of [WithRules], with those described by the compact rules in
+// text, as encoded by [Config.MarshalText]. The rules are applied as if by
+// [ParseRule] and [WithRules], and other settings of the receiver are
+// preserved.
+func (c *Config) UnmarshalText(text []byte) error {
+	var rules []Rule
+
+	rest := strings.TrimLeftFunc(string(text), unicode.IsSpace)
+	for rest != "" {
+		field := rest
+
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrInvalidRule, rest, err)
+			}
+
+			field, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+
+			next, _ := utf8.DecodeRuneInString(rest)
+			if rest != "" && !unicode.IsSpace(next) {
+				return fmt.Errorf("%w: %s: missing space after quoted rule",
+					ErrInvalidRule, quoted)
+			}
+		} else if i := strings.IndexFunc(rest, unicode.IsSpace); i >= 0 {
+			field, rest = rest[:i], rest[i:]
+		} else {
+			rest = ""
+		}
+
+		rule, err := parseRule(field)
+		if err != nil {
+			return err
+		}
+
+		if _, err := rule.option(); err != nil {
+			return fmt.Errorf("%q: %w", field, err)
+		}
+
+		rules = append(rules, rule)
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+
+	config, err := WithRules(rules...)(c.withoutRules())
+	if err != nil {
+		return err
+	}
+
+	*c = config
+
+	return nil
+}
+
+// withoutRules returns a copy of the receiver without the settings encoded by
+// [Config.MarshalText].
+func (c Config) withoutRules() Config {
+	c.prefix, c.suffix, c.remove, c.ensure = nil, nil, nil, nil
+	c.sources.prefix, c.sources.suffix, c.sources.remove = nil, nil, nil
+	c.replace, c.removals, c.rewrites = nil, nil, nil
+
+	return c
+}
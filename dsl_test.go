@@ -0,0 +1,279 @@
+package mung
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		want  string
+	}{
+		{
+			name:  "prepend",
+			rules: []string{"^/usr/local/bin"},
+			want:  "/usr/local/bin:/usr/bin:/bin:/old/bin",
+		},
+		{
+			name:  "append",
+			rules: []string{"$/opt/bin", "$/bin"},
+			want:  "/usr/bin:/old/bin:/opt/bin:/bin",
+		},
+		{
+			name:  "ensure",
+			rules: []string{"+/bin", "+/sbin"},
+			want:  "/usr/bin:/bin:/old/bin:/sbin",
+		},
+		{
+			name:  "remove",
+			rules: []string{"-/old/bin"},
+			want:  "/usr/bin:/bin",
+		},
+		{
+			name:  "remove_pattern",
+			rules: []string{"d|/old/*|g", "d#^/b#r"},
+			want:  "/usr/bin",
+		},
+		{
+			name:  "replace",
+			rules: []string{"s|/bin|/sbin|"},
+			want:  "/usr/bin:/sbin:/old/bin",
+		},
+		{
+			name:  "replace_prefix",
+			rules: []string{"s#/usr#/opt#p"},
+			want:  "/opt/bin:/bin:/old/bin",
+		},
+		{
+			name:  "multibyte_delim",
+			rules: []string{"s→/old/bin→/new/bin→"},
+			want:  "/usr/bin:/bin:/new/bin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option[Config]{
+				WithSubject([]string{"/usr/bin:/bin:/old/bin"}),
+				WithDelim(":"),
+			}
+
+			for _, s := range tt.rules {
+				opt, err := ParseRule(s)
+				if err != nil {
+					t.Fatalf("ParseRule(%q) error = %v", s, err)
+				}
+
+				opts = append(opts, opt)
+			}
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"", "invalid rule: empty rule"},
+		{"^", `"^": invalid rule: empty value`},
+		{"/usr/bin", `invalid rule: "/usr/bin": unknown action '/'`},
+		{"s", `invalid rule: "s": missing delimiter`},
+		{"s|a|b", `invalid rule: "s|a|b": want 3 fields delimited by "|"`},
+		{"d|a|b|", `invalid rule: "d|a|b|": want 2 fields delimited by "|"`},
+		{"s|a|b|x", `invalid rule: "s|a|b|x": unknown flags "x"`},
+		{"d|(|r", `"d|(|r": invalid rule: error parsing regexp`},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseRule(tt.rule)
+		if !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("ParseRule(%q) error = %v, want %v",
+				tt.rule, err, ErrInvalidRule)
+
+			continue
+		}
+
+		if !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("ParseRule(%q) error = %q, want prefix %q",
+				tt.rule, err, tt.want)
+		}
+	}
+}
+
+func TestConfigMarshalText(t *testing.T) {
+	config := Make(
+		WithPrefixItems("/usr/local/bin", "/home/me/bin"),
+		WithSuffixItems("/opt/bin"),
+		WithRemoveItems("/old/bin", ""),
+		WithReplace(map[string]string{
+			"/bin":           "/sbin",
+			"/a|b":           "/c",
+			"/Program Files": "/pf",
+		}),
+	)
+
+	text, err := config.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+
+	want := `^/usr/local/bin ^/home/me/bin $/opt/bin -/old/bin ` +
+		`"s|/Program Files|/pf|" s#/a|b#/c# s|/bin|/sbin|`
+	if string(text) != want {
+		t.Errorf("MarshalText() = %s, want %s", text, want)
+	}
+
+	other := []Option[Config]{
+		WithSubject([]string{"/usr/bin:/bin"}), WithDelim(":"),
+	}
+
+	got := Make(other...)
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+
+	if want := Wrap(config, other...).String(); got.String() != want {
+		t.Errorf("UnmarshalText() String() = %q, want %q", got.String(), want)
+	}
+
+	if !slicesEqual(got.prefix, config.prefix) ||
+		!slicesEqual(got.suffix, config.suffix) ||
+		!mapsEqual(got.replace, config.replace) {
+		t.Errorf("UnmarshalText() = %+v, want %+v", got, config)
+	}
+}
+
+func TestConfigMarshalTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []OptionE[Config]
+		rules string
+	}{
+		{
+			name: "exact",
+			opts: []OptionE[Config]{Try(
+				WithPrefixItems("/p"), WithSuffixItems("/s"),
+				WithRemoveItems("/x"), WithReplaceItem("/usr/bin", "/u"),
+			)},
+			rules: "^/p $/s -/x s|/usr/bin|/u|",
+		},
+		{
+			name: "ensure",
+			opts: []OptionE[Config]{WithRules(
+				Rule{Action: ActionEnsure, Value: "/bin"},
+				Rule{Action: ActionEnsure, Value: "/e"},
+			)},
+			rules: "+/bin +/e",
+		},
+		{
+			name: "patterns",
+			opts: []OptionE[Config]{WithRules(
+				Rule{Action: ActionRemove, Match: MatchGlob, Value: "/opt/*"},
+				Rule{Action: ActionRemove, Match: MatchPrefix, Value: "/tmp/"},
+				Rule{Action: ActionRemove, Match: MatchPrefix, Value: "/var/"},
+				Rule{
+					Action: ActionReplace, Match: MatchRegex,
+					Value: `^/usr/(\w+)$`, Replacement: "/u/$1",
+				},
+			)},
+			rules: `d|/opt/*|g d|/tmp/|p d|/var/|p s|^/usr/(\w+)$|/u/$1|r`,
+		},
+	}
+
+	subject := "/tmp/a:/opt/b:/usr/bin:/var/c:/bin:/x"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := TryMake(tt.opts...)
+			if err != nil {
+				t.Fatalf("TryMake() error = %v", err)
+			}
+
+			text, err := config.MarshalText()
+			if err != nil || string(text) != tt.rules {
+				t.Fatalf("MarshalText() = %s, %v, want %s, nil",
+					text, err, tt.rules)
+			}
+
+			var got Config
+			if err := got.UnmarshalText(text); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+
+			if !got.Equal(config) {
+				t.Errorf("UnmarshalText() = %+v, want %+v", got, config)
+			}
+
+			other := []Option[Config]{
+				WithSubject([]string{subject}), WithDelim(":"),
+			}
+
+			want := Wrap(config, other...).String()
+			if got := Wrap(got, other...).String(); got != want {
+				t.Errorf("UnmarshalText() String() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestConfigMarshalTextUnencodable(t *testing.T) {
+	for name, opt := range map[string]Option[Config]{
+		"subject":   WithSubjectItems("/bin"),
+		"delim":     WithDelim(";"),
+		"filter":    WithFilter(func(string) bool { return true }),
+		"case_fold": WithCaseFold(),
+		"stages":    WithStages(upperStage),
+		"tagged":    WithPrefixTagged("t", "/p"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			text, err := Make(WithPrefixItems("/q"), opt).MarshalText()
+			if !errors.Is(err, ErrUnencodable) || text != nil {
+				t.Errorf("MarshalText() = %q, %v, want nil, %v",
+					text, err, ErrUnencodable)
+			}
+		})
+	}
+}
+
+func TestConfigUnmarshalText(t *testing.T) {
+	config := Make(
+		WithSubject([]string{"a:b:c"}),
+		WithDelim(":"),
+		WithPrefixItems("x"),
+	)
+
+	if err := config.UnmarshalText([]byte("  ^p\t-b  d|c*|g\n")); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+
+	if got, want := config.String(), "p:a"; got != want {
+		t.Errorf("UnmarshalText() String() = %q, want %q", got, want)
+	}
+
+	for _, text := range []string{`^a "-b`, `"^a"b`, `^a /b`, `s|a|b`} {
+		before := config
+
+		err := config.UnmarshalText([]byte(text))
+		if !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("UnmarshalText(%q) error = %v, want %v",
+				text, err, ErrInvalidRule)
+		}
+
+		if !config.Equal(before) {
+			t.Errorf("UnmarshalText(%q) modified receiver", text)
+		}
+	}
+
+	var empty Config
+	if err := empty.UnmarshalText(nil); err != nil {
+		t.Errorf("UnmarshalText(nil) error = %v", err)
+	}
+}
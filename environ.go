@@ -0,0 +1,214 @@
+package mung
+
+import (
+	"iter"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Environ is a set of environment variables, e.g., to munge several PATH-like
+// variables of the environment of a child process coherently before passing
+// it to [os/exec.Cmd].
+//
+// Each variable is either defined by a value, as parsed by [ParseEnviron] or
+// set with [Environ.SetValue], or by a configuration, as set with
+// [Environ.Set] or [Environ.Munge], whose munged string is the value of the
+// variable. Variables defined by a value are never munged, so that variables
+// that are not PATH-like are passed through unchanged.
+//
+// Variable names are case-sensitive, and variables are kept in the order they
+// were first defined. The zero value is an empty environment.
+// Copies of an Environ share its variables, so that a variable defined or
+// removed through one copy is defined or removed in each; use [Environ.Clone]
+// to obtain an independent copy. Copies of the zero value share variables
+// only once any variable is defined.
+type Environ struct {
+	vars *environVars
+}
+
+// environVars holds the variables shared by copies of an [Environ].
+type environVars struct {
+	names  []string
+	values map[string]string
+	config map[string]Config
+}
+
+// ParseEnviron returns an environment of the variables in env, where each
+// string has the form "name=value", e.g., as returned by [os.Environ].
+//
+// Strings without a "=" are ignored, except that a leading "=" is part of
+// the name, as in the per-drive variables of Windows. If a variable is
+// defined more than once, the last value is used.
+func ParseEnviron(env []string) Environ {
+	var e Environ
+
+	for _, kv := range env {
+		if kv == "" {
+			continue
+		}
+
+		// Search from the second byte, so that a leading "=" is in the name.
+		if i := strings.IndexByte(kv[1:], '=') + 1; i > 0 {
+			e.SetValue(kv[:i], kv[i+1:])
+		}
+	}
+
+	return e
+}
+
+// Clone returns a copy of the receiver that shares no variables with it.
+// Configurations are copied with [Config.Clone].
+func (e Environ) Clone() Environ {
+	v := e.get()
+
+	c := &environVars{
+		names:  slices.Clone(v.names),
+		values: maps.Clone(v.values),
+		config: make(map[string]Config, len(v.config)),
+	}
+
+	for name, config := range v.config {
+		c.config[name] = config.Clone()
+	}
+
+	return Environ{vars: c}
+}
+
+// Lookup returns the value of the named variable and true, or the empty
+// string and false if it is not defined.
+// The value of a variable defined by a configuration is its munged string.
+func (e Environ) Lookup(name string) (string, bool) {
+	v := e.get()
+	if config, ok := v.config[name]; ok {
+		return config.String(), true
+	}
+
+	value, ok := v.values[name]
+
+	return value, ok
+}
+
+// Getenv returns the value of the named variable, or the empty string if it is
+// not defined. Its signature matches [os.Getenv], so that it can be given to
+// [WithExpandEnvFunc] to expand references to other variables of the
+// receiver.
+func (e Environ) Getenv(name string) string {
+	value, _ := e.Lookup(name)
+
+	return value
+}
+
+// Config returns the configuration of the named variable.
+//
+// If the variable is defined by a value, or not defined, the configuration
+// has a subject of its value and the delimiter [os.PathListSeparator].
+func (e Environ) Config(name string) Config {
+	v := e.get()
+	if config, ok := v.config[name]; ok {
+		return config
+	}
+
+	var subject []string
+	if value, ok := v.values[name]; ok {
+		subject = []string{value}
+	}
+
+	return Make(
+		WithSubject(subject), WithDelim(string(os.PathListSeparator)),
+	)
+}
+
+// Set defines the named variable by config, whose munged string is the value
+// of the variable. The string is munged each time the value is read.
+func (e *Environ) Set(name string, config Config) {
+	v := e.define(name)
+	delete(v.values, name)
+	v.config[name] = config
+}
+
+// Munge applies the given options to the configuration of the named variable,
+// as returned by [Environ.Config], and defines the variable by the result,
+// e.g., env.Munge("PATH", WithPrefixItems("/opt/bin")).
+func (e *Environ) Munge(name string, opts ...Option[Config]) {
+	e.Set(name, Wrap(e.Config(name), opts...))
+}
+
+// SetValue defines the named variable by value, which is never munged.
+func (e *Environ) SetValue(name, value string) {
+	v := e.define(name)
+	delete(v.config, name)
+	v.values[name] = value
+}
+
+// Unset removes the named variable.
+func (e *Environ) Unset(name string) {
+	v := e.get()
+	if i := slices.Index(v.names, name); i >= 0 {
+		v.names = slices.Delete(v.names, i, i+1)
+	}
+
+	delete(v.values, name)
+	delete(v.config, name)
+}
+
+// All returns a sequence of the name and value of each variable, in the order
+// they were first defined.
+func (e Environ) All() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for _, name := range e.get().names {
+			value, _ := e.Lookup(name)
+			if !yield(name, value) {
+				return
+			}
+		}
+	}
+}
+
+// Environ returns the variables as strings of the form "name=value", in the
+// order they were first defined, e.g., for [os/exec.Cmd.Env].
+func (e Environ) Environ() []string {
+	env := make([]string, 0, len(e.get().names))
+
+	for name, value := range e.All() {
+		env = append(env, name+"="+value)
+	}
+
+	return env
+}
+
+// get returns the receiver's variables, which are empty if none is defined.
+func (e Environ) get() *environVars {
+	if e.vars == nil {
+		return &environVars{}
+	}
+
+	return e.vars
+}
+
+// define adds name to the receiver's variable names unless it is defined, and
+// returns the receiver's variables, allocating them if none is defined.
+func (e *Environ) define(name string) *environVars {
+	if e.vars == nil {
+		e.vars = &environVars{}
+	}
+
+	v := e.vars
+	if v.values == nil {
+		v.values = map[string]string{}
+	}
+
+	if v.config == nil {
+		v.config = map[string]Config{}
+	}
+
+	_, isValue := v.values[name]
+	_, isConfig := v.config[name]
+
+	if !isValue && !isConfig {
+		v.names = append(v.names, name)
+	}
+
+	return v
+}
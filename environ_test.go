@@ -0,0 +1,150 @@
+package mung
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseEnviron(t *testing.T) {
+	tests := []struct {
+		name string
+		env  []string
+		want []string
+	}{
+		{
+			name: "empty",
+			want: []string{},
+		},
+		{
+			name: "order",
+			env:  []string{"B=2", "A=1", "C="},
+			want: []string{"B=2", "A=1", "C="},
+		},
+		{
+			name: "last_value",
+			env:  []string{"A=1", "B=2", "A=3"},
+			want: []string{"A=3", "B=2"},
+		},
+		{
+			name: "malformed",
+			env:  []string{"", "A", "=", "B=x=y"},
+			want: []string{"B=x=y"},
+		},
+		{
+			name: "windows_drive",
+			env:  []string{`=C:=C:\Windows`},
+			want: []string{`=C:=C:\Windows`},
+		},
+		{
+			name: "not_munged",
+			env:  []string{"A=a::a"},
+			want: []string{"A=a::a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseEnviron(tt.env).Environ()
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Environ() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvironMunge(t *testing.T) {
+	sep := string(os.PathListSeparator)
+
+	env := ParseEnviron([]string{
+		"HOME=/home/me",
+		"PATH=/usr/bin" + sep + "/bin" + sep + "/usr/bin",
+	})
+	orig := env.Clone()
+
+	env.Munge("PATH", WithPrefixItems("/opt/bin"))
+	env.Munge("PATH", WithRemoveItems("/bin"))
+	env.Munge("MANPATH",
+		WithSubjectItems("$HOME/man"), WithExpandEnvFunc(env.Getenv),
+	)
+	env.SetValue("HOME", "/root")
+
+	want := []string{
+		"HOME=/root",
+		"PATH=/opt/bin" + sep + "/usr/bin",
+		"MANPATH=/root/man",
+	}
+	if got := env.Environ(); !slicesEqual(got, want) {
+		t.Errorf("Environ() = %q, want %q", got, want)
+	}
+
+	want = []string{"HOME=/home/me", "PATH=/usr/bin" + sep + "/bin" + sep + "/usr/bin"}
+	if got := orig.Environ(); !slicesEqual(got, want) {
+		t.Errorf("Clone() Environ() = %q, want %q", got, want)
+	}
+
+	env.Unset("PATH")
+	env.Unset("TERM")
+
+	if got, ok := env.Lookup("PATH"); got != "" || ok {
+		t.Errorf("Lookup() after Unset() = %q, %v, want %q, false", got, ok, "")
+	}
+
+	if got, want := env.Config("PATH").Subject(), []string(nil); !slicesEqual(got, want) {
+		t.Errorf("Config() after Unset() Subject() = %q, want %q", got, want)
+	}
+
+	env.Set("PATH", Make(WithSubject([]string{"/sbin"})))
+
+	want = []string{"HOME=/root", "MANPATH=/root/man", "PATH=/sbin"}
+	if got := env.Environ(); !slicesEqual(got, want) {
+		t.Errorf("Environ() after Set() = %q, want %q", got, want)
+	}
+}
+
+func TestEnvironZero(t *testing.T) {
+	var env Environ
+
+	if got := env.Environ(); len(got) != 0 {
+		t.Errorf("Environ() = %q, want empty", got)
+	}
+
+	env.Munge("PATH", WithSubjectItems("/bin"))
+
+	if got, ok := env.Lookup("PATH"); got != "/bin" || !ok {
+		t.Errorf("Lookup() = %q, %v, want %q, true", got, ok, "/bin")
+	}
+}
+
+func TestEnvironCopy(t *testing.T) {
+	env := ParseEnviron([]string{"PATH=/bin", "A=1", "B=2"})
+	clone := env.Clone()
+
+	cp := env
+	cp.Unset("A")
+	cp.SetValue("C", "3")
+
+	want := []string{"PATH=/bin", "B=2", "C=3"}
+	for _, e := range []Environ{env, cp} {
+		if got := e.Environ(); !slicesEqual(got, want) {
+			t.Errorf("Environ() = %q, want %q", got, want)
+		}
+	}
+
+	want = []string{"PATH=/bin", "A=1", "B=2"}
+	if got := clone.Environ(); !slicesEqual(got, want) {
+		t.Errorf("Clone().Environ() = %q, want %q", got, want)
+	}
+
+	var zero Environ
+
+	clone = zero.Clone()
+	clone.SetValue("A", "1")
+
+	if got := zero.Environ(); len(got) != 0 {
+		t.Errorf("zero Environ() = %q, want empty", got)
+	}
+
+	if got := clone.Environ(); !slicesEqual(got, []string{"A=1"}) {
+		t.Errorf("Clone().Environ() = %q, want %q", got, []string{"A=1"})
+	}
+}
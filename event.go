@@ -0,0 +1,177 @@
+package mung
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Op identifies an operation applied to an item while realizing the munged
+// sequence.
+type Op int
+
+// Constant values of Op.
+const (
+	// OpKeep keeps an item from the subject.
+	OpKeep Op = iota
+	// OpInsert inserts an item from the prefix or suffix.
+	OpInsert
+	// OpDuplicate drops an item that is yielded in another position.
+	OpDuplicate
+	// OpRemove drops an item that matches a remove rule.
+	OpRemove
+	// OpReplace keeps or inserts an item that matches a replacement rule,
+	// yielding its replacement instead.
+	OpReplace
+	// OpFilter drops an item rejected by the predicate function or any
+	// built-in filters.
+	OpFilter
+	// OpPin moves or inserts an item pinned with [WithPin].
+	OpPin
+)
+
+// Internal operations reported while realizing the munged sequence.
+const (
+	// opOutput reports an item leaving the replacement stage unchanged.
+	opOutput Op = -1 - iota
+	// opSuperseded reports that a kept or inserted item is dropped by
+	// [WithDedupeKeepLast] in favor of a later occurrence.
+	opSuperseded
+	// opHeld reports an item collected by a stage that reorders items.
+	opHeld
+	// opReleased reports that a stage that reorders items yields the item it
+	// collected with the given ordinal.
+	opReleased
+)
+
+// String returns the name of the operation.
+func (op Op) String() string {
+	switch op {
+	case OpKeep:
+		return "keep"
+	case OpInsert:
+		return "insert"
+	case OpDuplicate:
+		return "duplicate"
+	case OpRemove:
+		return "remove"
+	case OpReplace:
+		return "replace"
+	case OpFilter:
+		return "filter"
+	case OpPin:
+		return "pin"
+	default:
+		return "unknown"
+	}
+}
+
+// event describes an operation applied to an item while realizing the munged
+// sequence.
+type event struct {
+	op     Op
+	item   string
+	source Scope
+	rule   string    // remove rule or key of replacement rule
+	to     string    // replacement
+	index  int       // ordinal of the item superseded or released
+	stage  StageName // stage holding or releasing the item
+}
+
+// emit reports ev to the receiver's observer, if any.
+func (c Config) emit(ev event) {
+	if c.observe != nil {
+		c.observe(ev)
+	}
+}
+
+// passed returns the operation applied to an item yielded from source.
+func passed(source Scope) Op {
+	if source == ScopeSubject {
+		return OpKeep
+	}
+
+	return OpInsert
+}
+
+// dropped returns the event reporting that item s from source is not yielded,
+// either because it matches a rule in remove or because it is a duplicate.
+func (c Config) dropped(s string, source Scope, remove set[string]) event {
+	if remove.Contains(s) {
+		v := viewOf(remove, s)
+
+		rule := c.rule(c.removed(source), v)
+		if rule == "" {
+			rule, _ = c.removalOf(v)
+		}
+
+		return event{op: OpRemove, item: s, source: source, rule: rule}
+	}
+
+	return event{op: OpDuplicate, item: s, source: source}
+}
+
+// rule returns the first of the given rules equal to item s.
+func (c Config) rule(rules []string, s string) string {
+	for r := range c.split(rules) {
+		if c.equalKeys(r, s) {
+			return r
+		}
+	}
+
+	return ""
+}
+
+// replaceRule returns the key of the replacement rule that matches item s.
+func (c Config) replaceRule(s string) string {
+	for _, from := range slices.Sorted(maps.Keys(c.replace)) {
+		if c.equalKeys(c.normalize(c.expandEnv(from)), s) {
+			return from
+		}
+	}
+
+	return ""
+}
+
+// observed returns a function like replace that also reports each item to
+// the receiver's observer.
+func (c Config) observed(
+	replace func(string) (string, bool),
+) func(string) (string, bool) {
+	return func(s string) (string, bool) {
+		r, ok := replace(s)
+		if ok {
+			c.emit(event{op: OpReplace, item: s, rule: c.replaceRule(s), to: r})
+		} else {
+			c.emit(event{op: opOutput, item: s})
+		}
+
+		return r, ok
+	}
+}
+
+// keepLastObserved is like [keepLast] but also reports each item superseded
+// by a later occurrence to the receiver's observer.
+func (c Config) keepLastObserved(items iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		all := slices.Collect(items)
+		keep := make([]bool, len(all))
+		prev := c.newSet()
+
+		for i := len(all) - 1; i >= 0; i-- {
+			keep[i] = !prev.Seen(all[i])
+		}
+
+		for i, s := range all {
+			if !keep[i] {
+				c.emit(event{op: opSuperseded, item: s, index: i})
+			}
+		}
+
+		for i, s := range all {
+			if keep[i] && !yield(s) {
+				return
+			}
+		}
+	}
+}
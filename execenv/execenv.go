@@ -0,0 +1,129 @@
+// Package execenv runs commands with munged environments, e.g., to implement
+// a wrapper that runs cmake with a filtered PATH:
+//
+//	cmd := exec.Command("cmake", args...)
+//	err := execenv.Munge(cmd, "PATH",
+//		mung.WithExclude(func(s string) bool {
+//			return strings.HasPrefix(s, "/opt/conda/")
+//		}),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	return cmd.Run()
+//
+// Each helper starts from the environment the command would otherwise run
+// with, as returned by [exec.Cmd.Environ], so variables that are not munged
+// are passed through unchanged.
+package execenv
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ardnew/mung"
+)
+
+// Set defines the named variable of the environment of cmd as the munged
+// string of config, replacing any value it already has.
+//
+// If munging fails, as reported by [mung.Config.StringErr], the environment
+// of cmd is not modified and the error is returned.
+func Set(cmd *exec.Cmd, name string, config mung.Config) error {
+	value, err := config.StringErr()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	env := mung.ParseEnviron(cmd.Environ())
+	env.SetValue(name, value)
+	cmd.Env = env.Environ()
+
+	return nil
+}
+
+// Munge applies the given options to the configuration of the named variable
+// of the environment of cmd, as returned by [mung.Environ.Config], and
+// defines the variable as the munged string of the result, as if by [Set].
+//
+// The configuration has the current value of the variable as its subject and
+// [os.PathListSeparator] as its delimiter, unless the options change them.
+func Munge(cmd *exec.Cmd, name string, opts ...mung.Option[mung.Config]) error {
+	env := mung.ParseEnviron(cmd.Environ())
+
+	return Set(cmd, name, mung.Wrap(env.Config(name), opts...))
+}
+
+// Apply sets the environment of cmd to the variables of env, e.g., to run
+// several commands with the same munged environment.
+// Variables not defined by env are not inherited from the current process.
+func Apply(cmd *exec.Cmd, env mung.Environ) {
+	cmd.Env = env.Environ()
+}
+
+// Command is like [exec.Command] but returns a command that runs with the
+// variables of env as its environment.
+//
+// If name contains no path separators, it is resolved using the PATH variable
+// of env instead of the PATH of the current process, as if by [LookPath].
+func Command(env mung.Environ, name string, arg ...string) *exec.Cmd {
+	return command(exec.Command(name, arg...), env, name)
+}
+
+// CommandContext is like [exec.CommandContext] but returns a command that
+// runs with the variables of env as its environment.
+//
+// If name contains no path separators, it is resolved using the PATH variable
+// of env, as by [Command].
+func CommandContext(
+	ctx context.Context, env mung.Environ, name string, arg ...string,
+) *exec.Cmd {
+	return command(exec.CommandContext(ctx, name, arg...), env, name)
+}
+
+// LookPath is like [exec.LookPath] but searches the directories named by the
+// PATH variable of env instead of the PATH of the current process.
+// Relative directories are ignored, so that a command is never run from the
+// current directory by accident, as described by [exec.ErrDot].
+func LookPath(env mung.Environ, file string) (string, error) {
+	if strings.ContainsAny(file, pathSeparators) {
+		return exec.LookPath(file)
+	}
+
+	path, _ := env.Lookup("PATH")
+	for _, dir := range filepath.SplitList(path) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+
+		if found, err := exec.LookPath(filepath.Join(dir, file)); err == nil {
+			return found, nil
+		}
+	}
+
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+// pathSeparators contains the characters that separate the elements of a
+// path, any of which keeps a command name from being searched for in PATH.
+const pathSeparators = `/` + string(filepath.Separator)
+
+// command applies env to cmd and resolves name, the name of the command run
+// by cmd, using the PATH variable of env.
+func command(cmd *exec.Cmd, env mung.Environ, name string) *exec.Cmd {
+	Apply(cmd, env)
+
+	if !strings.ContainsAny(name, pathSeparators) {
+		path, err := LookPath(env, name)
+		if err != nil {
+			path = name
+		}
+
+		cmd.Path, cmd.Err = path, err
+	}
+
+	return cmd
+}
@@ -0,0 +1,120 @@
+package execenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ardnew/mung"
+)
+
+func TestSet(t *testing.T) {
+	cmd := exec.Command("true")
+	cmd.Env = []string{"A=1", "PATH=/bin", "B=2"}
+
+	config := mung.Make(
+		mung.WithSubject([]string{"/usr/bin:/bin:/usr/bin"}), mung.WithDelim(":"),
+	)
+	if err := Set(cmd, "PATH", config); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	want := []string{"A=1", "PATH=/usr/bin:/bin", "B=2"}
+	if !slices.Equal(cmd.Env, want) {
+		t.Errorf("Set() Env = %q, want %q", cmd.Env, want)
+	}
+
+	errFilter := errors.New("filter failed")
+	config = mung.Wrap(config,
+		mung.WithFilterErr(func(string) (bool, error) { return false, errFilter }))
+
+	if err := Set(cmd, "PATH", config); !errors.Is(err, errFilter) {
+		t.Errorf("Set() error = %v, want %v", err, errFilter)
+	}
+
+	if !slices.Equal(cmd.Env, want) {
+		t.Errorf("Set() after error Env = %q, want %q", cmd.Env, want)
+	}
+}
+
+func TestMunge(t *testing.T) {
+	sep := string(os.PathListSeparator)
+
+	t.Setenv("MUNG_TEST_PATH", "/usr/bin"+sep+"/bin")
+	t.Setenv("MUNG_TEST_VALUE", "a::a")
+
+	cmd := exec.Command("true")
+	if err := Munge(cmd, "MUNG_TEST_PATH", mung.WithPrefixItems("/opt/bin")); err != nil {
+		t.Fatalf("Munge() error = %v", err)
+	}
+
+	if err := Munge(cmd, "MUNG_TEST_NEW", mung.WithSubjectItems("/x")); err != nil {
+		t.Fatalf("Munge() error = %v", err)
+	}
+
+	env := mung.ParseEnviron(cmd.Env)
+
+	for name, want := range map[string]string{
+		"MUNG_TEST_PATH":  "/opt/bin" + sep + "/usr/bin" + sep + "/bin",
+		"MUNG_TEST_VALUE": "a::a",
+		"MUNG_TEST_NEW":   "/x",
+	} {
+		if got, _ := env.Lookup(name); got != want {
+			t.Errorf("Munge() %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	env := mung.ParseEnviron([]string{"PATH=/usr/bin:/bin:/usr/bin"})
+	env.Munge("PATH", mung.WithDelim(":"), mung.WithRemoveItems("/bin"))
+
+	want := []string{"PATH=/usr/bin"}
+
+	if cmd := Command(env, "true"); !slices.Equal(cmd.Env, want) {
+		t.Errorf("Command() Env = %q, want %q", cmd.Env, want)
+	}
+
+	cmd := CommandContext(context.Background(), env, "true")
+	if !slices.Equal(cmd.Env, want) {
+		t.Errorf("CommandContext() Env = %q, want %q", cmd.Env, want)
+	}
+}
+
+func TestCommandLookPath(t *testing.T) {
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "mung-test-tool")
+
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	env := mung.ParseEnviron([]string{"PATH=relative" + string(os.PathListSeparator) + dir})
+
+	cmd := Command(env, "mung-test-tool")
+	if cmd.Err != nil || cmd.Path != tool {
+		t.Fatalf("Command() Path = %q, Err = %v, want %q", cmd.Path, cmd.Err, tool)
+	}
+
+	if err := cmd.Run(); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+
+	// The command is not searched for in the PATH of the current process.
+	cmd = CommandContext(context.Background(), env, "true")
+	if !errors.Is(cmd.Err, exec.ErrNotFound) {
+		t.Errorf("CommandContext() Err = %v, want %v", cmd.Err, exec.ErrNotFound)
+	}
+
+	if err := cmd.Run(); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Run() error = %v, want %v", err, exec.ErrNotFound)
+	}
+
+	if _, err := LookPath(env, tool); err != nil {
+		t.Errorf("LookPath(%q) error = %v", tool, err)
+	}
+}
@@ -0,0 +1,91 @@
+package mung
+
+import "iter"
+
+// Origin describes where an item of a munged sequence came from.
+type Origin struct {
+	// Source identifies the strings the item was split from.
+	Source Scope
+	// Item is the item as split from its source and normalized, before any
+	// replacement.
+	Item string
+	// Replaced reports whether the item matched a replacement rule.
+	Replaced bool
+	// Rule is the key of the replacement rule matched by the item, if any.
+	Rule string
+	// Tag is the tag of the item given with [WithPrefixTagged] or
+	// [WithSuffixTagged], if any.
+	Tag string
+	// Pinned reports whether the item was pinned with [WithPin], in which
+	// case Item is the pinned item and the other fields are unset.
+	Pinned bool
+}
+
+// Explain returns a sequence like [Config.Filtered] that also yields the
+// [Origin] of each munged string.
+//
+// Items reordered with [WithPriority], [WithSort], or [WithGroups] keep their
+// origins, and items moved or inserted with [WithPin] are described as pinned.
+// Stages set with [WithStages] or [WithStageAfter] are applied to the sequence
+// but are not described by the origins. Each string they yield is attributed
+// to the most recent item leaving the replacement stage, so stages inserted
+// after [StageDedup] that discard or insert items shift the origins of later
+// items.
+func (c Config) Explain() iter.Seq2[string, Origin] {
+	return func(yield func(string, Origin) bool) {
+		var (
+			origin  Origin
+			pending []Origin
+			skip    []bool
+			held    = map[StageName][]Origin{}
+		)
+
+		label := c.labeler()
+
+		c.observe = func(ev event) {
+			switch ev.op {
+			case OpKeep, OpInsert:
+				pending = append(pending, Origin{
+					Source: ev.source, Item: ev.item, Tag: label(ev.item, ev.source),
+				})
+				skip = append(skip, false)
+
+			case opSuperseded:
+				// Every item is kept or inserted before any is superseded or
+				// output, so the ordinal indexes pending directly.
+				skip[ev.index] = true
+
+			case OpReplace, opOutput:
+				for len(pending) > 0 && skip[0] {
+					pending, skip = pending[1:], skip[1:]
+				}
+
+				if len(pending) > 0 {
+					origin = pending[0]
+					pending, skip = pending[1:], skip[1:]
+				}
+
+				if ev.op == OpReplace {
+					origin.Replaced, origin.Rule = true, ev.rule
+				}
+
+			case opHeld:
+				// Each item collected by a stage that reorders items is
+				// described by the origin of the item most recently output.
+				held[ev.stage] = append(held[ev.stage], origin)
+
+			case opReleased:
+				origin = held[ev.stage][ev.index]
+
+			case OpPin:
+				origin = Origin{Item: ev.item, Pinned: true}
+			}
+		}
+
+		for s := range c.Filtered() {
+			if !yield(s, origin) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package mung
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigExplain(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option[Config]
+		items   []string
+		origins []Origin
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "sources",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:c:x"}),
+				WithPrefix([]string{"p"}),
+				WithSuffix([]string{"c"}),
+				WithRemove([]string{"x"}),
+				WithReplace(map[string]string{"b": "B"}),
+			},
+			items: []string{"p", "a", "B", "c"},
+			origins: []Origin{
+				{Source: ScopePrefix, Item: "p"},
+				{Source: ScopeSubject, Item: "a"},
+				{Source: ScopeSubject, Item: "b", Replaced: true, Rule: "b"},
+				{Source: ScopeSuffix, Item: "c"},
+			},
+		},
+		{
+			name: "keep_last",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:a"}),
+				WithPrefix([]string{"b"}),
+				WithReplace(map[string]string{"a": "A"}),
+				WithDedupeKeepLast(),
+			},
+			items: []string{"b", "A"},
+			origins: []Origin{
+				{Source: ScopeSubject, Item: "b"},
+				{Source: ScopeSubject, Item: "a", Replaced: true, Rule: "a"},
+			},
+		},
+		{
+			name: "filtered",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:z:b"}),
+				WithSuffix([]string{"z:c"}),
+				WithFilter(func(s string) bool { return s != "z" }),
+			},
+			items: []string{"a", "b", "c"},
+			origins: []Origin{
+				{Source: ScopeSubject, Item: "a"},
+				{Source: ScopeSubject, Item: "b"},
+				{Source: ScopeSuffix, Item: "c"},
+			},
+		},
+		{
+			name: "tagged",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:h"}),
+				WithPrefixTagged("homebrew", "h:b"),
+				WithPrefixItems("p"),
+				WithSuffixTagged("go", "g"),
+				WithSuffixTagged("user", "a"),
+			},
+			items: []string{"p", "h", "b", "g", "a"},
+			origins: []Origin{
+				{Source: ScopePrefix, Item: "p"},
+				{Source: ScopePrefix, Item: "h", Tag: "homebrew"},
+				{Source: ScopePrefix, Item: "b", Tag: "homebrew"},
+				{Source: ScopeSuffix, Item: "g", Tag: "go"},
+				{Source: ScopeSuffix, Item: "a", Tag: "user"},
+			},
+		},
+		{
+			name: "tagged_last_wins",
+			opts: []Option[Config]{
+				WithCaseFold(),
+				WithPrefixTagged("first", "p"),
+				WithPrefixTagged("second", "P"),
+			},
+			items: []string{"P"},
+			origins: []Origin{
+				{Source: ScopePrefix, Item: "P", Tag: "second"},
+			},
+		},
+		{
+			name: "sort",
+			opts: []Option[Config]{
+				WithSubject([]string{"b:a"}),
+				WithPrefixTagged("brew", "z"),
+				WithSort(strings.Compare),
+			},
+			items: []string{"a", "b", "z"},
+			origins: []Origin{
+				{Source: ScopeSubject, Item: "a"},
+				{Source: ScopeSubject, Item: "b"},
+				{Source: ScopePrefix, Item: "z", Tag: "brew"},
+			},
+		},
+		{
+			name: "priority",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithReplace(map[string]string{"b": "B"}),
+				WithPriority(func(s string) int {
+					if s == "c" {
+						return 0
+					}
+
+					return 1
+				}),
+			},
+			items: []string{"c", "a", "B"},
+			origins: []Origin{
+				{Source: ScopeSubject, Item: "c"},
+				{Source: ScopeSubject, Item: "a"},
+				{Source: ScopeSubject, Item: "b", Replaced: true, Rule: "b"},
+			},
+		},
+		{
+			name: "groups_sort",
+			opts: []Option[Config]{
+				WithSubject([]string{"/usr/b:/home/a:/usr/a"}),
+				WithPrefixItems("/opt/x"),
+				WithSuffixTagged("user", "/home/b"),
+				WithGroups(func(s string) bool {
+					return strings.HasPrefix(s, "/home")
+				}),
+				WithSort(strings.Compare),
+			},
+			items: []string{"/home/a", "/home/b", "/opt/x", "/usr/a", "/usr/b"},
+			origins: []Origin{
+				{Source: ScopeSubject, Item: "/home/a"},
+				{Source: ScopeSuffix, Item: "/home/b", Tag: "user"},
+				{Source: ScopePrefix, Item: "/opt/x"},
+				{Source: ScopeSubject, Item: "/usr/a"},
+				{Source: ScopeSubject, Item: "/usr/b"},
+			},
+		},
+		{
+			name: "pin",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithPrefixTagged("tag", "p"),
+				WithPin("c", 0),
+				WithPin("x", -1),
+			},
+			items: []string{"c", "p", "a", "b", "x"},
+			origins: []Origin{
+				{Item: "c", Pinned: true},
+				{Source: ScopePrefix, Item: "p", Tag: "tag"},
+				{Source: ScopeSubject, Item: "a"},
+				{Source: ScopeSubject, Item: "b"},
+				{Item: "x", Pinned: true},
+			},
+		},
+		{
+			name: "pin_sort",
+			opts: []Option[Config]{
+				WithSubject([]string{"c:b:a"}),
+				WithReplace(map[string]string{"c": "C"}),
+				WithSort(strings.Compare),
+				WithPin("z", 1),
+			},
+			items: []string{"C", "z", "a", "b"},
+			origins: []Origin{
+				{Source: ScopeSubject, Item: "c", Replaced: true, Rule: "c"},
+				{Item: "z", Pinned: true},
+				{Source: ScopeSubject, Item: "a"},
+				{Source: ScopeSubject, Item: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":")}, tt.opts...)
+
+			var (
+				items   []string
+				origins []Origin
+			)
+
+			for s, origin := range Make(opts...).Explain() {
+				items = append(items, s)
+				origins = append(origins, origin)
+			}
+
+			if !slicesEqual(items, tt.items) {
+				t.Errorf("Explain() items = %v, want %v", items, tt.items)
+			}
+
+			if len(origins) != len(tt.origins) {
+				t.Fatalf("Explain() origins = %+v, want %+v", origins, tt.origins)
+			}
+
+			for i := range origins {
+				if origins[i] != tt.origins[i] {
+					t.Errorf("Explain() origin[%d] = %+v, want %+v",
+						i, origins[i], tt.origins[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConfigExplainBreak(t *testing.T) {
+	config := Make(WithSubject([]string{"a:b:c"}), WithDelim(":"))
+
+	var items []string
+
+	for s, origin := range config.Explain() {
+		if origin.Item == "b" {
+			break
+		}
+
+		items = append(items, s)
+	}
+
+	if !slicesEqual(items, []string{"a"}) {
+		t.Errorf("Explain() items = %v, want [a]", items)
+	}
+}
@@ -0,0 +1,92 @@
+package mung
+
+// FilterAnd returns a predicate function that selects strings selected by
+// every given predicate function, evaluated in order until one rejects.
+//
+// Nil predicate functions select all strings.
+// If no predicate functions are given, all strings are selected.
+func FilterAnd(predicates ...func(string) bool) func(string) bool {
+	return func(s string) bool {
+		for _, predicate := range predicates {
+			if predicate != nil && !predicate(s) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// FilterOr returns a predicate function that selects strings selected by
+// any given predicate function, evaluated in order until one selects.
+//
+// Nil predicate functions select all strings.
+// If no predicate functions are given, no strings are selected.
+func FilterOr(predicates ...func(string) bool) func(string) bool {
+	return func(s string) bool {
+		for _, predicate := range predicates {
+			if predicate == nil || predicate(s) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// FilterNot returns a predicate function that selects strings rejected by
+// the given predicate function.
+//
+// If predicate is nil, no strings are selected.
+func FilterNot(predicate func(string) bool) func(string) bool {
+	return func(s string) bool {
+		return predicate != nil && !predicate(s)
+	}
+}
+
+// WithFilterAnd is like [WithFilter] but combines predicate with the
+// predicate function already set, using [FilterAnd].
+// Strings are selected only if selected by both.
+//
+// Unlike [WithFilter], which replaces any predicate function already set,
+// WithFilterAnd may be applied multiple times to narrow the selection.
+// Predicate functions set with [WithFilterErr] or [WithFilterContext] are
+// combined as if by their [Config.Predicate] equivalent, and predicate
+// functions set with [WithBatchFilter] are replaced.
+// If no predicate function is set, WithFilterAnd is equivalent to
+// [WithFilter].
+func WithFilterAnd(predicate func(string) bool) Option[Config] {
+	return func(config Config) Config {
+		if predicate == nil {
+			// A nil predicate function selects all strings.
+			return config
+		}
+
+		if config.predicate != nil {
+			predicate = FilterAnd(config.predicate, predicate)
+		}
+
+		return WithFilter(predicate)(config)
+	}
+}
+
+// WithFilterOr is like [WithFilter] but combines predicate with the
+// predicate function already set, using [FilterOr].
+// Strings are selected if selected by either.
+//
+// Unlike [WithFilter], which replaces any predicate function already set,
+// WithFilterOr may be applied multiple times to widen the selection.
+// Predicate functions set with [WithFilterErr] or [WithFilterContext] are
+// combined as if by their [Config.Predicate] equivalent, and predicate
+// functions set with [WithBatchFilter] are replaced.
+// If no predicate function is set, WithFilterOr is equivalent to
+// [WithFilter].
+func WithFilterOr(predicate func(string) bool) Option[Config] {
+	return func(config Config) Config {
+		if config.predicate != nil && predicate != nil {
+			predicate = FilterOr(config.predicate, predicate)
+		}
+
+		return WithFilter(predicate)(config)
+	}
+}
@@ -0,0 +1,134 @@
+package mung
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFilterCombinators(t *testing.T) {
+	hasA := func(s string) bool { return strings.Contains(s, "a") }
+	hasB := func(s string) bool { return strings.Contains(s, "b") }
+
+	tests := []struct {
+		name      string
+		predicate func(string) bool
+		want      []bool // results for "", "a", "b", "ab"
+	}{
+		{"and_empty", FilterAnd(), []bool{true, true, true, true}},
+		{"and_nil", FilterAnd(nil, hasA), []bool{false, true, false, true}},
+		{"and", FilterAnd(hasA, hasB), []bool{false, false, false, true}},
+		{"or_empty", FilterOr(), []bool{false, false, false, false}},
+		{"or_nil", FilterOr(nil, hasA), []bool{true, true, true, true}},
+		{"or", FilterOr(hasA, hasB), []bool{false, true, true, true}},
+		{"not_nil", FilterNot(nil), []bool{false, false, false, false}},
+		{"not", FilterNot(hasA), []bool{true, false, true, false}},
+		{
+			"nested",
+			FilterAnd(hasA, FilterNot(hasB)),
+			[]bool{false, true, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, s := range []string{"", "a", "b", "ab"} {
+				if got := tt.predicate(s); got != tt.want[i] {
+					t.Errorf("predicate(%q) = %v, want %v", s, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterAndShortCircuit(t *testing.T) {
+	var calls int
+	reject := func(string) bool { calls++; return false }
+
+	FilterAnd(reject, reject)("x")
+	if calls != 1 {
+		t.Errorf("FilterAnd() called %d predicates, want 1", calls)
+	}
+
+	calls = 0
+	FilterOr(FilterNot(reject), reject)("x")
+	if calls != 1 {
+		t.Errorf("FilterOr() called %d predicates, want 1", calls)
+	}
+}
+
+func TestWithFilterCombine(t *testing.T) {
+	hasA := func(s string) bool { return strings.Contains(s, "a") }
+	hasB := func(s string) bool { return strings.Contains(s, "b") }
+	errA := func(s string) (bool, error) { return hasA(s), nil }
+
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want []string
+	}{
+		{
+			name: "filter_replaces",
+			opts: []Option[Config]{WithFilter(hasA), WithFilter(hasB)},
+			want: []string{"b", "ab"},
+		},
+		{
+			name: "and",
+			opts: []Option[Config]{WithFilter(hasA), WithFilterAnd(hasB)},
+			want: []string{"ab"},
+		},
+		{
+			name: "or",
+			opts: []Option[Config]{WithFilter(hasA), WithFilterOr(hasB)},
+			want: []string{"a", "b", "ab"},
+		},
+		{
+			name: "and_unset",
+			opts: []Option[Config]{WithFilterAnd(hasB)},
+			want: []string{"b", "ab"},
+		},
+		{
+			name: "or_unset",
+			opts: []Option[Config]{WithFilterOr(hasB)},
+			want: []string{"b", "ab"},
+		},
+		{
+			name: "and_nil",
+			opts: []Option[Config]{WithFilter(hasA), WithFilterAnd(nil)},
+			want: []string{"a", "ab"},
+		},
+		{
+			name: "or_nil",
+			opts: []Option[Config]{WithFilter(hasA), WithFilterOr(nil)},
+			want: []string{"a", "b", "ab", "c"},
+		},
+		{
+			name: "and_err",
+			opts: []Option[Config]{WithFilterErr(errA), WithFilterAnd(hasB)},
+			want: []string{"ab"},
+		},
+		{
+			name: "chained",
+			opts: []Option[Config]{
+				WithFilter(hasA),
+				WithFilterOr(hasB),
+				WithFilterAnd(FilterNot(FilterAnd(hasA, hasB))),
+			},
+			want: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:ab:c"}),
+				WithDelim(":"),
+			}, tt.opts...)
+
+			got := slices.Collect(Make(opts...).Filtered())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Filtered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Package flagutil provides [flag.Value] implementations for the options of
+// package mung, so that command-line tools embedding mung can accept the
+// same flags as the mung command without reimplementing them.
+//
+// The values also implement the Value interface of package
+// github.com/spf13/pflag. See package pflagutil to register them with a
+// pflag or cobra flag set.
+//
+// Each value has an Option method that returns the option it represents:
+//
+//	delim := flagutil.Delim(":")
+//	var remove flagutil.Remove
+//
+//	flag.Var(&delim, "d", "item delimiter")
+//	flag.Var(&remove, "r", "items to remove")
+//	flag.Parse()
+//
+//	config := mung.Make(
+//		mung.WithSubject(flag.Args()),
+//		delim.Option(),
+//		remove.Option(),
+//	)
+//
+// See [Flags] to register all of them at once.
+package flagutil
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ardnew/mung"
+)
+
+// errUninitialized is returned when setting a nil flag value.
+var errUninitialized = errors.New("uninitialized flag")
+
+// ErrReplaceSyntax is returned when setting a [Replace] value that is not of
+// the form "from=to".
+var ErrReplaceSyntax = errors.New("replacement must be of the form from=to")
+
+// Delim is a [flag.Value] holding the delimiter of items.
+// Its initial value is the default delimiter.
+type Delim string
+
+// Set sets the delimiter, unless value is blank.
+func (d *Delim) Set(value string) error {
+	if d == nil {
+		return errUninitialized
+	}
+
+	if strings.TrimSpace(value) != "" {
+		*d = Delim(value)
+	}
+
+	return nil
+}
+
+// String returns the delimiter.
+func (d *Delim) String() string {
+	if d == nil {
+		return ""
+	}
+
+	return string(*d)
+}
+
+// Type returns the name of the value's type, as required by pflag.
+func (*Delim) Type() string { return "string" }
+
+// Option returns an option that sets the delimiter, as if by [mung.WithDelim].
+func (d Delim) Option() mung.Option[mung.Config] {
+	return mung.WithDelim(string(d))
+}
+
+// Remove is a [flag.Value] holding the items to remove.
+// Each occurrence of the flag appends an item, and blank items are ignored.
+type Remove []string
+
+// Set appends an item to remove.
+func (r *Remove) Set(value string) error {
+	return appendItem((*[]string)(r), value)
+}
+
+// String returns the items to remove.
+func (r *Remove) String() string {
+	return formatItems((*[]string)(r))
+}
+
+// Type returns the name of the value's type, as required by pflag.
+func (*Remove) Type() string { return "stringArray" }
+
+// Option returns an option that removes the items, as if by
+// [mung.WithRemoveItems].
+func (r Remove) Option() mung.Option[mung.Config] {
+	return mung.WithRemoveItems(r...)
+}
+
+// Prefix is a [flag.Value] holding the items to prefix the subject.
+// Each occurrence of the flag appends an item, and blank items are ignored.
+type Prefix []string
+
+// Set appends an item to prefix.
+func (p *Prefix) Set(value string) error {
+	return appendItem((*[]string)(p), value)
+}
+
+// String returns the items to prefix.
+func (p *Prefix) String() string {
+	return formatItems((*[]string)(p))
+}
+
+// Type returns the name of the value's type, as required by pflag.
+func (*Prefix) Type() string { return "stringArray" }
+
+// Option returns an option that prefixes the items, as if by
+// [mung.WithPrefixItems].
+func (p Prefix) Option() mung.Option[mung.Config] {
+	return mung.WithPrefixItems(p...)
+}
+
+// Suffix is a [flag.Value] holding the items to suffix the subject.
+// Each occurrence of the flag appends an item, and blank items are ignored.
+type Suffix []string
+
+// Set appends an item to suffix.
+func (s *Suffix) Set(value string) error {
+	return appendItem((*[]string)(s), value)
+}
+
+// String returns the items to suffix.
+func (s *Suffix) String() string {
+	return formatItems((*[]string)(s))
+}
+
+// Type returns the name of the value's type, as required by pflag.
+func (*Suffix) Type() string { return "stringArray" }
+
+// Option returns an option that suffixes the items, as if by
+// [mung.WithSuffixItems].
+func (s Suffix) Option() mung.Option[mung.Config] {
+	return mung.WithSuffixItems(s...)
+}
+
+// Replace is a [flag.Value] holding replacement rules.
+// Each occurrence of the flag adds a rule of the form "from=to", split at
+// the first "=".
+type Replace map[string]string
+
+// Set adds a replacement rule.
+func (r *Replace) Set(value string) error {
+	if r == nil {
+		return errUninitialized
+	}
+
+	from, to, ok := strings.Cut(value, "=")
+	if !ok || from == "" {
+		return fmt.Errorf("%w: %q", ErrReplaceSyntax, value)
+	}
+
+	if *r == nil {
+		*r = Replace{}
+	}
+
+	(*r)[from] = to
+
+	return nil
+}
+
+// String returns the replacement rules.
+func (r *Replace) String() string {
+	if r == nil || len(*r) == 0 {
+		return ""
+	}
+
+	return fmt.Sprint(map[string]string(*r))
+}
+
+// Type returns the name of the value's type, as required by pflag.
+func (*Replace) Type() string { return "stringArray" }
+
+// Option returns an option that adds the replacement rules, as if by
+// [mung.WithReplaceItems].
+func (r Replace) Option() mung.Option[mung.Config] {
+	return mung.WithReplaceItems(r)
+}
+
+// Flags holds a value for each of the standard flags of the mung command.
+type Flags struct {
+	Delim   Delim
+	Remove  Remove
+	Prefix  Prefix
+	Suffix  Suffix
+	Replace Replace
+}
+
+// Register defines the standard flags of the mung command in fs, using the
+// receiver's values as defaults:
+//
+//	-d delim   item delimiter
+//	-r item    items to remove
+//	-p item    items to prefix subject(s)
+//	-s item    items to suffix subject(s)
+//	-R from=to items to replace
+func (f *Flags) Register(fs *flag.FlagSet) {
+	fs.Var(&f.Delim, "d", "item delimiter")
+	fs.Var(&f.Remove, "r", "items to remove")
+	fs.Var(&f.Prefix, "p", "items to prefix subject(s)")
+	fs.Var(&f.Suffix, "s", "items to suffix subject(s)")
+	fs.Var(&f.Replace, "R", "items to replace (from=to)")
+}
+
+// Options returns the options represented by the receiver's values.
+// The delimiter is omitted if it is empty, so that it does not replace a
+// delimiter set by other options.
+func (f *Flags) Options() []mung.Option[mung.Config] {
+	opts := []mung.Option[mung.Config]{
+		f.Remove.Option(),
+		f.Prefix.Option(),
+		f.Suffix.Option(),
+		f.Replace.Option(),
+	}
+
+	if f.Delim != "" {
+		opts = append([]mung.Option[mung.Config]{f.Delim.Option()}, opts...)
+	}
+
+	return opts
+}
+
+// appendItem appends value to the items at p, unless value is blank.
+func appendItem(p *[]string, value string) error {
+	if p == nil {
+		return errUninitialized
+	}
+
+	if strings.TrimSpace(value) != "" {
+		*p = append(*p, value)
+	}
+
+	return nil
+}
+
+// formatItems returns the items at p formatted as a list.
+func formatItems(p *[]string) string {
+	if p == nil || len(*p) == 0 {
+		return ""
+	}
+
+	return fmt.Sprint(*p)
+}
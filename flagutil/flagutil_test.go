@@ -0,0 +1,124 @@
+package flagutil
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/ardnew/mung"
+)
+
+func TestFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "defaults",
+			want: "/usr/bin:/bin:/old/bin",
+		},
+		{
+			name: "all",
+			args: []string{
+				"-p", "/usr/local/bin", "-s", "/opt/bin", "-s", " ",
+				"-r", "/old/bin", "-R", "/bin=/sbin", "-R", "/x=/y=z",
+			},
+			want: "/usr/local/bin:/usr/bin:/sbin:/opt/bin",
+		},
+		{
+			name: "delim",
+			args: []string{"-d", ";", "-p", "/a"},
+			want: "/a;/usr/bin:/bin:/old/bin",
+		},
+		{
+			name: "blank_delim",
+			args: []string{"-d", " ", "-p", "/a"},
+			want: "/a:/usr/bin:/bin:/old/bin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := Flags{Delim: ":"}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags.Register(fs)
+
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			opts := append([]mung.Option[mung.Config]{
+				mung.WithSubject([]string{"/usr/bin:/bin:/old/bin"}),
+			}, flags.Options()...)
+
+			if got := mung.Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagsEmptyDelim(t *testing.T) {
+	var flags Flags
+
+	opts := append([]mung.Option[mung.Config]{
+		mung.WithSubject([]string{"a,b"}),
+		mung.WithDelim(","),
+	}, flags.Options()...)
+
+	if got := mung.Make(opts...).Delim(); got != "," {
+		t.Errorf("Delim() = %q, want %q", got, ",")
+	}
+}
+
+func TestReplaceSyntax(t *testing.T) {
+	var replace Replace
+
+	for _, value := range []string{"noequals", "=to"} {
+		if err := replace.Set(value); !errors.Is(err, ErrReplaceSyntax) {
+			t.Errorf("Set(%q) error = %v, want %v", value, err, ErrReplaceSyntax)
+		}
+	}
+
+	if replace != nil {
+		t.Errorf("Set() = %v, want nil", replace)
+	}
+}
+
+func TestString(t *testing.T) {
+	delim := Delim(":")
+	remove := Remove{"a", "b"}
+	replace := Replace{"b": "2", "a": "1"}
+
+	for _, tt := range []struct {
+		value flag.Value
+		want  string
+	}{
+		{&delim, ":"},
+		{&remove, "[a b]"},
+		{&replace, "map[a:1 b:2]"},
+		{new(Prefix), ""},
+		{new(Suffix), ""},
+		{new(Replace), ""},
+	} {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("%T.String() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNilReceivers(t *testing.T) {
+	for _, value := range []flag.Value{
+		(*Delim)(nil), (*Remove)(nil), (*Prefix)(nil), (*Suffix)(nil),
+		(*Replace)(nil),
+	} {
+		if got := value.String(); got != "" {
+			t.Errorf("nil %T.String() = %q, want empty", value, got)
+		}
+
+		if err := value.Set("a=b"); err == nil {
+			t.Errorf("nil %T.Set() error = nil, want error", value)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/ardnew/mung/flagutil/pflagutil
+
+go 1.24.2
+
+require (
+	github.com/ardnew/mung v0.0.0-00010101000000-000000000000
+	github.com/spf13/pflag v1.0.9
+)
+
+require golang.org/x/text v0.34.0 // indirect
+
+replace github.com/ardnew/mung => ../..
@@ -0,0 +1,55 @@
+// Package pflagutil registers the standard flags of the mung command with a
+// [pflag.FlagSet], so that tools built on pflag or cobra can embed path
+// munging, e.g., as a subcommand, with the same command-line interface.
+//
+// With cobra, register the flags on the command's flag set and build the
+// configuration when the command runs:
+//
+//	var flags flagutil.Flags
+//
+//	cmd := &cobra.Command{
+//		Use: "path [subjects]",
+//		Run: func(cmd *cobra.Command, args []string) {
+//			opts := append(flags.Options(), mung.WithSubject(args))
+//			fmt.Println(mung.Make(opts...))
+//		},
+//	}
+//	pflagutil.Register(cmd.Flags(), &flags)
+package pflagutil
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/ardnew/mung/flagutil"
+)
+
+// Register defines the standard flags of the mung command in fs, using the
+// values of flags as defaults:
+//
+//	-d, --delim string          item delimiter
+//	-r, --remove stringArray    items to remove
+//	-p, --prefix stringArray    items to prefix subject(s)
+//	-s, --suffix stringArray    items to suffix subject(s)
+//	-R, --replace stringArray   items to replace (from=to)
+//
+// The options represented by the parsed flags are returned by
+// [flagutil.Flags.Options].
+func Register(fs *pflag.FlagSet, flags *flagutil.Flags) {
+	fs.VarP(&flags.Delim, "delim", "d", "item delimiter")
+	fs.VarP(&flags.Remove, "remove", "r", "items to remove")
+	fs.VarP(&flags.Prefix, "prefix", "p", "items to prefix subject(s)")
+	fs.VarP(&flags.Suffix, "suffix", "s", "items to suffix subject(s)")
+	fs.VarP(&flags.Replace, "replace", "R", "items to replace (from=to)")
+}
+
+// NewFlagSet returns a new [pflag.FlagSet] with the given name and error
+// handling policy, defining the standard flags of the mung command as if by
+// [Register], e.g., to add to a cobra command with AddFlagSet.
+func NewFlagSet(
+	name string, handling pflag.ErrorHandling, flags *flagutil.Flags,
+) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, handling)
+	Register(fs, flags)
+
+	return fs
+}
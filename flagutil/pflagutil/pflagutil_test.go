@@ -0,0 +1,81 @@
+package pflagutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ardnew/mung"
+	"github.com/ardnew/mung/flagutil"
+)
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "defaults",
+			want: "/usr/bin:/bin:/old/bin",
+		},
+		{
+			name: "short",
+			args: []string{
+				"-p", "/usr/local/bin", "-s", "/opt/bin", "-r", "/old/bin",
+				"-R", "/bin=/sbin",
+			},
+			want: "/usr/local/bin:/usr/bin:/sbin:/opt/bin",
+		},
+		{
+			name: "long",
+			args: []string{
+				"--delim=;", "--prefix", "/a", "--suffix=/b", "--remove", "/c",
+				"--replace", "/usr/bin:/bin:/old/bin=/d",
+			},
+			want: "/a;/d;/b",
+		},
+		{
+			name: "repeated",
+			args: []string{"-p", "/a,/b", "-p", "/c"},
+			want: "/c:/a,/b:/usr/bin:/bin:/old/bin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := flagutil.Flags{Delim: ":"}
+
+			fs := NewFlagSet("test", pflag.ContinueOnError, &flags)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			opts := append([]mung.Option[mung.Config]{
+				mung.WithSubject([]string{"/usr/bin:/bin:/old/bin"}),
+			}, flags.Options()...)
+
+			if got := mung.Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterUsage(t *testing.T) {
+	var flags flagutil.Flags
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	Register(fs, &flags)
+
+	usage := fs.FlagUsages()
+	for _, want := range []string{
+		"-d, --delim string", "-r, --remove stringArray",
+		"-R, --replace stringArray",
+	} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("FlagUsages() = %q, want contains %q", usage, want)
+		}
+	}
+}
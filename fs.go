@@ -0,0 +1,461 @@
+package mung
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// symlinks identifies how symbolic links in items are resolved.
+type symlinks int
+
+const (
+	// symlinksNone does not resolve symbolic links (default).
+	symlinksNone symlinks = iota
+	// symlinksCompare resolves symbolic links only for comparison.
+	symlinksCompare
+	// symlinksRewrite resolves symbolic links for comparison and output.
+	symlinksRewrite
+)
+
+// WithResolveSymlinks returns an option that compares items by the path
+// they refer to after evaluating any symbolic links.
+//
+// For example, "/bin" and "/usr/bin" are considered duplicates on systems
+// where "/bin" is a symbolic link to "/usr/bin".
+// The original spelling of each yielded item is preserved.
+// Use [WithRewriteSymlinks] to yield the resolved paths instead.
+//
+// Items that cannot be resolved (e.g., nonexistent paths) are compared as-is.
+// Each distinct item is resolved at most once per realization of the munged
+// sequence.
+func WithResolveSymlinks() Option[Config] {
+	return func(config Config) Config {
+		config.links, config.abs = symlinksCompare, false
+
+		return config
+	}
+}
+
+// WithRewriteSymlinks returns an option that replaces every item with the
+// path it refers to after evaluating any symbolic links.
+//
+// This is like [WithResolveSymlinks], except the resolved path of each item
+// is yielded instead of its original spelling.
+func WithRewriteSymlinks() Option[Config] {
+	return func(config Config) Config {
+		config.links, config.abs = symlinksRewrite, false
+
+		return config
+	}
+}
+
+// WithRealpath returns an option that compares items by their real path: the
+// absolute path they refer to after evaluating any symbolic links, e.g., to
+// eliminate duplicates such as "bin", "./bin", and "$PWD/bin" without
+// rewriting the spelling of any item.
+//
+// This is like [WithResolveSymlinks], except relative items are made
+// absolute with [filepath.Abs], and items that cannot be resolved are
+// compared by their absolute path. Under [WithFS], items are made absolute
+// relative to the root of the file system instead.
+// The original spelling of the first occurrence of each item is preserved,
+// or of the last under [WithDedupeKeepLast].
+// Use [WithRewriteRealpath] to yield the real paths instead.
+func WithRealpath() Option[Config] {
+	return func(config Config) Config {
+		config.links, config.abs = symlinksCompare, true
+
+		return config
+	}
+}
+
+// WithRewriteRealpath returns an option that replaces every item with its
+// real path, as described by [WithRealpath].
+func WithRewriteRealpath() Option[Config] {
+	return func(config Config) Config {
+		config.links, config.abs = symlinksRewrite, true
+
+		return config
+	}
+}
+
+// existence identifies the file type an item must refer to
+// in order to be selected.
+type existence int
+
+const (
+	// existAny selects items regardless of what they refer to (default).
+	existAny existence = iota
+	// existFile selects items that refer to an existing file of any type.
+	existFile
+	// existDir selects items that refer to an existing directory.
+	existDir
+	// existExec selects items that refer to an existing file with any of its
+	// execute permission bits set.
+	existExec
+)
+
+// WithExistingOnly returns an option that selects only the items that refer
+// to an existing file or directory.
+//
+// Like the predicate function of [WithFilter], the option affects only the
+// sequences [Config.Filtered] and [Config.String].
+// Each distinct item is stat'ed at most once per realization of the munged
+// sequence. Symbolic links are followed.
+func WithExistingOnly() Option[Config] {
+	return func(config Config) Config {
+		config.exist = existFile
+
+		return config
+	}
+}
+
+// WithExistingDirsOnly is like [WithExistingOnly] but selects only the items
+// that refer to an existing directory.
+func WithExistingDirsOnly() Option[Config] {
+	return func(config Config) Config {
+		config.exist = existDir
+
+		return config
+	}
+}
+
+// WithExecutableOnly is like [WithExistingOnly] but selects only the items
+// that refer to an existing file with any of its execute permission bits set.
+//
+// For directories, the execute permission grants search access.
+func WithExecutableOnly() Option[Config] {
+	return func(config Config) Config {
+		config.exist = existExec
+
+		return config
+	}
+}
+
+// exists reports whether item s refers to a file of the type required by the
+// receiver's existence filter, if any.
+func (c Config) exists(s string) bool {
+	if c.exist == existAny {
+		return true
+	}
+
+	info, err := c.statItem(s)
+	if err != nil {
+		return false
+	}
+
+	switch c.exist {
+	case existDir:
+		return info.IsDir()
+	case existExec:
+		return info.Mode().Perm()&0o111 != 0
+	default:
+		return true
+	}
+}
+
+// statItem returns the [fs.FileInfo] describing the file item s refers to.
+func (c Config) statItem(s string) (fs.FileInfo, error) {
+	if c.stat == nil {
+		return c.statFunc()(s)
+	}
+
+	return c.stat(s)
+}
+
+// statFunc returns the function used to stat items.
+func (c Config) statFunc() func(string) (fs.FileInfo, error) {
+	if c.statFn == nil {
+		return os.Stat
+	}
+
+	return c.statFn
+}
+
+// evalFunc returns the function used to evaluate symbolic links in items.
+func (c Config) evalFunc() func(string) (string, error) {
+	if c.evalFn == nil {
+		return filepath.EvalSymlinks
+	}
+
+	return c.evalFn
+}
+
+// realpathFunc returns the function used to resolve items to real paths.
+// See [WithRealpath].
+func (c Config) realpathFunc() func(string) (string, error) {
+	eval := c.evalFunc()
+
+	if c.evalFn != nil {
+		// Items are rooted at the root of the file system set with WithFS.
+		return func(name string) (string, error) {
+			name = path.Clean("/" + fsPath(name))
+			if resolved, err := eval(name); err == nil {
+				return resolved, nil
+			}
+
+			return name, nil
+		}
+	}
+
+	return func(name string) (string, error) {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return "", err
+		}
+
+		if resolved, err := eval(abs); err == nil {
+			return resolved, nil
+		}
+
+		return abs, nil
+	}
+}
+
+// WithFS returns an option that routes the filesystem queries made by other
+// options, e.g., [WithExistingOnly] and [WithResolveSymlinks], to fsys
+// instead of the host operating system.
+//
+// Items are interpreted as slash-separated paths rooted at fsys,
+// so "/usr/bin" and "usr/bin" both refer to "usr/bin" in fsys.
+//
+// Symbolic links with absolute targets are resolved relative to the root of
+// fsys. Symbolic links are evaluated only if fsys implements the methods
+//
+//	ReadLink(name string) (string, error)
+//	Lstat(name string) (fs.FileInfo, error)
+//
+// Otherwise, items are compared as-is by [WithResolveSymlinks].
+//
+// Note that [os.SameFile] only recognizes file info returned by package os,
+// so with [WithSameFile], items refer to the same file only if they have
+// equal keys.
+func WithFS(fsys fs.FS) Option[Config] {
+	return func(config Config) Config {
+		config.statFn = func(name string) (fs.FileInfo, error) {
+			// Evaluate links first so that absolute targets are resolved
+			// relative to the root of fsys.
+			if resolved, err := evalSymlinksFS(fsys, name); err == nil {
+				name = resolved
+			}
+
+			return fs.Stat(fsys, fsPath(name))
+		}
+		config.evalFn = func(name string) (string, error) {
+			return evalSymlinksFS(fsys, name)
+		}
+		config.readDir = func(name string) ([]fs.DirEntry, error) {
+			if resolved, err := evalSymlinksFS(fsys, name); err == nil {
+				name = resolved
+			}
+
+			return fs.ReadDir(fsys, fsPath(name))
+		}
+
+		return config
+	}
+}
+
+// WithStatFunc returns an option that routes the stat queries made by other
+// options, e.g., [WithExistingOnly] and [WithSameFile], to the given function
+// instead of [os.Stat].
+//
+// This is a lower-level alternative to [WithFS] for backing existence checks
+// with remote file systems, caches, or container image layers.
+// The stat function receives each item unmodified and must follow symbolic
+// links like [os.Stat]. It does not affect [WithResolveSymlinks].
+//
+// A nil stat function restores the default.
+func WithStatFunc(stat func(name string) (fs.FileInfo, error)) Option[Config] {
+	return func(config Config) Config {
+		config.statFn = stat
+
+		return config
+	}
+}
+
+// readLinkFS is a file system that supports reading symbolic links.
+type readLinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// maxSymlinks is the maximum number of symbolic links evaluated while
+// resolving a single path.
+const maxSymlinks = 255
+
+var errTooManyLinks = errors.New("too many symbolic links")
+
+// fsPath returns the name of the given item within an [fs.FS].
+func fsPath(name string) string {
+	name = strings.TrimLeft(path.Clean(filepath.ToSlash(name)), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+// evalSymlinksFS is like [filepath.EvalSymlinks] but evaluates the links
+// within fsys, which must implement [readLinkFS].
+//
+// Links with absolute targets are resolved relative to the root of fsys.
+// The result is absolute (has a leading slash) if and only if name is.
+func evalSymlinksFS(fsys fs.FS, name string) (string, error) {
+	rfs, ok := fsys.(readLinkFS)
+	if !ok {
+		return "", errors.ErrUnsupported
+	}
+
+	var (
+		done  string // resolved leading elements
+		rest  = fsPath(name)
+		links int
+	)
+
+	for rest != "." && rest != "" {
+		var elem string
+
+		elem, rest, _ = strings.Cut(rest, "/")
+		next := path.Join(done, elem)
+
+		info, err := rfs.Lstat(next)
+		if err != nil {
+			return "", err
+		}
+
+		if info.Mode()&fs.ModeSymlink == 0 {
+			done = next
+
+			continue
+		}
+
+		if links++; links > maxSymlinks {
+			return "", &fs.PathError{Op: "readlink", Path: name, Err: errTooManyLinks}
+		}
+
+		target, err := rfs.ReadLink(next)
+		if err != nil {
+			return "", err
+		}
+
+		if !path.IsAbs(target) {
+			target = path.Join(done, target)
+		}
+
+		// Restart from the root with the link replaced by its target.
+		// Cleaning a rooted path prevents ".." from escaping the root.
+		rest = fsPath(path.Clean("/" + path.Join(target, rest)))
+		done = ""
+	}
+
+	if done == "" {
+		done = "."
+	}
+
+	if strings.HasPrefix(filepath.ToSlash(name), "/") {
+		return path.Clean("/" + done), nil
+	}
+
+	return done, nil
+}
+
+// WithSameFile returns an option that compares items by the file they refer
+// to, as reported by [os.SameFile].
+//
+// This identifies duplicates that string comparison and symbolic link
+// resolution cannot, such as bind mounts and hard-linked directories.
+// Items that do not refer to the same file (e.g., nonexistent paths) are
+// compared using the other comparison rules, e.g., [WithCaseFold] and
+// [WithEqual].
+//
+// Since file identity cannot be hashed portably, each comparison is made by
+// linear search. Each distinct item is stat'ed at most once per realization
+// of the munged sequence.
+func WithSameFile() Option[Config] {
+	return func(config Config) Config {
+		config.sameFile = true
+
+		return config
+	}
+}
+
+// newStatCache returns a function that calls stat with the given path,
+// caching the result for subsequent calls.
+//
+// If limit is positive, the cache holds at most limit paths and forgets every
+// path once it is full.
+// The returned function is safe for concurrent use.
+func newStatCache(
+	stat func(string) (fs.FileInfo, error), limit int,
+) func(string) (fs.FileInfo, error) {
+	type result struct {
+		info fs.FileInfo
+		err  error
+	}
+
+	var mu sync.Mutex
+
+	cache := map[string]result{}
+
+	return func(path string) (fs.FileInfo, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r, ok := cache[path]; ok {
+			return r.info, r.err
+		}
+
+		if limit > 0 && len(cache) >= limit {
+			clear(cache)
+		}
+
+		info, err := stat(path)
+		cache[path] = result{info, err}
+
+		return info, err
+	}
+}
+
+// newSymlinkResolver returns a function that evaluates symbolic links in the
+// given path using eval, caching the result for subsequent calls.
+//
+// If the path cannot be resolved, the function returns the path unmodified.
+// If limit is positive, the cache holds at most limit paths and forgets every
+// path once it is full.
+// The returned function is safe for concurrent use.
+func newSymlinkResolver(
+	eval func(string) (string, error), limit int,
+) func(string) string {
+	var mu sync.Mutex
+
+	cache := map[string]string{}
+
+	return func(name string) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if resolved, ok := cache[name]; ok {
+			return resolved
+		}
+
+		if limit > 0 && len(cache) >= limit {
+			clear(cache)
+		}
+
+		resolved, err := eval(name)
+		if err != nil {
+			resolved = name
+		}
+
+		cache[name] = resolved
+
+		return resolved
+	}
+}
@@ -0,0 +1,497 @@
+package mung
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// symlinkTree creates a temporary directory containing a directory "usr/bin"
+// and a symbolic link "bin" referring to it, returning the directory's path.
+func symlinkTree(t *testing.T) string {
+	t.Helper()
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "usr", "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("usr", "bin"), filepath.Join(root, "bin")); err != nil {
+		t.Skipf("symbolic links unsupported: %v", err)
+	}
+	return root
+}
+
+func TestWithResolveSymlinks(t *testing.T) {
+	root := symlinkTree(t)
+	bin := filepath.Join(root, "bin")
+	usrBin := filepath.Join(root, "usr", "bin")
+	missing := filepath.Join(root, "missing")
+
+	tests := []struct {
+		name    string
+		initial Config
+		opt     Option[Config]
+		want    []string
+	}{
+		{
+			name:    "compare_keeps_original",
+			initial: Config{subject: []string{bin, usrBin, missing, missing}},
+			opt:     WithResolveSymlinks(),
+			want:    []string{bin, missing},
+		},
+		{
+			name: "compare_remove",
+			initial: Config{
+				subject: []string{bin, missing},
+				remove:  []string{usrBin},
+			},
+			opt:  WithResolveSymlinks(),
+			want: []string{missing},
+		},
+		{
+			name:    "rewrite_outputs_resolved",
+			initial: Config{subject: []string{bin, usrBin, missing}},
+			opt:     WithRewriteSymlinks(),
+			want:    []string{usrBin, missing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(string(os.PathListSeparator)), tt.opt)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRealpath(t *testing.T) {
+	root := symlinkTree(t)
+	usrBin := filepath.Join(root, "usr", "bin")
+	missing := filepath.Join(root, "missing")
+
+	t.Chdir(root)
+
+	dot := "." + string(filepath.Separator)
+	subject := []string{
+		"bin", dot + filepath.Join("usr", "bin"), usrBin,
+		"missing", dot + "missing", missing,
+	}
+
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want []string
+	}{
+		{
+			name: "compare_keeps_original",
+			opts: []Option[Config]{WithRealpath()},
+			want: []string{"bin", "missing"},
+		},
+		{
+			name: "compare_keep_last",
+			opts: []Option[Config]{WithRealpath(), WithDedupeKeepLast()},
+			want: []string{usrBin, missing},
+		},
+		{
+			name: "compare_remove",
+			opts: []Option[Config]{WithRealpath(), WithRemoveItems(usrBin)},
+			want: []string{"missing"},
+		},
+		{
+			name: "rewrite_outputs_resolved",
+			opts: []Option[Config]{WithRewriteRealpath()},
+			want: []string{usrBin, missing},
+		},
+		{
+			name: "symlinks_not_absolute",
+			opts: []Option[Config]{WithRealpath(), WithResolveSymlinks()},
+			want: []string{"bin", usrBin, "missing", dot + "missing", missing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject(subject), WithDelim(string(os.PathListSeparator)),
+			}, tt.opts...)
+			got := slices.Collect(Make(opts...).All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRealpathFS(t *testing.T) {
+	fsys := symlinkMapFS(t)
+
+	config := Make(
+		WithSubject([]string{"bin", "/usr/bin", "usr/local/bin", "/opt/bin", "missing", "/missing"}),
+		WithDelim(":"),
+		WithFS(fsys),
+	)
+
+	if got, want := slices.Collect(Wrap(config, WithRealpath()).All()),
+		[]string{"bin", "usr/local/bin", "missing"}; !slicesEqual(got, want) {
+		t.Errorf("WithRealpath() All() = %v, want %v", got, want)
+	}
+
+	if got, want := slices.Collect(Wrap(config, WithRewriteRealpath()).All()),
+		[]string{"/usr/bin", "/opt/bin", "/missing"}; !slicesEqual(got, want) {
+		t.Errorf("WithRewriteRealpath() All() = %v, want %v", got, want)
+	}
+}
+
+func TestNewSymlinkResolver(t *testing.T) {
+	root := symlinkTree(t)
+	bin := filepath.Join(root, "bin")
+	resolve := newSymlinkResolver(filepath.EvalSymlinks, 0)
+
+	want := filepath.Join(root, "usr", "bin")
+	if got := resolve(bin); got != want {
+		t.Errorf("resolve(%q) = %q, want %q", bin, got, want)
+	}
+
+	// Results are cached, so removing the link does not affect the result.
+	if err := os.Remove(bin); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolve(bin); got != want {
+		t.Errorf("resolve(%q) cached = %q, want %q", bin, got, want)
+	}
+
+	missing := filepath.Join(root, "missing")
+	if got := resolve(missing); got != missing {
+		t.Errorf("resolve(%q) = %q, want %q", missing, got, missing)
+	}
+}
+
+func TestWithSameFile(t *testing.T) {
+	root := symlinkTree(t)
+	bin := filepath.Join(root, "bin")
+	usrBin := filepath.Join(root, "usr", "bin")
+	missing := filepath.Join(root, "missing")
+	missingUpper := filepath.Join(root, "MISSING")
+
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+	}{
+		{
+			name:    "same_file_collapses",
+			initial: Config{subject: []string{usrBin, bin, missing, missing}},
+			want:    []string{usrBin, missing},
+		},
+		{
+			name: "same_file_remove",
+			initial: Config{
+				subject: []string{bin, missing},
+				remove:  []string{usrBin},
+			},
+			want: []string{missing},
+		},
+		{
+			name: "same_file_replace",
+			initial: Config{
+				subject: []string{bin, missing},
+				replace: map[string]string{usrBin: "/opt/bin"},
+			},
+			want: []string{"/opt/bin", missing},
+		},
+		{
+			name:    "fallback_to_comparison_rules",
+			initial: Config{subject: []string{missing, missingUpper}},
+			opts:    []Option[Config]{WithCaseFold()},
+			want:    []string{missing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(string(os.PathListSeparator)), WithSameFile()}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithSameFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStatCache(t *testing.T) {
+	calls := 0
+	stat := newStatCache(func(name string) (fs.FileInfo, error) {
+		calls++
+		return os.Stat(name)
+	}, 0)
+
+	dir := t.TempDir()
+	for range 3 {
+		if _, err := stat(dir); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stat(filepath.Join(dir, "missing")); err == nil {
+			t.Error("stat(missing) returned nil error")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("stat called %d times, want 2", calls)
+	}
+}
+
+func TestNewStatCacheLimit(t *testing.T) {
+	calls := 0
+	stat := newStatCache(func(name string) (fs.FileInfo, error) {
+		calls++
+		return nil, fs.ErrNotExist
+	}, 2)
+
+	for _, name := range []string{"a", "b", "a", "c", "a", "b"} {
+		_, _ = stat(name)
+	}
+
+	// "c" clears the cache holding "a" and "b", so "a" and "b" are queried
+	// again, and "b" clears the cache holding "c" and "a".
+	if calls != 5 {
+		t.Errorf("stat called %d times, want 5", calls)
+	}
+}
+
+func TestWithExistingOnly(t *testing.T) {
+	root := symlinkTree(t)
+	bin := filepath.Join(root, "bin")
+	usrBin := filepath.Join(root, "usr", "bin")
+	missing := filepath.Join(root, "missing")
+	file := filepath.Join(usrBin, "tool")
+	script := filepath.Join(usrBin, "script")
+
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	subject := []string{bin, usrBin, missing, file, script}
+
+	tests := []struct {
+		name string
+		opt  Option[Config]
+		want []string
+	}{
+		{"existing", WithExistingOnly(), []string{bin, usrBin, file, script}},
+		{"directories", WithExistingDirsOnly(), []string{bin, usrBin}},
+		{"executables", WithExecutableOnly(), []string{bin, usrBin, script}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(WithSubject(subject), WithDelim(string(os.PathListSeparator)), tt.opt)
+			got := slices.Collect(config.Filtered())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Filtered() = %v, want %v", got, tt.want)
+			}
+			// The unfiltered sequence is unaffected.
+			if all := slices.Collect(config.All()); !slicesEqual(all, subject) {
+				t.Errorf("All() = %v, want %v", all, subject)
+			}
+		})
+	}
+
+	t.Run("with_predicate", func(t *testing.T) {
+		config := Make(
+			WithSubject(subject),
+			WithDelim(string(os.PathListSeparator)),
+			WithExistingDirsOnly(),
+			WithFilter(func(s string) bool { return s != bin }),
+		)
+		got := slices.Collect(config.Filtered())
+		if want := []string{usrBin}; !slicesEqual(got, want) {
+			t.Errorf("Filtered() = %v, want %v", got, want)
+		}
+	})
+}
+
+// symlinkMapFS returns an in-memory file system with directories "usr/bin" and
+// "opt/bin", a file "usr/bin/tool", and symbolic links "bin" -> "usr/bin",
+// "sbin" -> "/bin", "loop" -> "loop", and "usr/local" -> "../opt".
+func symlinkMapFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+
+	fsys := fstest.MapFS{
+		"usr/bin":      {Mode: fs.ModeDir | 0o755},
+		"usr/bin/tool": {Mode: 0o755},
+		"opt/bin":      {Mode: fs.ModeDir | 0o755},
+		"bin":          {Mode: fs.ModeSymlink, Data: []byte("usr/bin")},
+		"sbin":         {Mode: fs.ModeSymlink, Data: []byte("/bin")},
+		"loop":         {Mode: fs.ModeSymlink, Data: []byte("loop")},
+		"usr/local":    {Mode: fs.ModeSymlink, Data: []byte("../opt")},
+	}
+	if _, ok := any(fsys).(readLinkFS); !ok {
+		t.Skip("fstest.MapFS does not support symbolic links")
+	}
+	return fsys
+}
+
+func TestWithFS(t *testing.T) {
+	fsys := symlinkMapFS(t)
+
+	tests := []struct {
+		name   string
+		opts   []Option[Config]
+		filter bool
+		want   []string
+	}{
+		{
+			name:   "existing",
+			opts:   []Option[Config]{WithExistingOnly()},
+			filter: true,
+			want:   []string{"/usr/bin", "/bin", "/sbin", "/usr/local/bin", "/usr/bin/tool"},
+		},
+		{
+			name:   "directories",
+			opts:   []Option[Config]{WithExistingDirsOnly()},
+			filter: true,
+			want:   []string{"/usr/bin", "/bin", "/sbin", "/usr/local/bin"},
+		},
+		{
+			name: "resolve_symlinks",
+			opts: []Option[Config]{WithResolveSymlinks()},
+			want: []string{"/usr/bin", "/usr/local/bin", "/missing", "/usr/bin/tool"},
+		},
+		{
+			name: "rewrite_symlinks",
+			opts: []Option[Config]{WithRewriteSymlinks()},
+			want: []string{"/usr/bin", "/opt/bin", "/missing", "/usr/bin/tool"},
+		},
+	}
+
+	subject := []string{"/usr/bin", "/bin", "/sbin", "/usr/local/bin", "/missing", "/usr/bin/tool"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithSubject(subject), WithDelim(":"), WithFS(fsys)}, tt.opts...)
+			config := Make(opts...)
+			seq := config.All()
+			if tt.filter {
+				seq = config.Filtered()
+			}
+			got := slices.Collect(seq)
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithFS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported_symlinks", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("/bin", "/usr/bin"),
+			WithDelim(":"),
+			WithFS(struct{ fs.FS }{fsys}), // hide ReadLink and Lstat
+			WithResolveSymlinks(),
+		)
+		got := slices.Collect(config.All())
+		if want := []string{"/bin", "/usr/bin"}; !slicesEqual(got, want) {
+			t.Errorf("WithFS() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestEvalSymlinksFS(t *testing.T) {
+	fsys := symlinkMapFS(t)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "root", in: "/", want: "/"},
+		{name: "dot", in: ".", want: "."},
+		{name: "no_links", in: "/usr/bin/tool", want: "/usr/bin/tool"},
+		{name: "relative_link", in: "/bin/tool", want: "/usr/bin/tool"},
+		{name: "unrooted", in: "bin", want: "usr/bin"},
+		{name: "absolute_link", in: "/sbin/tool", want: "/usr/bin/tool"},
+		{name: "parent_link", in: "/usr/local/bin", want: "/opt/bin"},
+		{name: "missing", in: "/bin/missing", wantErr: true},
+		{name: "loop", in: "/loop", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalSymlinksFS(fsys, tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalSymlinksFS(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evalSymlinksFS(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := evalSymlinksFS(struct{ fs.FS }{fstest.MapFS{}}, "/bin")
+		if !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("evalSymlinksFS() error = %v, want %v", err, errors.ErrUnsupported)
+		}
+	})
+}
+
+func TestWithStatFunc(t *testing.T) {
+	dirs := map[string]bool{"/remote/bin": true, "/remote/lib": false}
+	var queried []string
+	stat := func(name string) (fs.FileInfo, error) {
+		queried = append(queried, name)
+		isDir, ok := dirs[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		mode := fs.FileMode(0o644)
+		if isDir {
+			mode = fs.ModeDir | 0o755
+		}
+		return fstest.MapFS{"f": {Mode: mode}}.Stat("f")
+	}
+
+	config := Make(
+		WithSubjectItems("/remote/bin", "/remote/lib", "/remote/missing", "/remote/bin"),
+		WithDelim(":"),
+		WithStatFunc(stat),
+		WithExistingDirsOnly(),
+	)
+
+	got := slices.Collect(config.Filtered())
+	if want := []string{"/remote/bin"}; !slicesEqual(got, want) {
+		t.Errorf("WithStatFunc() = %v, want %v", got, want)
+	}
+	if want := []string{"/remote/bin", "/remote/lib", "/remote/missing"}; !slicesEqual(queried, want) {
+		t.Errorf("WithStatFunc() queried = %v, want %v", queried, want)
+	}
+
+	t.Run("nil_restores_default", func(t *testing.T) {
+		dir := t.TempDir()
+		config := Make(
+			WithSubjectItems(dir, "/remote/bin"),
+			WithDelim(string(os.PathListSeparator)),
+			WithStatFunc(stat),
+			WithStatFunc(nil),
+			WithExistingOnly(),
+		)
+		got := slices.Collect(config.Filtered())
+		if want := []string{dir}; !slicesEqual(got, want) {
+			t.Errorf("WithStatFunc(nil) = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package mung
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Hash returns a fingerprint of the munged sequence of [Config.Filtered],
+// e.g., to cheaply detect whether a munged value changed since it was last
+// exported.
+//
+// The fingerprint is computed with 64-bit FNV-1a over the length and content
+// of each string in order, so it is stable across processes and does not
+// depend on the delimiters used to split or join items. Equal sequences
+// have equal fingerprints. To compare with the unmunged items of a value,
+// hash a configuration with the same subject and [WithAllowDuplicates].
+func (c Config) Hash() uint64 {
+	h := fnv.New64a()
+
+	var n [binary.MaxVarintLen64]byte
+
+	for s := range c.Filtered() {
+		_, _ = h.Write(n[:binary.PutUvarint(n[:], uint64(len(s)))])
+		_, _ = h.Write([]byte(s))
+	}
+
+	return h.Sum64()
+}
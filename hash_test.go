@@ -0,0 +1,38 @@
+package mung
+
+import "testing"
+
+func TestConfigHash(t *testing.T) {
+	hash := func(opts ...Option[Config]) uint64 { return Make(opts...).Hash() }
+
+	base := hash(WithSubject([]string{"a:b:c"}), WithDelim(":"))
+
+	if got := hash(WithSubject([]string{"a;b;c"}), WithDelim(";")); got != base {
+		t.Errorf("Hash() with other delimiter = %x, want %x", got, base)
+	}
+
+	if got := hash(WithSubject([]string{"a:b:a:c"}), WithDelim(":")); got != base {
+		t.Errorf("Hash() with duplicate = %x, want %x", got, base)
+	}
+
+	for name, opts := range map[string][]Option[Config]{
+		"reordered": {WithSubject([]string{"b:a:c"}), WithDelim(":")},
+		"joined":    {WithSubject([]string{"ab:c"}), WithDelim(":")},
+		"regrouped": {WithSubject([]string{"a:bc"}), WithDelim(":")},
+		"prefixed": {
+			WithSubject([]string{"a:b:c"}), WithDelim(":"), WithPrefixItems("p"),
+		},
+		"raw": {
+			WithSubject([]string{"a:b:a:c"}), WithDelim(":"),
+			WithAllowDuplicates(),
+		},
+	} {
+		if got := hash(opts...); got == base {
+			t.Errorf("Hash() %s = %x, want different from %x", name, got, base)
+		}
+	}
+
+	if got, want := (Config{}).Hash(), uint64(0xcbf29ce484222325); got != want {
+		t.Errorf("Hash() of empty = %x, want FNV-1a offset basis %x", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package mung
+
+// WithOnDuplicate returns an option that calls fn with each item dropped
+// because it is yielded in another position, as the munged sequence is
+// realized.
+//
+// Under [WithDedupeKeepLast], fn is called with each earlier occurrence once
+// the sequence is fully buffered, before any string is yielded.
+// Applying WithOnDuplicate again replaces any function already set.
+func WithOnDuplicate(fn func(item string)) Option[Config] {
+	return func(config Config) Config {
+		config.onDuplicate = fn
+
+		return config
+	}
+}
+
+// WithOnRemove returns an option that calls fn with each item dropped because
+// it matches a remove rule, and the rule it matched, as the munged sequence
+// is realized.
+//
+// Applying WithOnRemove again replaces any function already set.
+func WithOnRemove(fn func(item, rule string)) Option[Config] {
+	return func(config Config) Config {
+		config.onRemove = fn
+
+		return config
+	}
+}
+
+// WithOnReplace returns an option that calls fn with each item that matches
+// a replacement rule, and the replacement yielded instead, as the munged
+// sequence is realized.
+//
+// Applying WithOnReplace again replaces any function already set.
+func WithOnReplace(fn func(from, to string)) Option[Config] {
+	return func(config Config) Config {
+		config.onReplace = fn
+
+		return config
+	}
+}
+
+// hooked returns the receiver's observer, if any, extended to call the
+// functions set with [WithOnDuplicate], [WithOnRemove], and [WithOnReplace],
+// and to log with the logger set with [WithLogger].
+// It returns nil if there is nothing to observe.
+func (c Config) hooked() func(event) {
+	logger := c.debugLogger()
+	if c.onDuplicate == nil && c.onRemove == nil && c.onReplace == nil &&
+		logger == nil {
+		return c.observe
+	}
+
+	observe := c.observe
+
+	return func(ev event) {
+		if observe != nil {
+			observe(ev)
+		}
+
+		if logger != nil {
+			c.logEvent(logger, ev)
+		}
+
+		switch ev.op {
+		case OpDuplicate, opSuperseded:
+			if c.onDuplicate != nil {
+				c.onDuplicate(ev.item)
+			}
+
+		case OpRemove:
+			if c.onRemove != nil {
+				c.onRemove(ev.item, ev.rule)
+			}
+
+		case OpReplace:
+			if c.onReplace != nil {
+				c.onReplace(ev.item, ev.to)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package mung
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	tests := []struct {
+		name                          string
+		opts                          []Option[Config]
+		want                          string
+		duplicates, removes, replaces []string
+	}{
+		{
+			name: "none",
+			opts: []Option[Config]{WithSubject([]string{"a:b"})},
+			want: "a:b",
+		},
+		{
+			name: "decisions",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:a:x:c"}),
+				WithPrefix([]string{"c"}),
+				WithRemove([]string{"X"}),
+				WithReplace(map[string]string{"B": "y"}),
+				WithCaseFold(),
+			},
+			want:       "c:a:y",
+			duplicates: []string{"a", "c"},
+			removes:    []string{"x=X"},
+			replaces:   []string{"b=y"},
+		},
+		{
+			name: "keep_last",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:a"}),
+				WithDedupeKeepLast(),
+			},
+			want:       "b:a",
+			duplicates: []string{"a"},
+		},
+		{
+			name: "filtered",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:z:z"}),
+				WithRemove([]string{"z"}),
+				WithFilter(func(s string) bool { return s != "z" }),
+			},
+			want: "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var duplicates, removes, replaces []string
+
+			opts := append([]Option[Config]{
+				WithDelim(":"),
+				WithOnDuplicate(func(item string) {
+					duplicates = append(duplicates, item)
+				}),
+				WithOnRemove(func(item, rule string) {
+					removes = append(removes, item+"="+rule)
+				}),
+				WithOnReplace(func(from, to string) {
+					replaces = append(replaces, from+"="+to)
+				}),
+			}, tt.opts...)
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			if !slicesEqual(duplicates, tt.duplicates) {
+				t.Errorf("OnDuplicate = %v, want %v", duplicates, tt.duplicates)
+			}
+
+			if !slicesEqual(removes, tt.removes) {
+				t.Errorf("OnRemove = %v, want %v", removes, tt.removes)
+			}
+
+			if !slicesEqual(replaces, tt.replaces) {
+				t.Errorf("OnReplace = %v, want %v", replaces, tt.replaces)
+			}
+		})
+	}
+}
+
+func TestHooksPlan(t *testing.T) {
+	var removes []string
+
+	plan := Make(
+		WithSubject([]string{"a:b"}),
+		WithDelim(":"),
+		WithRemove([]string{"b"}),
+		WithOnRemove(func(item, _ string) { removes = append(removes, item) }),
+	).Plan()
+
+	if len(plan.Steps) != 2 || plan.Steps[1].Op != OpRemove {
+		t.Errorf("Plan().Steps = %+v, want keep and remove", plan.Steps)
+	}
+
+	if !slicesEqual(removes, []string{"b"}) {
+		t.Errorf("OnRemove = %v, want [b]", removes)
+	}
+}
+
+func TestHooksMerge(t *testing.T) {
+	var got []string
+
+	record := func(prefix string) func(string) {
+		return func(item string) { got = append(got, prefix+item) }
+	}
+
+	base := Make(WithOnDuplicate(record("base:")))
+	layer := Make(WithOnDuplicate(record("layer:")))
+
+	merged := base.Merge(layer).Merge(Config{})
+	_ = Wrap(merged, WithSubject([]string{"a:a"}), WithDelim(":")).String()
+
+	if want := []string{"base:a", "layer:a"}; !slices.Equal(got, want) {
+		t.Errorf("merged OnDuplicate = %v, want %v", got, want)
+	}
+}
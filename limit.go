@@ -0,0 +1,102 @@
+package mung
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// ErrTooManyItems is the error reported by [Config.FilteredErr] and
+// [Config.StringErr] when the subject strings contain more items than the
+// limit set with [WithMaxItems].
+var ErrTooManyItems = errors.New("too many items")
+
+// WithMaxItems returns an option that limits the number of items split from
+// the subject strings to n, e.g., to protect a service that munges untrusted
+// input from pathologically large values.
+//
+// Splitting stops at the first item over the limit, which is reported with
+// an error wrapping [ErrTooManyItems] by [Config.FilteredErr] and
+// [Config.StringErr]. Methods that do not report errors, such as
+// [Config.String], yield the munged sequence of the first n items only, as
+// under [WithMaxItemsTruncate].
+//
+// Subject strings read lazily, e.g., with [WithSubjectReader], are read only
+// until the limit is exceeded, even if the munged sequence cannot be streamed.
+// Prefix and suffix items are not counted. A limit less than or equal to zero
+// disables the limit (default).
+func WithMaxItems(n int) Option[Config] {
+	return func(config Config) Config {
+		config.maxItems, config.truncate = n, false
+
+		return config
+	}
+}
+
+// WithMaxItemsTruncate is like [WithMaxItems] but silently ignores the items
+// over the limit instead of reporting an error.
+func WithMaxItemsTruncate(n int) Option[Config] {
+	return func(config Config) Config {
+		config.maxItems, config.truncate = n, true
+
+		return config
+	}
+}
+
+// limit returns a sequence of at most the receiver's maximum number of items
+// of the given subject items, reporting the first item over the limit unless
+// truncating.
+func (c Config) limit(items iter.Seq[string]) iter.Seq[string] {
+	if c.maxItems <= 0 {
+		return items
+	}
+
+	return func(yield func(string) bool) {
+		n := 0
+
+		for s := range items {
+			if n++; n > c.maxItems {
+				if !c.truncate && c.onErr != nil {
+					c.onErr(s, fmt.Errorf("%w: limit is %d", ErrTooManyItems,
+						c.maxItems))
+				}
+
+				return
+			}
+
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// limitSource returns a source that produces the strings of src until they
+// hold more items than the receiver's maximum number of items, or src if
+// there is no limit, so that subject strings read lazily are not read in full
+// only to be limited afterward by [Config.limit].
+//
+// The string holding the first item over the limit is produced, so that the
+// item is reported by [Config.limit] as if the source were read in full.
+func (c Config) limitSource(src source) source {
+	if src == nil || c.maxItems <= 0 {
+		return src
+	}
+
+	return func(config Config) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			n := 0
+
+			for s := range src(config) {
+				for range c.splitSeq(slices.Values([]string{s})) {
+					n++
+				}
+
+				if !yield(s) || n > c.maxItems {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package mung
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option[Config]
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "under",
+			opts: []Option[Config]{WithMaxItems(3)},
+			want: "a:b",
+		},
+		{
+			name:    "over",
+			opts:    []Option[Config]{WithMaxItems(2)},
+			want:    "a:b",
+			wantErr: true,
+		},
+		{
+			name: "truncate",
+			opts: []Option[Config]{WithMaxItemsTruncate(2)},
+			want: "a:b",
+		},
+		{
+			name: "disabled",
+			opts: []Option[Config]{WithMaxItems(2), WithMaxItems(0)},
+			want: "a:b",
+		},
+		{
+			name: "prefix_not_counted",
+			opts: []Option[Config]{
+				WithMaxItems(3), WithPrefixItems("p", "q"), WithSuffixItems("s"),
+			},
+			want: "q:p:a:b:s",
+		},
+		{
+			name:    "keep_last",
+			opts:    []Option[Config]{WithMaxItems(2), WithDedupeKeepLast()},
+			want:    "a:b",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:a"}), WithDelim(":"),
+			}, tt.opts...)
+			config := Make(opts...)
+
+			got, err := config.StringErr()
+			if got != tt.want || errors.Is(err, ErrTooManyItems) != tt.wantErr {
+				t.Errorf("StringErr() = %q, %v, want %q, error %v",
+					got, err, tt.want, tt.wantErr)
+			}
+
+			if got := config.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMaxItemsReader(t *testing.T) {
+	r := &endlessReader{}
+
+	got, err := Make(
+		WithSubjectReader(r), WithDelim(":"), WithMaxItems(4),
+	).StringErr()
+	if got != "a" || !errors.Is(err, ErrTooManyItems) {
+		t.Errorf("StringErr() = %q, %v, want %q, %v",
+			got, err, "a", ErrTooManyItems)
+	}
+
+	if r.n > 1<<16 {
+		t.Errorf("StringErr() read %d bytes, want at most %d", r.n, 1<<16)
+	}
+
+	if !strings.Contains(err.Error(), "4") {
+		t.Errorf("error %q does not mention the limit", err)
+	}
+}
+
+func TestWithMaxItemsReaderCollected(t *testing.T) {
+	for name, opt := range map[string]Option[Config]{
+		"keep_last":    WithDedupeKeepLast(),
+		"prepend_skip": WithPrependPolicy(PrependSkip),
+		"batch": WithBatchFilter(func(items []string) []bool {
+			return make([]bool, len(items))
+		}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := &endlessReader{}
+
+			_, err := Make(
+				WithSubjectReader(r), WithDelim(":"), WithMaxItems(3), opt,
+			).StringErr()
+			if !errors.Is(err, ErrTooManyItems) {
+				t.Errorf("StringErr() error = %v, want %v", err, ErrTooManyItems)
+			}
+
+			if r.n > 1<<16 {
+				t.Errorf("StringErr() read %d bytes, want at most %d", r.n, 1<<16)
+			}
+		})
+	}
+}
+
+func TestWithMaxItemsMerge(t *testing.T) {
+	base := Make(WithMaxItems(2))
+
+	if got := base.Merge(Config{}); !got.Equal(base) {
+		t.Error("Merge() with zero value changed the limit")
+	}
+
+	merged := base.Merge(Make(WithMaxItemsTruncate(3)))
+	if !merged.Equal(Make(WithMaxItemsTruncate(3))) {
+		t.Error("Merge() did not take the limit of other")
+	}
+
+	if base.Equal(Make(WithMaxItemsTruncate(2))) {
+		t.Error("Equal() = true for configurations that differ in truncation")
+	}
+}
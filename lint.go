@@ -0,0 +1,314 @@
+package mung
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// IssueKind identifies the kind of problem described by an [Issue].
+type IssueKind int
+
+// Constant values of IssueKind.
+const (
+	// IssueEmpty is an empty item, e.g., between two adjacent delimiters,
+	// which many programs interpret as the current directory.
+	IssueEmpty IssueKind = iota
+	// IssueDuplicate is an item equal to an earlier item.
+	IssueDuplicate
+	// IssueRelative is an item that is not an absolute path, and so refers to
+	// a different directory depending on the current directory.
+	IssueRelative
+	// IssueMissing is an item that does not refer to an existing file.
+	IssueMissing
+	// IssueNotDir is an item that refers to a file other than a directory.
+	IssueNotDir
+	// IssueWorldWritable is an item that refers to a directory writable by
+	// every user, who could therefore plant programs in it.
+	IssueWorldWritable
+	// IssueShadowed is an item that refers to a directory containing an
+	// executable file of the same name as one in an earlier directory, which
+	// is therefore never found by a search of the items in order.
+	IssueShadowed
+)
+
+// String returns a short description of the kind of issue.
+func (k IssueKind) String() string {
+	switch k {
+	case IssueEmpty:
+		return "empty"
+	case IssueDuplicate:
+		return "duplicate"
+	case IssueRelative:
+		return "relative"
+	case IssueMissing:
+		return "missing"
+	case IssueNotDir:
+		return "not a directory"
+	case IssueWorldWritable:
+		return "world-writable"
+	case IssueShadowed:
+		return "shadowed"
+	default:
+		return fmt.Sprintf("IssueKind(%d)", int(k))
+	}
+}
+
+// Issue describes a problem found by [Lint] with an item of the subject
+// strings of a configuration.
+type Issue struct {
+	// Kind identifies the problem.
+	Kind IssueKind
+	// Index is the position of the item among all items of the subject
+	// strings, including empty items.
+	Index int
+	// Item is the item, normalized by the configuration.
+	Item string
+	// Other is the earlier item duplicated by the item under
+	// [IssueDuplicate], or the earlier item containing the executable file
+	// Name under [IssueShadowed].
+	Other string
+	// Name is the name of the executable file shadowed under [IssueShadowed].
+	Name string
+	// Err is the error that caused the issue, if any, e.g., the error
+	// returned by stat under [IssueMissing].
+	Err error
+}
+
+// String returns a description of the issue, e.g., for display by a linter.
+func (i Issue) String() string {
+	s := fmt.Sprintf("item %d %q: %s", i.Index, i.Item, i.Kind)
+
+	switch i.Kind {
+	case IssueEmpty:
+		s = fmt.Sprintf("item %d: %s", i.Index, i.Kind)
+	case IssueDuplicate:
+		s += fmt.Sprintf(" of %q", i.Other)
+	case IssueShadowed:
+		s += fmt.Sprintf(" %q by %q", i.Name, i.Other)
+	default:
+	}
+
+	if i.Err != nil {
+		s += ": " + i.Err.Error()
+	}
+
+	return s
+}
+
+// Lint returns the issues found with the items of the subject strings of c,
+// in the order of the items, e.g., to check the value of PATH in a CI
+// pipeline or to warn users of a misconfigured environment.
+//
+// Unlike the munged sequence, the items are linted as given: each item is
+// normalized, but empty items and duplicates are reported instead of
+// eliminated, and the removal, prefix, suffix, and replacement rules are not
+// applied. Duplicates are found using the comparison rules of c, e.g.,
+// [WithCaseFold] and [WithRealpath].
+//
+// Items are checked against the file system, as configured by [WithFS] or
+// [WithStatFunc], and the executable files of each directory are listed to
+// find shadowed files. Each issue of an item is reported, except that an
+// empty or duplicate item is not checked further.
+//
+// Empty items are only found if the [Tokenizer] of c is a [DelimTokenizer]
+// without escapes or quotes, since other tokenizers ignore them.
+func Lint(c Config) []Issue {
+	c = c.prepare()
+
+	var (
+		issues []Issue
+		dirs   []Issue // the directories checked for shadowed files
+		index  int
+	)
+
+	first := c.firstOf()
+
+	for str := range c.subjects() {
+		for _, s := range c.components(c.expandEnv(str)) {
+			issue := Issue{Index: index, Item: c.normalize(s)}
+			index++
+
+			if issue.Item == "" {
+				issue.Kind = IssueEmpty
+				issues = append(issues, issue)
+
+				continue
+			}
+
+			if other, ok := first(issue.Item); ok {
+				issue.Kind, issue.Other = IssueDuplicate, other
+				issues = append(issues, issue)
+
+				continue
+			}
+
+			found, isDir := c.lintItem(issue)
+			issues = append(issues, found...)
+
+			if isDir {
+				dirs = append(dirs, issue)
+			}
+		}
+	}
+
+	issues = append(issues, c.shadowed(dirs)...)
+
+	// Issues of shadowed files are found last but belong with their item.
+	slices.SortStableFunc(issues, func(a, b Issue) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	return issues
+}
+
+// lintItem returns the issues found with the file system entry of the item
+// of the given issue, and whether the item refers to a directory.
+func (c Config) lintItem(issue Issue) ([]Issue, bool) {
+	var issues []Issue
+
+	report := func(kind IssueKind, err error) {
+		issue.Kind, issue.Err = kind, err
+		issues = append(issues, issue)
+	}
+
+	// Rooted items are absolute within a file system set with WithFS.
+	if !filepath.IsAbs(issue.Item) && !strings.HasPrefix(issue.Item, "/") {
+		report(IssueRelative, nil)
+	}
+
+	info, err := c.statItem(issue.Item)
+
+	switch {
+	case err != nil:
+		report(IssueMissing, err)
+
+		return issues, false
+
+	case !info.IsDir():
+		report(IssueNotDir, nil)
+
+		return issues, false
+
+	case info.Mode().Perm()&0o002 != 0:
+		report(IssueWorldWritable, nil)
+	}
+
+	return issues, true
+}
+
+// firstOf returns a function that reports the first item given to it that
+// is equal to item s by the receiver's comparison rules, and whether there
+// was one, remembering s otherwise.
+func (c Config) firstOf() func(s string) (string, bool) {
+	if c.linear() {
+		var items []string
+
+		return func(s string) (string, bool) {
+			for _, item := range items {
+				if c.equalKeys(item, s) {
+					return item, true
+				}
+			}
+
+			items = append(items, s)
+
+			return "", false
+		}
+	}
+
+	items := map[string]string{}
+
+	return func(s string) (string, bool) {
+		k := c.key(s)
+		if item, ok := items[k]; ok {
+			return item, true
+		}
+
+		items[k] = s
+
+		return "", false
+	}
+}
+
+// shadowed returns the issues of the executable files in each of the given
+// directories with the same name as one in an earlier directory.
+func (c Config) shadowed(dirs []Issue) []Issue {
+	var issues []Issue
+
+	owner := map[string]string{} // directory of the first file of each name
+
+	for _, dir := range dirs {
+		entries, err := c.readDirFunc()(dir.Item)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !c.executable(filepath.Join(dir.Item, name)) {
+				continue
+			}
+
+			if other, ok := owner[name]; ok {
+				dir.Kind, dir.Other, dir.Name = IssueShadowed, other, name
+				issues = append(issues, dir)
+			} else {
+				owner[name] = dir.Item
+			}
+		}
+	}
+
+	return issues
+}
+
+// executable reports whether item s refers to a regular file with any of its
+// execute permission bits set.
+func (c Config) executable(s string) bool {
+	info, err := c.statItem(s)
+
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
+
+// readDirFunc returns the function used to list the entries of directories.
+func (c Config) readDirFunc() func(string) ([]fs.DirEntry, error) {
+	if c.readDir == nil {
+		return os.ReadDir
+	}
+
+	return c.readDir
+}
+
+// components returns the items of str, including empty items, if the
+// receiver's [Tokenizer] is a [DelimTokenizer] without escapes or quotes.
+// Otherwise, it returns the items as split by the Tokenizer.
+func (c Config) components(str string) []string {
+	d, ok := c.Tokenizer().(DelimTokenizer)
+	if !ok || d.syntax() != 0 || d.delims().empty() {
+		return slices.Collect(c.Tokenizer().Split(str))
+	}
+
+	delims := d.delims()
+
+	var items []string
+
+	start := 0
+
+	for i := 0; i < len(str); {
+		if n := delims.match(str[i:]); n > 0 {
+			items = append(items, str[start:i])
+			i += n
+			start = i
+
+			continue
+		}
+
+		i++
+	}
+
+	return append(items, str[start:])
+}
@@ -0,0 +1,138 @@
+package mung
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"usr/bin/ls":  {Mode: 0o755},
+		"usr/bin/cat": {Mode: 0o755},
+		"bin/ls":      {Mode: 0o755},
+		"bin/readme":  {Mode: 0o644},
+		"bin/sub":     {Mode: fs.ModeDir | 0o755},
+		"tmp":         {Mode: fs.ModeDir | 0o777},
+		"etc/passwd":  {Mode: 0o644},
+		"opt/bin/cat": {Mode: 0o644},
+	}
+
+	config := Make(
+		WithSubjectItems("/usr/bin::/bin:/usr/bin:rel:/tmp", "/etc/passwd:/missing:/opt/bin:"),
+		WithDelim(":"),
+		WithFS(fsys),
+	)
+
+	want := []Issue{
+		{Kind: IssueEmpty, Index: 1},
+		{Kind: IssueShadowed, Index: 2, Item: "/bin", Other: "/usr/bin", Name: "ls"},
+		{Kind: IssueDuplicate, Index: 3, Item: "/usr/bin", Other: "/usr/bin"},
+		{Kind: IssueRelative, Index: 4, Item: "rel"},
+		{Kind: IssueMissing, Index: 4, Item: "rel"},
+		{Kind: IssueWorldWritable, Index: 5, Item: "/tmp"},
+		{Kind: IssueNotDir, Index: 6, Item: "/etc/passwd"},
+		{Kind: IssueMissing, Index: 7, Item: "/missing"},
+		{Kind: IssueEmpty, Index: 9},
+	}
+
+	got := Lint(config)
+	if len(got) != len(want) {
+		t.Fatalf("Lint() = %v, want %v", got, want)
+	}
+
+	for i, issue := range got {
+		if issue.Kind == IssueMissing && !errors.Is(issue.Err, fs.ErrNotExist) {
+			t.Errorf("Lint()[%d].Err = %v, want %v", i, issue.Err, fs.ErrNotExist)
+		}
+
+		issue.Err = nil
+		if issue != want[i] {
+			t.Errorf("Lint()[%d] = %+v, want %+v", i, issue, want[i])
+		}
+	}
+}
+
+func TestLintComparison(t *testing.T) {
+	fsys := fstest.MapFS{"a": {Mode: fs.ModeDir | 0o755}}
+
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want []IssueKind
+	}{
+		{
+			name: "exact",
+			want: nil,
+		},
+		{
+			name: "case_fold",
+			opts: []Option[Config]{WithCaseFold()},
+			want: []IssueKind{IssueDuplicate},
+		},
+		{
+			name: "equal",
+			opts: []Option[Config]{WithEqual(func(a, b string) bool { return len(a) == len(b) })},
+			want: []IssueKind{IssueDuplicate},
+		},
+		{
+			name: "trim",
+			opts: []Option[Config]{WithTrimSpace(), WithSubjectItems(" ")},
+			want: []IssueKind{IssueEmpty},
+		},
+		{
+			name: "tokenizer",
+			opts: []Option[Config]{WithEscape(), WithSubjectItems(":")},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubjectItems("/a:/A"), WithDelim(":"), WithFS(fsys),
+			}, tt.opts...)
+
+			var got []IssueKind
+
+			for _, issue := range Lint(Make(opts...)) {
+				if issue.Kind != IssueMissing {
+					got = append(got, issue.Kind)
+				}
+			}
+
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Lint() kinds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueString(t *testing.T) {
+	tests := []struct {
+		issue Issue
+		want  string
+	}{
+		{Issue{Kind: IssueEmpty, Index: 1}, "item 1: empty"},
+		{
+			Issue{Kind: IssueDuplicate, Index: 2, Item: "/a", Other: "/A"},
+			`item 2 "/a": duplicate of "/A"`,
+		},
+		{
+			Issue{Kind: IssueShadowed, Index: 3, Item: "/b", Other: "/a", Name: "ls"},
+			`item 3 "/b": shadowed "ls" by "/a"`,
+		},
+		{
+			Issue{Kind: IssueMissing, Item: "/x", Err: fs.ErrNotExist},
+			`item 0 "/x": missing: file does not exist`,
+		},
+		{Issue{Kind: IssueKind(99)}, `item 0 "": IssueKind(99)`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.issue.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
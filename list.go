@@ -0,0 +1,288 @@
+package mung
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// List is a list of items of any comparable type munged with the same
+// deduplication, removal, replacement, and placement rules that a [Config]
+// applies to strings, e.g., for struct-valued search paths or typed flag sets:
+//
+//	type dir struct{ path, tag string }
+//
+//	list := mung.Make(
+//		mung.WithListSubject(dir{"/usr/bin", "sys"}, dir{"/old/bin", "old"}),
+//		mung.WithListPrefix(dir{"/opt/bin", "opt"}),
+//		mung.WithListRemove(dir{"/old/bin", "old"}),
+//	)
+//	for d := range list.All() {
+//		fmt.Println(d.path)
+//	}
+//
+// A List is munged by a configuration in which each item is represented by
+// a single string, never split by a [Tokenizer]. By default, each distinct
+// item is represented by an opaque string of its own, so that only the
+// policies of a Config, e.g., [WithDedupeKeepLast] and [WithPositionPolicy],
+// apply to a List as given with [WithListConfig]. Options that inspect or
+// rewrite the strings, e.g., [WithFilter], [WithMap], [WithReplace],
+// [WithSort], or [WithCleanPaths], see only the opaque strings, and items
+// rewritten by them are omitted. Set a [Codec] with [WithListCodec] to
+// represent each item by its text instead, so that these options apply to
+// the text of the items.
+//
+// The items and rules of a List replace any strings and rules given with
+// WithListConfig.
+//
+// The zero value is an empty List.
+type List[T comparable] struct {
+	subject []T
+	prefix  []T
+	suffix  []T
+	remove  []T
+	replace [][2]T
+	key     func(T) T
+	codec   Codec[T]
+	opts    []Option[Config]
+}
+
+// Codec converts the items of a [List] to and from the strings munged by its
+// configuration, e.g., to filter, map, or sort items by their text.
+//
+// Decode must accept each string returned by Encode. Strings rejected by
+// Decode, e.g., strings rewritten by [WithMap] that no longer represent an
+// item, are omitted from the List, as are items encoded as empty strings.
+type Codec[T any] interface {
+	Encode(item T) string
+	Decode(s string) (T, error)
+}
+
+// WithListSubject returns an option that adds items to the subject of a
+// [List], as if by [WithSubjectItems].
+func WithListSubject[T comparable](items ...T) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.subject = append(slices.Clip(list.subject), items...)
+
+		return list
+	}
+}
+
+// WithListPrefix returns an option that adds items to prepend to a [List],
+// as if by [WithPrefixItems].
+func WithListPrefix[T comparable](items ...T) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.prefix = append(slices.Clip(list.prefix), items...)
+
+		return list
+	}
+}
+
+// WithListSuffix returns an option that adds items to append to a [List],
+// as if by [WithSuffixItems].
+func WithListSuffix[T comparable](items ...T) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.suffix = append(slices.Clip(list.suffix), items...)
+
+		return list
+	}
+}
+
+// WithListRemove returns an option that adds items to remove from a [List],
+// as if by [WithRemoveItems].
+func WithListRemove[T comparable](items ...T) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.remove = append(slices.Clip(list.remove), items...)
+
+		return list
+	}
+}
+
+// WithListReplace returns an option that adds a rule replacing each item of
+// a [List] equal to from with to, as if by [WithReplaceItem].
+func WithListReplace[T comparable](from, to T) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.replace = append(slices.Clip(list.replace), [2]T{from, to})
+
+		return list
+	}
+}
+
+// WithListKey returns an option that sets the function used to derive the
+// comparison key of each item of a [List], as if by [WithKeyFunc].
+// Items are equal if their keys are equal, e.g., key may clear the fields
+// of a struct that do not identify it.
+// A nil key function compares items with ==.
+func WithListKey[T comparable](key func(T) T) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.key = key
+
+		return list
+	}
+}
+
+// WithListCodec returns an option that sets the [Codec] used to represent
+// each item of a [List] by its text. A nil codec represents each distinct
+// item by an opaque string (default).
+func WithListCodec[T comparable](codec Codec[T]) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.codec = codec
+
+		return list
+	}
+}
+
+// WithListConfig returns an option that adds options applied to the
+// configuration munging a [List], e.g., [WithDedupeKeepLast],
+// [WithAllowDuplicates], [WithPositionPolicy], or [WithPrependPolicy].
+func WithListConfig[T comparable](opts ...Option[Config]) Option[List[T]] {
+	return func(list List[T]) List[T] {
+		list.opts = append(slices.Clip(list.opts), opts...)
+
+		return list
+	}
+}
+
+// All returns a sequence of the munged items of the receiver that satisfy
+// any predicate functions given with [WithListConfig].
+func (l List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var coder listCoder[T] = &indexCoder[T]{index: map[T]int{}}
+		if l.codec != nil {
+			coder = codecCoder[T]{l.codec}
+		}
+
+		encode := func(items []T) []string {
+			s := make([]string, len(items))
+			for i, item := range items {
+				s[i] = coder.encode(item)
+			}
+
+			return s
+		}
+
+		opts := append(slices.Clip(l.opts),
+			WithTokenizer(itemTokenizer{}),
+			WithSubject(encode(l.subject)),
+			WithPrefix(nil), WithPrefixItems(encode(l.prefix)...),
+			WithSuffix(nil), WithSuffixItems(encode(l.suffix)...),
+			WithRemove(encode(l.remove)),
+			WithReplace(nil),
+		)
+
+		for _, r := range l.replace {
+			opts = append(opts,
+				WithReplaceItem(coder.encode(r[0]), coder.encode(r[1])))
+		}
+
+		if l.key != nil {
+			opts = append(opts, WithKeyFunc(coder.keyFunc(l.key)))
+		}
+
+		for s := range Make(opts...).Filtered() {
+			if item, ok := coder.decode(s); ok && !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// listCoder converts the items of a [List] to and from strings.
+type listCoder[T comparable] interface {
+	encode(item T) string
+	decode(s string) (T, bool)
+	// keyFunc returns a function deriving the comparison key of the string
+	// representing each item with key. It is called once every item of the
+	// List is encoded.
+	keyFunc(key func(T) T) func(string) string
+}
+
+// indexCoder represents each distinct item of a [List] by its decimal index
+// among the distinct items encoded.
+type indexCoder[T comparable] struct {
+	items []T
+	index map[T]int
+}
+
+func (c *indexCoder[T]) encode(item T) string {
+	i, ok := c.index[item]
+	if !ok {
+		i = len(c.items)
+		c.index[item] = i
+		c.items = append(c.items, item)
+	}
+
+	return strconv.Itoa(i)
+}
+
+func (c *indexCoder[T]) decode(s string) (T, bool) {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 || i >= len(c.items) {
+		var zero T
+
+		return zero, false
+	}
+
+	return c.items[i], true
+}
+
+// keyFunc derives the keys of the items before the sequence is realized, so
+// that the configuration only reads the items and their keys.
+func (c *indexCoder[T]) keyFunc(key func(T) T) func(string) string {
+	keys := make([]string, len(c.items))
+	for i := range keys {
+		keys[i] = c.encode(key(c.items[i]))
+	}
+
+	return func(s string) string {
+		if i, err := strconv.Atoi(s); err == nil && i >= 0 && i < len(keys) {
+			return keys[i]
+		}
+
+		return s
+	}
+}
+
+// codecCoder represents each item of a [List] by its text, as encoded by a
+// [Codec].
+type codecCoder[T comparable] struct {
+	codec Codec[T]
+}
+
+func (c codecCoder[T]) encode(item T) string { return c.codec.Encode(item) }
+
+func (c codecCoder[T]) decode(s string) (T, bool) {
+	item, err := c.codec.Decode(s)
+
+	return item, err == nil
+}
+
+func (c codecCoder[T]) keyFunc(key func(T) T) func(string) string {
+	return func(s string) string {
+		if item, ok := c.decode(s); ok {
+			return c.encode(key(item))
+		}
+
+		return s
+	}
+}
+
+// itemTokenizer is a [Tokenizer] that never splits strings, so that each
+// string munged for a [List] is exactly one item. Empty strings contain no
+// items. Joined items are separated by newlines, which are not escaped.
+type itemTokenizer struct{}
+
+// Split returns a sequence of s, unless s is empty.
+func (itemTokenizer) Split(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if s != "" {
+			yield(s)
+		}
+	}
+}
+
+// Join returns the items separated by newlines.
+func (itemTokenizer) Join(items iter.Seq[string]) string {
+	return strings.Join(slices.Collect(items), "\n")
+}
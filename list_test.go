@@ -0,0 +1,184 @@
+package mung
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type listDir struct {
+	path string
+	tag  string
+}
+
+// listDirCodec encodes a listDir as its path and tag separated by "#".
+type listDirCodec struct{}
+
+func (listDirCodec) Encode(d listDir) string { return d.path + "#" + d.tag }
+
+func (listDirCodec) Decode(s string) (listDir, error) {
+	path, tag, ok := strings.Cut(s, "#")
+	if !ok {
+		return listDir{}, errors.New("missing tag")
+	}
+
+	return listDir{path, tag}, nil
+}
+
+func TestList(t *testing.T) {
+	usr, opt, old := listDir{"/usr/bin", "sys"}, listDir{"/opt/bin", "opt"},
+		listDir{"/old/bin", "old"}
+	usrLocal := listDir{"/usr/bin", "local"}
+
+	tests := []struct {
+		name string
+		opts []Option[List[listDir]]
+		want []listDir
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "rules",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, old, usr),
+				WithListPrefix(opt),
+				WithListRemove(old),
+			},
+			want: []listDir{opt, usr},
+		},
+		{
+			name: "replace",
+			opts: []Option[List[listDir]]{
+				WithListSubject(old, usr),
+				WithListReplace(old, opt),
+			},
+			want: []listDir{opt, usr},
+		},
+		{
+			name: "suffix_moved",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, opt),
+				WithListSuffix(usr),
+			},
+			want: []listDir{opt, usr},
+		},
+		{
+			name: "key",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, usrLocal, opt),
+				WithListRemove(listDir{path: "/opt/bin"}),
+				WithListKey(func(d listDir) listDir { return listDir{path: d.path} }),
+			},
+			want: []listDir{usr},
+		},
+		{
+			name: "config",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, opt, usr),
+				WithListConfig[listDir](WithDedupeKeepLast()),
+			},
+			want: []listDir{opt, usr},
+		},
+		{
+			name: "codec_filter",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, opt, old),
+				WithListCodec[listDir](listDirCodec{}),
+				WithListConfig[listDir](WithFilter(func(s string) bool {
+					return !strings.HasPrefix(s, "/old/")
+				})),
+			},
+			want: []listDir{usr, opt},
+		},
+		{
+			name: "codec_sort",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, old, opt),
+				WithListCodec[listDir](listDirCodec{}),
+				WithListConfig[listDir](WithSort(strings.Compare)),
+			},
+			want: []listDir{old, opt, usr},
+		},
+		{
+			name: "codec_map",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, old),
+				WithListCodec[listDir](listDirCodec{}),
+				WithListConfig[listDir](WithMap(func(s string) string {
+					return strings.Replace(s, "#sys", "#local", 1)
+				})),
+			},
+			want: []listDir{usrLocal, old},
+		},
+		{
+			name: "codec_rejected",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, old),
+				WithListCodec[listDir](listDirCodec{}),
+				WithListConfig[listDir](WithMap(func(s string) string {
+					return strings.TrimSuffix(s, "#old")
+				})),
+			},
+			want: []listDir{usr},
+		},
+		{
+			name: "codec_key",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, usrLocal, opt),
+				WithListRemove(listDir{path: "/opt/bin"}),
+				WithListCodec[listDir](listDirCodec{}),
+				WithListKey(func(d listDir) listDir { return listDir{path: d.path} }),
+			},
+			want: []listDir{usr},
+		},
+		{
+			name: "config_rewrites_ignored",
+			opts: []Option[List[listDir]]{
+				WithListSubject(usr, opt),
+				WithListConfig[listDir](
+					WithDelim(":"), WithMap(func(s string) string { return s + "x" }),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slices.Collect(Make(tt.opts...).All()); !slices.Equal(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListConfigParity(t *testing.T) {
+	subject := []string{"/a", "/b", "/c", "/a", "/d"}
+
+	for _, opts := range [][]Option[Config]{
+		nil,
+		{WithDedupeKeepLast()},
+		{WithAllowDuplicates()},
+		{WithPositionPolicy(KeepSubjectPosition)},
+		{WithPrependPolicy(PrependSkip)},
+		{WithPrependPolicy(PrependDuplicate), WithAllowDuplicates()},
+	} {
+		config := Make(append(slices.Clip(opts),
+			WithDelim(":"), WithSubjectItems(subject...),
+			WithPrefixItems("/c", "/e"), WithSuffixItems("/b"),
+			WithRemoveItems("/d"), WithReplaceItem("/a", "/z"),
+		)...)
+		list := Make(
+			WithListSubject(subject...),
+			WithListPrefix("/c", "/e"), WithListSuffix("/b"),
+			WithListRemove("/d"), WithListReplace("/a", "/z"),
+			WithListConfig[string](opts...),
+		)
+
+		want := config.String()
+		if got := strings.Join(slices.Collect(list.All()), ":"); got != want {
+			t.Errorf("All() = %q, want %q", got, want)
+		}
+	}
+}
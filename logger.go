@@ -0,0 +1,86 @@
+package mung
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger returns an option that logs each item split from the prefix,
+// subject, and suffix strings, and each duplicate, removal, replacement, and
+// filter decision, with logger at [slog.LevelDebug] as the munged sequence is
+// realized.
+//
+// Each record's message is the name of the [Op] applied to the item.
+// Nothing is logged if logger is nil or not enabled at [slog.LevelDebug].
+func WithLogger(logger *slog.Logger) Option[Config] {
+	return func(config Config) Config {
+		config.logger = logger
+
+		return config
+	}
+}
+
+// debugLogger returns the receiver's logger if it is enabled at
+// [slog.LevelDebug], or nil otherwise.
+func (c Config) debugLogger() *slog.Logger {
+	if c.logger == nil || !c.logger.Enabled(c.logContext(), slog.LevelDebug) {
+		return nil
+	}
+
+	return c.logger
+}
+
+// logContext returns the context of the current realization, if any.
+func (c Config) logContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
+}
+
+// logEvent logs ev with logger at [slog.LevelDebug].
+func (c Config) logEvent(logger *slog.Logger, ev event) {
+	op := ev.op
+
+	switch op {
+	case opOutput, opHeld, opReleased:
+		// Items output unchanged or reordered are already logged as kept or
+		// inserted.
+		return
+
+	case opSuperseded:
+		op = OpDuplicate
+	}
+
+	attrs := []slog.Attr{slog.String("item", ev.item)}
+
+	if name := sourceName(ev.source); name != "" {
+		attrs = append(attrs, slog.String("source", name))
+	}
+
+	if ev.rule != "" {
+		attrs = append(attrs, slog.String("rule", ev.rule))
+	}
+
+	if op == OpReplace {
+		attrs = append(attrs, slog.String("to", ev.to))
+	}
+
+	logger.LogAttrs(c.logContext(), slog.LevelDebug, op.String(), attrs...)
+}
+
+// sourceName returns the name of the strings identified by source, or the
+// empty string if source does not identify exactly one of them.
+func sourceName(source Scope) string {
+	switch source {
+	case ScopePrefix:
+		return "prefix"
+	case ScopeSubject:
+		return "subject"
+	case ScopeSuffix:
+		return "suffix"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,70 @@
+package mung
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	newLogger := func(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+		return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+			Level: level,
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+					return slog.Attr{}
+				}
+
+				return a
+			},
+		}))
+	}
+
+	opts := []Option[Config]{
+		WithSubject([]string{"a:b:z:a:x"}),
+		WithDelim(":"),
+		WithPrefix([]string{"p"}),
+		WithRemove([]string{"x"}),
+		WithReplace(map[string]string{"b": "B"}),
+		WithFilter(func(s string) bool { return s != "z" }),
+	}
+
+	t.Run("debug", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		config := Make(append(opts, WithLogger(newLogger(&buf, slog.LevelDebug)))...)
+		if got := config.String(); got != "p:a:B" {
+			t.Errorf("String() = %q, want %q", got, "p:a:B")
+		}
+
+		want := []string{
+			"msg=insert item=p source=prefix",
+			"msg=keep item=a source=subject",
+			"msg=keep item=b source=subject",
+			"msg=replace item=b rule=b to=B",
+			"msg=filter item=z source=subject",
+			"msg=duplicate item=a source=subject",
+			"msg=remove item=x source=subject rule=x",
+		}
+
+		got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if !slicesEqual(got, want) {
+			t.Errorf("log =\n%s\nwant\n%s",
+				strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		config := Make(append(opts, WithLogger(newLogger(&buf, slog.LevelInfo)))...)
+		if got := config.String(); got != "p:a:B" {
+			t.Errorf("String() = %q, want %q", got, "p:a:B")
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("log = %q, want empty", buf.String())
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package mung
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Merge returns a configuration combining the receiver with other, such that
+// other is layered over the receiver, e.g., to apply a per-user configuration
+// over a system-wide policy.
+//
+// Settings are combined as follows:
+//   - Subject, remove, prefix, suffix, and ensured strings, rules removing
+//     or replacing items by pattern, and stages, are concatenated, with
+//     those of other last. So, the prefix strings of other lead the result
+//     and its suffix strings trail the result. Tags of prefix and suffix
+//     strings are kept, with those of other winning.
+//   - Items pinned with [WithPin] are united, with the positions of other
+//     taking precedence.
+//   - Replacement rules are united, with the rules of other taking precedence.
+//   - Predicate functions are combined as if by [WithFilterAnd], selecting
+//     only strings selected by both, except that a predicate function set
+//     with [WithBatchFilter] in other replaces that of the receiver.
+//     Exclusion predicate functions are combined with [FilterOr].
+//   - Mapping functions set with [WithMap] are composed, applying that of
+//     the receiver first.
+//   - Functions set with [WithOnDuplicate], [WithOnRemove], and
+//     [WithOnReplace] are composed, calling that of the receiver first.
+//   - Each other setting of other takes precedence if it is not the zero
+//     value, e.g., an empty delimiter does not replace that of the receiver,
+//     and boolean settings are enabled if enabled in either.
+func (c Config) Merge(other Config) Config {
+	m := c.Clone()
+
+	m.subject, m.sources.subject = concatStrings(c, other, Config.subjectStrings)
+	m.remove, m.sources.remove = concatStrings(c, other, Config.removeStrings)
+	m.prefix, m.sources.prefix = concatStrings(c, other, Config.prefixStrings)
+	m.suffix, m.sources.suffix = concatStrings(c, other, Config.suffixStrings)
+	m.removals = slices.Concat(c.removals, other.removals)
+	m.rewrites = slices.Concat(c.rewrites, other.rewrites)
+	m.ensure = slices.Concat(c.ensure, other.ensure)
+	m.stages = slices.Concat(c.stages, other.stages)
+	m.after = slices.Concat(c.after, other.after)
+	m.tags = slices.Concat(c.tags, other.tags)
+
+	for _, p := range other.pins {
+		m = WithPin(p.item, p.index)(m)
+	}
+
+	if other.replace != nil {
+		m = WithReplaceItems(other.replace)(m)
+	}
+
+	if other.delim != "" {
+		m.delim, m.alt = other.delim, slices.Clone(other.alt)
+	}
+
+	m.pattern = cmp.Or(other.pattern, c.pattern)
+	m.output = cmp.Or(other.output, c.output)
+	m.syntax = c.syntax | other.syntax
+	m.tok = cmp.Or(other.tok, c.tok)
+
+	m.dedupe = cmp.Or(other.dedupe, c.dedupe)
+	m.placing = cmp.Or(other.placing, c.placing)
+	m.prepend = cmp.Or(other.prepend, c.prepend)
+	m.strict = c.strict || other.strict
+	m.fold = c.fold || other.fold
+	m.trim = c.trim || other.trim
+	m.clean = c.clean || other.clean
+	m.tilde = c.tilde || other.tilde
+	m.windows = c.windows || other.windows
+	m.extended = c.extended || other.extended
+	m.wsl = cmp.Or(other.wsl, c.wsl)
+	m.sameFile = c.sameFile || other.sameFile
+	m.exist = cmp.Or(other.exist, c.exist)
+	m.filterScope = cmp.Or(other.filterScope, c.filterScope)
+	m.removeScope = cmp.Or(other.removeScope, c.removeScope)
+	m.cache = cmp.Or(other.cache, c.cache)
+	m.logger = cmp.Or(other.logger, c.logger)
+	m.workers = cmp.Or(other.workers, c.workers)
+
+	if other.links != symlinksNone {
+		m.links, m.abs = other.links, other.abs
+	}
+
+	// The normal form is set together with the mode, and NFC is its zero
+	// value, so the form of other is taken whenever its mode is set.
+	if other.unicode != unicodeNone {
+		m.unicode, m.form = other.unicode, other.form
+	}
+
+	if other.timeout != 0 {
+		m.timeout, m.timeoutErr = other.timeout, other.timeoutErr
+	}
+
+	if other.order != nil {
+		m.order = slices.Clone(other.order)
+	}
+
+	if other.maxItems != 0 {
+		m.maxItems, m.truncate = other.maxItems, other.truncate
+	}
+
+	if other.equal != nil {
+		m.equal = other.equal
+	}
+
+	if other.quote != nil {
+		m.quote = other.quote
+	}
+
+	if other.keyFunc != nil {
+		m.keyFunc = other.keyFunc
+	}
+
+	if other.expand != nil {
+		m.expand = other.expand
+	}
+
+	if other.statFn != nil {
+		m.statFn = other.statFn
+	}
+
+	if other.evalFn != nil {
+		m.evalFn = other.evalFn
+	}
+
+	if other.readDir != nil {
+		m.readDir = other.readDir
+	}
+
+	if other.priority != nil {
+		m.priority = other.priority
+	}
+
+	if other.compare != nil {
+		m.compare = other.compare
+	}
+
+	if other.mapping != nil {
+		m.mapping = other.mapping
+		if c.mapping != nil {
+			m.mapping = func(s string) string { return other.mapping(c.mapping(s)) }
+		}
+	}
+
+	switch {
+	case other.predicate != nil:
+		// The Predicate equivalent of each variant is set with the variant.
+		if c.predicate == nil && c.batch == nil {
+			m.predicate, m.predicateErr = other.predicate, other.predicateErr
+			m.predicateCtx, m.batch = other.predicateCtx, other.batch
+		} else {
+			m = WithFilterAnd(other.predicate)(m)
+		}
+
+	case other.batch != nil:
+		m = WithBatchFilter(other.batch)(m)
+	}
+
+	if other.exclude != nil {
+		m.exclude = other.exclude
+		if c.exclude != nil {
+			m.exclude = FilterOr(c.exclude, other.exclude)
+		}
+	}
+
+	m.onDuplicate = chain(c.onDuplicate, other.onDuplicate)
+	m.onRemove = chain2(c.onRemove, other.onRemove)
+	m.onReplace = chain2(c.onReplace, other.onReplace)
+	m.skipSame = c.skipSame || other.skipSame
+
+	return m
+}
+
+// chain returns a function calling a and then b, ignoring either if nil.
+func chain(a, b func(string)) func(string) {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+
+	return func(s string) { a(s); b(s) }
+}
+
+// chain2 is like chain for functions of two strings.
+func chain2(a, b func(string, string)) func(string, string) {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+
+	return func(s, t string) { a(s, t); b(s, t) }
+}
+
+// concatStrings returns the concatenation of the strings of a and b selected
+// by field, or, if either is produced lazily, a source that produces them.
+func concatStrings(
+	a, b Config, field func(Config) ([]string, source),
+) ([]string, source) {
+	as, asrc := field(a)
+	bs, bsrc := field(b)
+
+	if asrc == nil && bsrc == nil {
+		return slices.Concat(as, bs), nil
+	}
+
+	return nil, func(c Config) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for _, s := range []iter.Seq[string]{
+				c.values(as, asrc), c.values(bs, bsrc),
+			} {
+				for str := range s {
+					if !yield(str) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
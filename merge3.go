@@ -0,0 +1,113 @@
+package mung
+
+import "slices"
+
+// Conflict describes an item placed differently by both sides of [Merge3].
+//
+// OursAfter and TheirsAfter are the items that the item follows in ours and
+// theirs, respectively, among the items of the merged result, or the empty
+// string if the item leads.
+type Conflict struct {
+	Item        string
+	OursAfter   string
+	TheirsAfter string
+}
+
+// Merge3 reconciles the munged sequences of ours and theirs, two divergent
+// edits of base, e.g., to sync a PATH-like value across machines.
+//
+// The merged items include the items of base kept by both sides, and the
+// items added by either side. So, an item removed by either side is removed.
+// The order of ours is preserved, except that the items added or moved by
+// theirs alone are placed after the same item as in theirs, disregarding
+// the items moved by ours.
+//
+// An item added or moved by both sides to follow different items is a
+// conflict, in which case the item is placed as in ours and reported.
+// Items are compared exactly, as in [Union], and each is merged once.
+func Merge3(base, ours, theirs Config) ([]string, []Conflict) {
+	b, o, t := distinct(base), distinct(ours), distinct(theirs)
+
+	inBase, inOurs, inTheirs := SetOf(slices.Values(b)),
+		SetOf(slices.Values(o)), SetOf(slices.Values(t))
+
+	kept := func(s string) bool {
+		if inBase.Contains(s) {
+			return inOurs.Contains(s) && inTheirs.Contains(s)
+		}
+
+		return inOurs.Contains(s) || inTheirs.Contains(s)
+	}
+
+	_, _, oursMoved := diff(b, o)
+	_, _, theirsMoved := diff(b, t)
+	movedO := SetOf(slices.Values(oursMoved))
+	movedT := SetOf(slices.Values(theirsMoved))
+
+	var merged []string
+
+	for _, s := range o {
+		if kept(s) {
+			merged = append(merged, s)
+		}
+	}
+
+	// after returns the nearest item preceding items[i] that is merged,
+	// ignoring the items in skip.
+	after := func(items []string, i int, skip Set[string]) string {
+		for j := i - 1; j >= 0; j-- {
+			if !skip.Contains(items[j]) && slices.Contains(merged, items[j]) {
+				return items[j]
+			}
+		}
+
+		return ""
+	}
+
+	var conflicts []Conflict
+
+	for i, s := range t {
+		if !kept(s) {
+			continue
+		}
+
+		theirsAfter := after(t, i, nil)
+
+		switch {
+		case !inOurs.Contains(s):
+			// Added by theirs alone.
+
+		case movedT.Contains(s) && !movedO.Contains(s):
+			// Moved by theirs alone.
+
+		case movedT.Contains(s) || (!inBase.Contains(s) && inOurs.Contains(s)):
+			// Moved or added by both.
+			oursAfter := after(o, slices.Index(o, s), nil)
+			if oursAfter != theirsAfter {
+				conflicts = append(conflicts, Conflict{
+					Item: s, OursAfter: oursAfter, TheirsAfter: theirsAfter,
+				})
+			}
+
+			continue
+
+		default:
+			continue
+		}
+
+		if j := slices.Index(merged, s); j >= 0 {
+			merged = slices.Delete(merged, j, j+1)
+		}
+
+		// Items moved by ours are not anchors, since their position in theirs
+		// is not their position in the merged items.
+		j := 0
+		if anchor := after(t, i, movedO); anchor != "" {
+			j = slices.Index(merged, anchor) + 1
+		}
+
+		merged = slices.Insert(merged, j, s)
+	}
+
+	return merged, conflicts
+}
@@ -0,0 +1,118 @@
+package mung
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               []string
+		conflicts          []Conflict
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "unchanged",
+			base:   "a:b:c",
+			ours:   "a:b:c",
+			theirs: "a:b:c",
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			name:   "added_by_each",
+			base:   "a:b:c",
+			ours:   "a:b:c:x",
+			theirs: "y:a:b:c",
+			want:   []string{"y", "a", "b", "c", "x"},
+		},
+		{
+			name:   "added_by_theirs_within",
+			base:   "a:b:c",
+			ours:   "x:a:b:c",
+			theirs: "a:b:y:c",
+			want:   []string{"x", "a", "b", "y", "c"},
+		},
+		{
+			name:   "removed_by_each",
+			base:   "a:b:c:d",
+			ours:   "a:c:d",
+			theirs: "a:b:c",
+			want:   []string{"a", "c"},
+		},
+		{
+			name:   "removed_and_added",
+			base:   "a:b:c",
+			ours:   "a:c",
+			theirs: "a:b:c:d",
+			want:   []string{"a", "c", "d"},
+		},
+		{
+			name:   "moved_by_theirs",
+			base:   "a:b:c",
+			ours:   "a:b:c:x",
+			theirs: "c:a:b",
+			want:   []string{"c", "a", "b", "x"},
+		},
+		{
+			name:   "moved_by_ours",
+			base:   "a:b:c",
+			ours:   "c:a:b",
+			theirs: "a:b:c:y",
+			want:   []string{"c", "a", "b", "y"},
+		},
+		{
+			name:   "moved_by_both_alike",
+			base:   "a:b:c",
+			ours:   "c:a:b",
+			theirs: "c:a:b",
+			want:   []string{"c", "a", "b"},
+		},
+		{
+			name:      "moved_by_both_differently",
+			base:      "a:b:c",
+			ours:      "c:a:b",
+			theirs:    "a:c:b",
+			want:      []string{"c", "a", "b"},
+			conflicts: []Conflict{{Item: "c", OursAfter: "", TheirsAfter: "a"}},
+		},
+		{
+			name:   "added_by_both_alike",
+			base:   "a:b",
+			ours:   "a:x:b",
+			theirs: "a:x:b",
+			want:   []string{"a", "x", "b"},
+		},
+		{
+			name:      "added_by_both_differently",
+			base:      "a:b",
+			ours:      "x:a:b",
+			theirs:    "a:b:x",
+			want:      []string{"x", "a", "b"},
+			conflicts: []Conflict{{Item: "x", OursAfter: "", TheirsAfter: "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := func(s string) Config {
+				return Make(WithSubject([]string{s}), WithDelim(":"))
+			}
+
+			got, conflicts := Merge3(config(tt.base), config(tt.ours), config(tt.theirs))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Merge3() = %v, want %v", got, tt.want)
+			}
+
+			if len(conflicts) > 0 || len(tt.conflicts) > 0 {
+				if !reflect.DeepEqual(conflicts, tt.conflicts) {
+					t.Errorf("Merge3() conflicts = %+v, want %+v",
+						conflicts, tt.conflicts)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package mung
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestConfigMerge(t *testing.T) {
+	hasA := func(s string) bool { return strings.Contains(s, "a") }
+	hasB := func(s string) bool { return strings.Contains(s, "b") }
+
+	tests := []struct {
+		name        string
+		base, layer []Option[Config]
+		want        []string
+	}{
+		{
+			name: "empty",
+			base: []Option[Config]{WithSubject([]string{"a:b"})},
+			want: []string{"a", "b"},
+		},
+		{
+			name:  "concatenate",
+			base:  []Option[Config]{WithSubject([]string{"a"}), WithPrefix([]string{"p1"}), WithSuffix([]string{"s1"})},
+			layer: []Option[Config]{WithSubject([]string{"b"}), WithPrefix([]string{"p2"}), WithSuffix([]string{"s2"})},
+			want:  []string{"p2", "p1", "a", "b", "s1", "s2"},
+		},
+		{
+			name:  "remove",
+			base:  []Option[Config]{WithSubject([]string{"a:b:c"}), WithRemove([]string{"a"})},
+			layer: []Option[Config]{WithRemove([]string{"c"})},
+			want:  []string{"b"},
+		},
+		{
+			name:  "replace_precedence",
+			base:  []Option[Config]{WithSubject([]string{"a:b"}), WithReplace(map[string]string{"a": "x", "b": "y"})},
+			layer: []Option[Config]{WithReplace(map[string]string{"a": "z"})},
+			want:  []string{"z", "y"},
+		},
+		{
+			name:  "delim_precedence",
+			base:  []Option[Config]{WithSubject([]string{"a:b,c"}), WithDelim(":")},
+			layer: []Option[Config]{WithDelim(",")},
+			want:  []string{"a:b", "c"},
+		},
+		{
+			name:  "empty_delim_ignored",
+			base:  []Option[Config]{WithSubject([]string{"a:b"}), WithDelim(":")},
+			layer: []Option[Config]{WithCaseFold()},
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "predicates_and",
+			base:  []Option[Config]{WithSubject([]string{"a:b:ab"}), WithFilter(hasA)},
+			layer: []Option[Config]{WithFilter(hasB)},
+			want:  []string{"ab"},
+		},
+		{
+			name: "predicate_base_only",
+			base: []Option[Config]{WithSubject([]string{"a:b:ab"}), WithFilter(hasA)},
+			want: []string{"a", "ab"},
+		},
+		{
+			name:  "predicate_layer_only",
+			base:  []Option[Config]{WithSubject([]string{"a:b:ab"})},
+			layer: []Option[Config]{WithFilter(hasB)},
+			want:  []string{"b", "ab"},
+		},
+		{
+			name:  "exclude_or",
+			base:  []Option[Config]{WithSubject([]string{"a:b:ab:c"}), WithExclude(hasA)},
+			layer: []Option[Config]{WithExclude(hasB)},
+			want:  []string{"c"},
+		},
+		{
+			name:  "mapping_composed",
+			base:  []Option[Config]{WithSubject([]string{"a"}), WithMap(strings.ToUpper)},
+			layer: []Option[Config]{WithMap(func(s string) string { return s + "!" })},
+			want:  []string{"A!"},
+		},
+		{
+			name:  "stages_concatenated",
+			base:  []Option[Config]{WithSubject([]string{"b:a"}), WithStages(upperStage)},
+			layer: []Option[Config]{WithStages(sortStage)},
+			want:  []string{"A", "B"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := Make(append([]Option[Config]{WithDelim(":")}, tt.base...)...)
+			layer := Make(tt.layer...)
+			before := base.Clone()
+
+			got := slices.Collect(base.Merge(layer).Filtered())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Merge().Filtered() = %v, want %v", got, tt.want)
+			}
+
+			if !base.Equal(before) {
+				t.Errorf("Merge() modified receiver = %+v, want %+v", base, before)
+			}
+		})
+	}
+}
+
+func TestConfigMergeSettings(t *testing.T) {
+	base := Make(WithCaseFold(), WithDedupeKeepLast(), WithParallelFilter(2))
+	layer := Make(WithTrimSpace(), WithParallelFilter(4))
+
+	got := base.Merge(layer)
+	want := Make(
+		WithCaseFold(), WithDedupeKeepLast(), WithTrimSpace(),
+		WithParallelFilter(4),
+	)
+
+	if !got.Equal(want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+
+	if got := layer.Merge(base); got.dedupe != dedupeKeepLast || got.workers != 2 {
+		t.Errorf("Merge() reversed = %+v, want keep last with 2 workers", got)
+	}
+}
+
+func TestConfigMergeUnicode(t *testing.T) {
+	nfd := Make(WithNormalizeUnicode(norm.NFD))
+	nfc := Make(WithRewriteUnicode(norm.NFC))
+
+	if got, want := nfd.Merge(nfc), nfc; !got.Equal(want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+
+	if got, want := nfd.Merge(Config{}), nfd; !got.Equal(want) {
+		t.Errorf("Merge() with unset form = %+v, want %+v", got, want)
+	}
+}
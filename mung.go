@@ -3,10 +3,22 @@
 package mung
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"iter"
+	"log/slog"
 	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
 
 	_ "embed"
 )
@@ -36,56 +48,272 @@ func Wrap[T any](t T, opts ...Option[T]) T {
 	return t
 }
 
+// Compose returns an option that applies the given options in order, e.g., to
+// name a group of options that are commonly used together.
+func Compose[T any](opts ...Option[T]) Option[T] {
+	return func(t T) T { return Wrap(t, opts...) }
+}
+
+// When returns an option that applies the given options in order if cond is
+// true, and otherwise has no effect, e.g.:
+//
+//	config := mung.Make(
+//		mung.WithSubject(args),
+//		mung.When(verbose, mung.WithLogger(logger)),
+//	)
+func When[T any](cond bool, opts ...Option[T]) Option[T] {
+	if !cond {
+		return Compose[T]()
+	}
+
+	return Compose(opts...)
+}
+
+// Unless is like [When] but applies the given options if cond is false.
+func Unless[T any](cond bool, opts ...Option[T]) Option[T] {
+	return When(!cond, opts...)
+}
+
+// OptionE functions are like [Option] functions but may fail, e.g., to parse
+// user input, returning their argument unmodified with a non-nil error.
+type OptionE[T any] func(T) (T, error)
+
+// TryMake is like [Make] but applies options that may fail.
+//
+// Options are applied in order until one returns a non-nil error, which is
+// returned along with the object as modified by the preceding options.
+//
+//nolint:ireturn
+func TryMake[T any](opts ...OptionE[T]) (t T, err error) {
+	return TryWrap(t, opts...)
+}
+
+// TryWrap is like [Wrap] but applies options that may fail.
+//
+// Options are applied in order until one returns a non-nil error, which is
+// returned along with t as modified by the preceding options.
+//
+//nolint:ireturn
+func TryWrap[T any](t T, opts ...OptionE[T]) (T, error) {
+	for _, o := range opts {
+		u, err := o(t)
+		if err != nil {
+			return t, err
+		}
+
+		t = u
+	}
+
+	return t, nil
+}
+
+// MustMake is like [TryMake] but panics if any option fails.
+// It simplifies initialization of package-level variables, e.g.:
+//
+//	var config = mung.MustMake(mung.WithDelimRegexp(`[,;]`))
+//
+//nolint:ireturn
+func MustMake[T any](opts ...OptionE[T]) T {
+	t, err := TryMake(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// Try returns an [OptionE] that applies the given options and never fails,
+// e.g., to combine them with other options in [TryMake] or [TryWrap].
+func Try[T any](opts ...Option[T]) OptionE[T] {
+	return func(t T) (T, error) { return Wrap(t, opts...), nil }
+}
+
 // Config represents the configuration for string munging operations.
 type Config struct {
 	subject []string
+	sources sources
+	claims  claims
 	delim   string
 	remove  []string
 	prefix  []string
 	suffix  []string
+	ensure  []string
 	replace map[string]string
-
-	predicate func(string) bool
+	dedupe  dedupe
+	placing PositionPolicy
+	prepend PrependPolicy
+	strict  bool
+	fold    bool
+	equal   func(a, b string) bool
+	keyFunc func(string) string
+	trim    bool
+	clean   bool
+	tilde   bool
+	expand  func(string) string
+	unicode unicodeMode
+	form    norm.Form
+	links   symlinks
+	abs     bool
+	resolve func(string) string
+
+	windows  bool
+	extended bool
+	wsl      wslConversion
+	mapping  func(string) string
+	stages   []Stage
+	after    []stagePoint
+	order    []StageName
+	priority func(string) int
+	compare  func(a, b string) int
+	pins     []pin
+	tags     []tag
+	removals []removal
+	rewrites []rewriter
+
+	sameFile bool
+	exist    existence
+	stat     func(string) (fs.FileInfo, error)
+	statFn   func(string) (fs.FileInfo, error)
+	readDir  func(string) ([]fs.DirEntry, error)
+	evalFn   func(string) (string, error)
+
+	alt     []string
+	pattern *regexp.Regexp
+	output  string
+	syntax  syntax
+	tok     Tokenizer
+	quote   func(string) string
+
+	predicate    func(string) bool
+	predicateErr func(string) (bool, error)
+	predicateCtx func(context.Context, string) bool
+	batch        func([]string) []bool
+	exclude      func(string) bool
+	filterScope  Scope
+	removeScope  Scope
+	cache        FilterCache
+	workers      int
+	timeout      time.Duration
+	timeoutErr   bool
+	maxItems     int
+	truncate     bool
+
+	onDuplicate func(string)
+	onRemove    func(item, rule string)
+	onReplace   func(from, to string)
+	logger      *slog.Logger
+	skipSame    bool
+
+	ctx     context.Context //nolint:containedctx // per-call, see FilteredContext
+	onErr   func(string, error)
+	report  *Report
+	observe func(event)
+	built   *compiled
+	view    func(string) string
 }
 
-// String returns the munged strings joined with the configuration's delimiter.
+// dedupe identifies the policy used to reconcile repeated items.
+type dedupe int
+
+const (
+	// dedupeKeepFirst keeps the first occurrence of each item (default).
+	dedupeKeepFirst dedupe = iota
+	// dedupeKeepLast keeps the final occurrence of each item.
+	dedupeKeepLast
+	// dedupeNone keeps every occurrence of each item.
+	dedupeNone
+)
+
+// PositionPolicy identifies where an item that appears in both the subject
+// and suffix is yielded.
+type PositionPolicy int
+
+// Constant values of PositionPolicy.
+const (
+	// MoveToSuffix yields the item in its suffix position (default).
+	MoveToSuffix PositionPolicy = iota
+	// KeepSubjectPosition yields the item in its subject position.
+	KeepSubjectPosition
+)
+
+// PrependPolicy identifies how an item that appears in both the prefix
+// and subject is yielded.
+type PrependPolicy int
+
+// Constant values of PrependPolicy.
+const (
+	// PrependMove yields the item in its prefix position only (default).
+	PrependMove PrependPolicy = iota
+	// PrependSkip yields the item in its subject position only.
+	PrependSkip
+	// PrependDuplicate yields the item in both positions.
+	PrependDuplicate
+)
+
+// String returns the munged strings joined with the configuration's
+// [Tokenizer], which by default joins them with the configured delimiter.
 func (c Config) String() string {
-	bufLen := sumLen(c.prefix) + sumLen(c.suffix) +
-		sumLen(c.subject) + sumLen(slices.Collect(maps.Values(c.replace))) +
-		max(0, len(c.delim)*
-			(len(c.prefix)+len(c.suffix)+len(c.subject)+len(c.replace)-1))
+	return c.Tokenizer().Join(c.quoted(c.Filtered()))
+}
 
-	var sb strings.Builder
+// StringErr is like [Config.String] but also returns the errors yielded by
+// [Config.FilteredErr], if any, joined with [errors.Join].
+// Each error is annotated with the item it was paired with.
+func (c Config) StringErr() (string, error) {
+	var errs []error
 
-	sb.Grow(bufLen)
+	s := c.Tokenizer().Join(c.quoted(func(yield func(string) bool) {
+		for s, err := range c.FilteredErr() {
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%q: %w", s, err))
 
-	c.Filtered()(func(s string) bool {
-		if sb.Len() > 0 {
-			sb.WriteString(c.delim)
+				continue
+			}
+
+			if !yield(s) {
+				return
+			}
 		}
+	}))
 
-		sb.WriteString(s)
+	return s, errors.Join(errs...)
+}
 
-		return true
-	})
+// StringReport is like [Config.String] but also returns a [Report] of the
+// remove and replace rules that matched no items, e.g., to warn about rules
+// that no longer have any effect without failing like [WithStrict].
+func (c Config) StringReport() (string, Report) {
+	var report Report
+
+	c.report = &report
+	s := c.String()
 
-	return sb.String()
+	return s, report
 }
 
-// Subject returns the subject strings to be processed.
-func (c Config) Subject() []string { return c.subject }
+// Subject returns a copy of the subject strings to be processed.
+func (c Config) Subject() []string { return slices.Collect(c.subjects()) }
 
 // Delim returns the delimiter used for splitting and joining strings.
+//
+// See [Config.OutputDelim] for the delimiter used for joining strings.
 func (c Config) Delim() string { return c.delim }
 
-// Remove returns the list of strings to be removed during processing.
-func (c Config) Remove() []string { return c.remove }
+// Remove returns a copy of the list of strings to be removed during
+// processing.
+func (c Config) Remove() []string {
+	return slices.Clone(c.materializeRules().remove)
+}
 
-// Prefix returns the list of strings to be prepended to the result.
-func (c Config) Prefix() []string { return c.prefix }
+// Prefix returns a copy of the list of strings to be prepended to the result.
+func (c Config) Prefix() []string {
+	return slices.Clone(c.materializeRules().prefix)
+}
 
-// Suffix returns the list of strings to be appended to the result.
-func (c Config) Suffix() []string { return c.suffix }
+// Suffix returns a copy of the list of strings to be appended to the result.
+func (c Config) Suffix() []string {
+	return slices.Clone(c.materializeRules().suffix)
+}
 
 // Replace returns a copy of the string replacement map.
 func (c Config) Replace() map[string]string { return maps.Clone(c.replace) }
@@ -104,94 +332,873 @@ func (c Config) Predicate() func(string) bool {
 // All returns each string item from the munged sequence.
 func (c Config) All() iter.Seq[string] { return c.seq(false) }
 
+// All2 is like [Config.All] but also yields the index of each string item in
+// the munged sequence, starting at zero, like [slices.All].
+func (c Config) All2() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		i := 0
+
+		for s := range c.seq(false) {
+			if !yield(i, s) {
+				return
+			}
+
+			i++
+		}
+	}
+}
+
 // Filtered returns each string item from the munged sequence
-// that satisfies the predicate function [Config.Predicate].
+// that satisfies the predicate function [Config.Predicate]
+// and any built-in filters, e.g., [WithExistingOnly].
 func (c Config) Filtered() iter.Seq[string] { return c.seq(true) }
 
+// FilteredErr is like [Config.Filtered] but also yields each error returned by
+// the predicate function set with [WithFilterErr], or [ErrFilterTimeout],
+// paired with the item for which it was returned.
+//
+// Items for which the predicate returns an error are not otherwise yielded.
+// Iteration continues after an error unless the caller stops it.
+func (c Config) FilteredErr() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		var failed []itemErr
+
+		c.onErr = func(s string, err error) {
+			failed = append(failed, itemErr{item: s, err: err})
+		}
+
+		// Errors are yielded before the next item that satisfies the predicate,
+		// preserving the order in which items were evaluated.
+		flush := func() bool {
+			for _, f := range failed {
+				if !yield(f.item, f.err) {
+					return false
+				}
+			}
+
+			failed = failed[:0]
+
+			return true
+		}
+
+		for s := range c.seq(true) {
+			if !flush() || !yield(s, nil) {
+				return
+			}
+		}
+
+		flush()
+	}
+}
+
+// FilteredContext is like [Config.Filtered] but passes ctx to the predicate
+// function set with [WithFilterContext].
+//
+// Iteration stops once ctx is done, and no further items are evaluated by the
+// predicate. The caller may inspect ctx.Err() after iteration to determine
+// whether the sequence was cut short.
+func (c Config) FilteredContext(ctx context.Context) iter.Seq[string] {
+	c.ctx = ctx
+
+	return func(yield func(string) bool) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		for s := range c.seq(true) {
+			if ctx.Err() != nil || !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// itemErr is an error paired with the item that caused it.
+type itemErr struct {
+	item string
+	err  error
+}
+
 // seq returns a sequence that yields munged strings using rules defined in the
 // receiver configuration [Config].
 func (c Config) seq(filter bool) iter.Seq[string] {
-	prev := memo[string]{}
+	// A compiled configuration keeps the caches prepared by [Config.Compile],
+	// so that they are shared by each call to [Munger.Apply].
+	if c.built == nil {
+		c = c.prepare()
+	}
+
+	c.observe = c.hooked()
+	c = c.materializeRules()
+
+	if !c.streams() {
+		c = c.materialize()
+	}
+
+	built := c.built
+	if built == nil {
+		built = c.compile()
+	}
+
+	// Rule matches are only tracked if unmatched rules can be reported.
+	var rules *ruleTracker
+	if (c.strict && c.onErr != nil) || c.report != nil {
+		rules = c.newRuleTracker()
+	}
+
+	// Replacement is applied after the rules below so that they always match
+	// against the original (unreplaced) items, unless reordered with
+	// [WithStageOrder].
+	replace := built.replace
+	if rules != nil {
+		replace = rules.replacing(replace)
+	}
+
+	c.view = c.viewer(replace)
+
+	if filter {
+		c.predicate = c.selector()
+	}
+
+	// Under [WithStageOrder], items may be removed before they are filtered, so
+	// that removed items are never passed to the predicate.
+	removeFirst := filter && c.before(StageRemove, StageFilter)
+
+	// yieldSeq yields the given items, which are already split and normalized.
 	yieldSeq := func(
-		seq []string, omit memo[string], yield func(string) bool,
+		itemSeq iter.Seq[string], scope Scope, remove set[string],
+		omit func(string) bool, prev set[string], yield func(string) bool,
 	) bool {
-		var itemSeq iter.Seq[string]
+		if removeFirst {
+			itemSeq = c.omitting(itemSeq, scope, remove, omit)
+			omit = func(string) bool { return false }
+		}
 
 		if filter {
 			// Every element must satisfy the predicate method [Config.filter]
-			itemSeq = c.filter(split(c.delim, seq))
-		} else {
-			itemSeq = split(c.delim, seq)
+			itemSeq = c.filter(itemSeq, scope)
+		}
+
+		for s := range itemSeq {
+			// Under [WithDedupeKeepLast], every occurrence is forwarded here and
+			// the duplicates are elided afterward by [keepLast].
+			// Under [WithAllowDuplicates], every occurrence is simply forwarded.
+			if !omit(s) &&
+				(c.dedupe != dedupeKeepFirst || !prev.Seen(s)) {
+				c.emit(event{op: passed(scope), item: s, source: scope})
+
+				if !yield(s) {
+					return false
+				}
+			} else if c.observe != nil {
+				c.observe(c.dropped(s, scope, remove))
+			}
+		}
+
+		return true
+	}
+
+	// [Prefix] documentation states that the trailing element leads the result.
+	// Since a Config's prefix elements can be modified multiple times,
+	// the ordering semantics must be applied after Config is finalized.
+	// The Config is finalized upon realizing the sequence here ([Config.seq]).
+	//
+	// Each element in prefix may already be delimited.
+	// This allows the user to override the reversal logic mentioned above.
+	// So we only want to reverse the order of the outer elements in prefix,
+	// while preserving the order of the inner, pre-delimited elements.
+	//
+	// Items yielded via yieldSeq are memoized to prevent duplicates.
+	// So the only items that need to be omitted are the ones
+	// that have not yet been (or never will be) yielded.
+	//
+	// When duplicates are allowed, suffix items are not moved out of the subject;
+	// the sources are simply concatenated.
+
+	var items iter.Seq[string] = func(yield func(string) bool) {
+		prev := c.newSet()
+
+		removePrefix := c.removing(built.removePrefix)
+		removeSubject := c.removing(built.removeSubject)
+		removeSuffix := c.removing(built.removeSuffix)
+
+		omitPrefix := removePrefix.Contains
+		omitSubject := removeSubject.Contains
+		omitSuffix := removeSuffix.Contains
+
+		if rules != nil {
+			omitPrefix = rules.removing(removePrefix, omitPrefix)
+			omitSubject = rules.removing(removeSubject, omitSubject)
+			omitSuffix = rules.removing(removeSuffix, omitSuffix)
+		}
+
+		prevPrefix := prev
+
+		// The subject is only memoized if it is not streamed, so it is split
+		// once into items that are both memoized and yielded.
+		subjectItems := c.limit(c.splitSeq(c.subjects()))
+
+		var subject set[string]
+
+		if c.prepend == PrependSkip || (c.dedupe == dedupeKeepLast &&
+			c.placing != MoveToSuffix) {
+			items := slices.Collect(subjectItems)
+			subjectItems = slices.Values(items)
+			subject = c.memoizeSeq(subjectItems)
+		}
+
+		switch c.prepend {
+		case PrependMove:
+			// Prefix items lead the result, and later occurrences are omitted.
+
+		case PrependSkip:
+			// Prefix items are not prepended if they remain in the subject.
+			removed, kept := omitPrefix, omitSubject
+			omitPrefix = func(s string) bool {
+				return removed(s) || (subject.Contains(s) && !kept(s))
+			}
+
+		case PrependDuplicate:
+			// Prefix items are not memoized with the subject and suffix items,
+			// so that later occurrences are yielded again.
+			if c.dedupe == dedupeKeepFirst {
+				prevPrefix = c.newSet()
+			}
+		}
+
+		switch {
+		case c.dedupe == dedupeNone:
+			// Duplicates are never reconciled, so neither position is omitted.
+
+		case c.placing == MoveToSuffix:
+			// Suffix items are moved out of the subject, unless they are removed
+			// from the suffix.
+			suffix, removed := built.suffix, omitSubject
+			omitSubject = func(s string) bool {
+				return removed(s) || (suffix.Contains(s) && !omitSuffix(s))
+			}
+
+		case c.dedupe == dedupeKeepLast:
+			// Subject items are omitted from the suffix, unless they are removed
+			// from the subject, so that they are not overridden by the later
+			// occurrence. Otherwise, the first occurrence is kept anyway.
+			removed := omitSuffix
+			omitSuffix = func(s string) bool {
+				return removed(s) || (subject.Contains(s) && !omitSubject(s))
+			}
+		}
+
+		if yieldSeq(c.split(Reverse(c.prefix)), ScopePrefix,
+			removePrefix, omitPrefix, prevPrefix, yield) {
+			if yieldSeq(subjectItems, ScopeSubject,
+				removeSubject, omitSubject, prev, yield) {
+				_ = yieldSeq(slices.Values(built.suffixItems), ScopeSuffix,
+					removeSuffix, omitSuffix, prev, yield)
+			}
+		}
+	}
+
+	stages := make([]Stage, 0, 6+len(c.stages)+len(c.after))
+
+	switch {
+	case c.dedupe != dedupeKeepLast:
+		// Duplicates are elided by yieldSeq, if at all.
+
+	case c.observe != nil:
+		stages = append(stages, c.keepLastObserved)
+
+	default:
+		stages = append(stages, func(items iter.Seq[string]) iter.Seq[string] {
+			return keepLast(items, c.newSet)
+		})
+	}
+
+	if c.observe != nil {
+		replace = c.observed(replace)
+	}
+
+	stages = append(stages, c.stagesAfter(StageDedup)...)
+	stages = append(stages, replaceStage(replace), c.rewriteStage())
+	stages = append(stages, c.stagesAfter(StageReplace)...)
+	stages = append(stages, c.orderStage())
+	stages = append(stages, c.ensureStage())
+	stages = append(append(stages, c.stages...), c.pinStage())
+	stages = append(stages, c.stagesAfter(StagePin)...)
+	items = pipe(items, stages...)
+
+	if rules == nil {
+		return items
+	}
+
+	return func(yield func(string) bool) {
+		for s := range items {
+			if !yield(s) {
+				return
+			}
+		}
+
+		report := c.unmatched(rules)
+		if c.report != nil {
+			*c.report = report
+		}
+
+		if c.strict && c.onErr != nil {
+			for _, u := range report.errs() {
+				c.onErr(u.item, u.err)
+			}
+		}
+	}
+}
+
+// removed returns the strings to remove from items in the given scope,
+// according to the scope set with [WithRemoveScope].
+func (c Config) removed(scope Scope) []string {
+	if !c.removeScope.has(scope) {
+		return nil
+	}
+
+	return c.remove
+}
+
+// prepare returns a copy of the receiver with any state used during a single
+// realization of the munged sequence initialized.
+func (c Config) prepare() Config {
+	return c.prepareLimit(0)
+}
+
+// prepareLimit is like [Config.prepare], but if limit is positive, the caches
+// of file system queries each hold at most limit paths, e.g., for a [Munger]
+// whose caches would otherwise grow with every subject it is applied to.
+func (c Config) prepareLimit(limit int) Config {
+	switch {
+	case c.links != symlinksNone && c.abs:
+		c.resolve = newSymlinkResolver(c.realpathFunc(), limit)
+	case c.links != symlinksNone:
+		c.resolve = newSymlinkResolver(c.evalFunc(), limit)
+	}
+
+	if c.sameFile || c.exist != existAny {
+		c.stat = newStatCache(c.statFunc(), limit)
+	}
+
+	return c
+}
+
+// selector returns the predicate used to select items during a single
+// realization of the munged sequence, or nil if every item is selected.
+//
+// The outcome of the user's predicate for each item is memoized in the
+// receiver's [FilterCache], or in a new cache if none was given.
+// Outcomes are not memoized if the predicate returns an error or if the
+// predicate's context is done.
+func (c Config) selector() func(string) bool {
+	cache := c.cache
+	if cache == nil {
+		cache = NewFilterCache()
+	}
+
+	if c.batch != nil {
+		return c.batchSelector(cache)
+	}
+
+	eval := c.evaluator()
+	if eval == nil {
+		return nil
+	}
+
+	// Under [WithParallelFilter], every candidate item is evaluated
+	// concurrently upon the first call, and the outcomes are then consumed in
+	// order as if they were evaluated serially.
+	if c.workers > 1 {
+		var (
+			serial  = eval
+			pending map[string]outcome
+		)
+
+		eval = func(s string) outcome {
+			if pending == nil {
+				pending = c.evalParallel(cache, serial)
+			}
+
+			if o, ok := pending[s]; ok {
+				return o
+			}
+
+			return serial(s)
+		}
+	}
+
+	return func(s string) bool {
+		if ok, found := cache.Load(s); found {
+			return ok
+		}
+
+		o := eval(s)
+		if o.err != nil && c.onErr != nil {
+			c.onErr(s, o.err)
+		}
+
+		if o.final {
+			cache.Store(s, o.selected)
+		}
+
+		return o.selected
+	}
+}
+
+// outcome is the result of evaluating the predicate for an item.
+type outcome struct {
+	selected bool
+	final    bool // whether the outcome may be memoized
+	err      error
+}
+
+// evaluator returns a function that evaluates the receiver's predicate for
+// an item, or nil if there is no predicate.
+func (c Config) evaluator() func(string) outcome {
+	switch {
+	case c.predicateCtx != nil:
+		ctx := c.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		return c.timed(func(s string) outcome {
+			if ctx.Err() != nil {
+				return outcome{}
+			}
+
+			ectx := ctx
+
+			if c.timeout > 0 {
+				var cancel context.CancelFunc
+
+				ectx, cancel = context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+			}
+
+			ok := c.predicateCtx(ectx, s)
+
+			if ctx.Err() == nil &&
+				errors.Is(ectx.Err(), context.DeadlineExceeded) {
+				return c.timedOut()
+			}
+
+			return outcome{selected: ok, final: ctx.Err() == nil}
+		})
+
+	case c.predicateErr != nil:
+		return c.timed(func(s string) outcome {
+			ok, err := c.predicateErr(s)
+			if err != nil {
+				return outcome{err: err}
+			}
+
+			return outcome{selected: ok, final: true}
+		})
+
+	case c.predicate != nil:
+		return c.timed(func(s string) outcome {
+			return outcome{selected: c.predicate(s), final: true}
+		})
+
+	default:
+		return nil
+	}
+}
+
+// timed returns a function that evaluates an item like eval, but abandons the
+// evaluation if it does not complete within the receiver's timeout, if any.
+//
+// An abandoned evaluation continues in the background until eval returns.
+func (c Config) timed(eval func(string) outcome) func(string) outcome {
+	if c.timeout <= 0 {
+		return eval
+	}
+
+	return func(s string) outcome {
+		done := make(chan outcome, 1)
+
+		go func() { done <- eval(s) }()
+
+		timer := time.NewTimer(c.timeout)
+		defer timer.Stop()
+
+		select {
+		case o := <-done:
+			return o
+		case <-timer.C:
+			return c.timedOut()
+		}
+	}
+}
+
+// timedOut returns the outcome of an evaluation that exceeded the receiver's
+// timeout. The outcome is never memoized.
+func (c Config) timedOut() outcome {
+	if c.timeoutErr {
+		return outcome{err: ErrFilterTimeout}
+	}
+
+	return outcome{}
+}
+
+// evalParallel evaluates every distinct candidate item that satisfies the
+// built-in filters and has no outcome in cache, using a bounded number of
+// concurrent workers.
+func (c Config) evalParallel(
+	cache FilterCache, eval func(string) outcome,
+) map[string]outcome {
+	items := c.candidates(cache)
+	outcomes := make([]outcome, len(items))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(c.workers, len(items)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				outcomes[i] = eval(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		next <- i
+	}
+
+	close(next)
+	wg.Wait()
+
+	pending := make(map[string]outcome, len(items))
+	for i, s := range items {
+		pending[s] = outcomes[i]
+	}
+
+	return pending
+}
+
+// candidates returns every distinct item split from the prefix, subject, and
+// suffix strings within the scope set with [WithFilterScope] that satisfies
+// the built-in filters and has no outcome in cache.
+func (c Config) candidates(cache FilterCache) []string {
+	items := []string{}
+	seen := Set[string]{}
+
+	var sources [][]string
+
+	if c.filterScope.has(ScopePrefix) {
+		sources = append(sources, c.prefix)
+	}
+
+	if c.filterScope.has(ScopeSubject) {
+		sources = append(sources, c.subject)
+	}
+
+	if c.filterScope.has(ScopeSuffix) {
+		sources = append(sources, c.suffix)
+	}
+
+	for s := range c.split(sources...) {
+		s = c.viewFor(StageFilter, s)
+		if _, found := cache.Load(s); !found && !seen.Seen(s) &&
+			c.exists(s) && !c.excluded(s) {
+			items = append(items, s)
+		}
+	}
+
+	return items
+}
+
+// batchSelector returns a predicate that selects the items selected by the
+// receiver's batch predicate function.
+//
+// The batch predicate is called once, when the returned predicate is first
+// called, with every distinct item that satisfies the built-in filters and
+// has no outcome in cache.
+func (c Config) batchSelector(cache FilterCache) func(string) bool {
+	evaluated := false
+
+	return func(s string) bool {
+		if !evaluated {
+			evaluated = true
+
+			items := c.candidates(cache)
+			if len(items) > 0 {
+				results := c.batch(items)
+				for i, s := range items {
+					cache.Store(s, i < len(results) && results[i])
+				}
+			}
+		}
+
+		ok, _ := cache.Load(s)
+
+		return ok
+	}
+}
+
+// split returns a sequence of the normalized items split from the given lists
+// by the receiver's [Tokenizer].
+//
+// If environment variable expansion is enabled, each string is expanded
+// before it is split.
+func (c Config) split(lists ...[]string) iter.Seq[string] {
+	return c.splitSeq(func(yield func(string) bool) {
+		for _, list := range lists {
+			for _, str := range list {
+				if !yield(str) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// splitSeq is like [Config.split] but splits each string in strs.
+func (c Config) splitSeq(strs iter.Seq[string]) iter.Seq[string] {
+	tok := c.Tokenizer()
+
+	return func(yield func(string) bool) {
+		for str := range strs {
+			for s := range tok.Split(c.expandEnv(str)) {
+				if c.trim && s != "" && strings.TrimSpace(s) == "" {
+					continue // skip items containing only whitespace
+				}
+
+				if !yield(c.normalize(s)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// normalize returns item s rewritten by the receiver's normalization rules.
+//
+// Unlike [Config.key], the normalized item replaces the original in output.
+func (c Config) normalize(s string) string {
+	if c.trim {
+		s = strings.TrimSpace(s)
+	}
+
+	if c.unicode == unicodeRewrite {
+		s = c.form.String(s)
+	}
+
+	if c.tilde {
+		s = expandTilde(s)
+	}
+
+	switch c.wsl {
+	case wslFromWindows:
+		s = WindowsToWSL(s)
+	case wslToWindows:
+		s = WSLToWindows(s)
+	}
+
+	if c.windows {
+		s = windowsPath(s, c.clean)
+	} else if c.clean {
+		s = filepath.Clean(s)
+	}
+
+	if c.links == symlinksRewrite && c.resolve != nil {
+		s = c.resolve(s)
+	}
+
+	if c.mapping != nil {
+		s = c.mapping(s)
+	}
+
+	return s
+}
+
+// key returns the string used to compare item s with other items.
+//
+// Items are considered equal if their keys are equal.
+func (c Config) key(s string) string {
+	if c.links == symlinksCompare && c.resolve != nil {
+		s = c.resolve(s)
+	}
+
+	if c.extended {
+		s = trimExtendedPrefix(s)
+	}
+
+	if c.unicode == unicodeCompare {
+		s = c.form.String(s)
+	}
+
+	if c.fold {
+		s = strings.ToLower(s)
+	}
+
+	if c.keyFunc != nil {
+		s = c.keyFunc(s)
+	}
+
+	return s
+}
+
+// keys returns a sequence that yields the key of each item in seq.
+func (c Config) keys(seq iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s := range seq {
+			if !yield(c.key(s)) {
+				return
+			}
+		}
+	}
+}
+
+// newSet returns an empty set of items compared using the receiver's
+// comparison rules.
+func (c Config) newSet() set[string] {
+	if c.linear() {
+		return &eqSet[string]{equal: c.equalKeys}
+	}
+
+	return keySet[string, string]{key: c.key, memo: Set[string]{}}
+}
+
+// linear reports whether items must be compared pairwise by linear search
+// instead of by hashing their keys.
+func (c Config) linear() bool { return c.equal != nil || c.sameFile }
+
+// equalKeys reports whether items a and b are equal.
+//
+// If same-file comparison is enabled, the items are equal if both can be
+// stat'ed and refer to the same file.
+// Otherwise, their keys are compared with the receiver's equality function,
+// if any, or with the == operator.
+func (c Config) equalKeys(a, b string) bool {
+	if c.sameFile {
+		if fa, err := c.statItem(a); err == nil {
+			if fb, err := c.statItem(b); err == nil && os.SameFile(fa, fb) {
+				return true
+			}
+		}
+	}
+
+	if c.equal == nil {
+		return c.key(a) == c.key(b)
+	}
+
+	return c.equal(c.key(a), c.key(b))
+}
+
+// memoize returns the set of all items split from the given lists.
+func (c Config) memoize(lists ...[]string) set[string] {
+	return c.memoizeSeq(c.split(lists...))
+}
+
+// memoizeSeq is like [Config.memoize] but returns a set of the items yielded
+// by items, which are already split and normalized.
+func (c Config) memoizeSeq(items iter.Seq[string]) set[string] {
+	if c.linear() {
+		s := c.newSet()
+		for item := range items {
+			s.Add(item)
 		}
 
-		for s := range itemSeq {
-			if !omit.contains(s) && !prev.seen(s) {
-				if r, ok := c.replace[s]; ok {
-					s = r
-				}
+		return s
+	}
 
-				if !yield(s) {
-					return false
+	return keySet[string, string]{key: c.key, memo: SetOf(c.keys(items))}
+}
+
+// replacer returns a function that reports the replacement of item s,
+// if any, by comparing s with the normalized items of the replacement rules.
+func (c Config) replacer() func(s string) (string, bool) {
+	// Rules are searched in sorted order so that the outcome is deterministic
+	// when multiple rules are equal to an item. The first such rule wins.
+	rules := slices.Sorted(maps.Keys(c.replace))
+
+	if c.linear() {
+		return func(s string) (string, bool) {
+			for _, from := range rules {
+				if c.equalKeys(c.normalize(c.expandEnv(from)), s) {
+					return c.expandEnv(c.replace[from]), true
 				}
 			}
+
+			return "", false
 		}
+	}
 
-		return true
+	keyed := make(map[string]string, len(rules))
+	for _, from := range rules {
+		if k := c.key(c.normalize(c.expandEnv(from))); !hasKey(keyed, k) {
+			keyed[k] = c.expandEnv(c.replace[from])
+		}
 	}
 
-	// [Prefix] documentation states that the trailing element leads the result.
-	// Since a Config's prefix elements can be modified multiple times,
-	// the ordering semantics must be applied after Config is finalized.
-	// The Config is finalized upon realizing the sequence here ([Config.seq]).
-	//
-	// Each element in prefix may already be delimited.
-	// This allows the user to override the reversal logic mentioned above.
-	// So we only want to reverse the order of the outer elements in prefix,
-	// while preserving the order of the inner, pre-delimited elements.
-	//
-	// Items yielded via yieldSeq are memoized to prevent duplicates.
-	// So the only items that need to be omitted are the ones
-	// that have not yet been (or never will be) yielded.
+	return func(s string) (string, bool) {
+		r, ok := keyed[c.key(s)]
 
-	return func(yield func(string) bool) {
-		if yieldSeq(reverse(c.prefix), memoize(split(c.delim, c.remove)), yield) {
-			if yieldSeq(
-				c.subject,
-				memoize(split(c.delim, c.remove, c.suffix)),
-				yield,
-			) {
-				_ = yieldSeq(c.suffix, memoize(split(c.delim, c.remove)), yield)
-			}
-		}
+		return r, ok
 	}
 }
 
 // filter returns a sequence that yields only the elements that satisfy the
-// predicate function [Config.Predicate].
-func (c Config) filter(seq iter.Seq[string]) iter.Seq[string] {
+// predicate function [Config.Predicate] and any built-in filters.
+//
+// Only the built-in filters are applied to items from sources outside the
+// scope set with [WithFilterScope].
+func (c Config) filter(seq iter.Seq[string], source Scope) iter.Seq[string] {
+	if !c.filterScope.has(source) {
+		c.predicate, c.exclude = nil, nil
+	}
+
 	// Fast-path instead of the default "accept-all" from [Config.Predicate],
 	// just return the given sequence unmodified.
 	//
 	// The sequence operations initialized in the receiver will still be applied
 	// in [Config.seq]. This just affects which elements actually reach those
 	// operations.
-	if c.predicate == nil {
+	if c.predicate == nil && c.exclude == nil && c.exist == existAny {
 		return seq // unfiltered
 	}
 
 	return func(yield func(string) bool) {
 		for s := range seq {
-			if c.predicate(s) && !yield(s) {
+			// Built-in filters are evaluated first, since they are usually cheaper
+			// than the user's predicate.
+			// Excluded items are never passed to the user's predicate.
+			if t := c.viewFor(StageFilter, s); !c.exists(t) || c.excluded(t) ||
+				(c.predicate != nil && !c.predicate(t)) {
+				c.emit(event{op: OpFilter, item: s, source: source})
+
+				continue
+			}
+
+			if !yield(s) {
 				return
 			}
 		}
 	}
 }
 
+// excluded returns true if and only if s satisfies the exclusion predicate
+// function set with [WithExclude].
+func (c Config) excluded(s string) bool {
+	return c.exclude != nil && c.exclude(s)
+}
+
 // WithSubject returns an option that sets all subject strings to be processed.
+//
+// The given slice is copied, so that later changes to it do not affect the
+// configuration. The same applies to [WithRemove], [WithPrefix], and
+// [WithSuffix].
 func WithSubject(subjects []string) Option[Config] {
 	return func(config Config) Config {
-		config.subject = subjects
+		config.subject = slices.Clone(subjects)
+		config.sources.subject = nil
 
 		return config
 	}
@@ -200,20 +1207,29 @@ func WithSubject(subjects []string) Option[Config] {
 // WithSubjectItems returns an option that adds subject strings to be processed.
 func WithSubjectItems(subjects ...string) Option[Config] {
 	return func(config Config) Config {
-		if config.subject == nil {
-			config.subject = make([]string, 0, len(subjects))
+		if config.sources.subject != nil {
+			config.sources.subject = appendSource(
+				config.sources.subject, slices.Clone(subjects),
+			)
+
+			return config
 		}
 
-		config.subject = append(config.subject, subjects...)
+		// Other copies of config may share the backing array of its subject,
+		// so append only writes into that array if no copy has done so.
+		config.subject = config.claims.subject.append(config.subject, subjects)
 
 		return config
 	}
 }
 
 // WithDelim returns an option that sets the string tokenizing delimiter.
+//
+// Any alternate delimiters set by [WithDelims] are cleared.
 func WithDelim(delim string) Option[Config] {
 	return func(config Config) Config {
 		config.delim = delim
+		config.alt = nil
 
 		return config
 	}
@@ -221,9 +1237,11 @@ func WithDelim(delim string) Option[Config] {
 
 // WithRemove returns an option that sets all strings to remove
 // during processing.
+// Use [WithRemoveScope] to remove items from only some sources.
 func WithRemove(removes []string) Option[Config] {
 	return func(config Config) Config {
-		config.remove = removes
+		config.remove = slices.Clone(removes)
+		config.sources.remove = nil
 
 		return config
 	}
@@ -233,11 +1251,15 @@ func WithRemove(removes []string) Option[Config] {
 // during processing.
 func WithRemoveItems(removes ...string) Option[Config] {
 	return func(config Config) Config {
-		if config.remove == nil {
-			config.remove = make([]string, 0, len(removes))
+		if config.sources.remove != nil {
+			config.sources.remove = appendSource(
+				config.sources.remove, slices.Clone(removes),
+			)
+
+			return config
 		}
 
-		config.remove = append(config.remove, removes...)
+		config.remove = config.claims.remove.append(config.remove, removes)
 
 		return config
 	}
@@ -251,9 +1273,10 @@ func WithRemoveItems(removes ...string) Option[Config] {
 // or, the leading argument is the first to be prepended.
 func WithPrefix(prefixes []string) Option[Config] {
 	return func(config Config) Config {
-		config.prefix = prefixes
+		config.prefix = slices.Clone(prefixes)
+		config.sources.prefix = nil
 
-		return config
+		return config.untagged(ScopePrefix)
 	}
 }
 
@@ -265,11 +1288,15 @@ func WithPrefix(prefixes []string) Option[Config] {
 // or, the leading argument is the first to be prepended.
 func WithPrefixItems(prefixes ...string) Option[Config] {
 	return func(config Config) Config {
-		if config.prefix == nil {
-			config.prefix = make([]string, 0, len(prefixes))
+		if config.sources.prefix != nil {
+			config.sources.prefix = appendSource(
+				config.sources.prefix, slices.Clone(prefixes),
+			)
+
+			return config
 		}
 
-		config.prefix = append(config.prefix, prefixes...)
+		config.prefix = config.claims.prefix.append(config.prefix, prefixes)
 
 		return config
 	}
@@ -283,9 +1310,10 @@ func WithPrefixItems(prefixes ...string) Option[Config] {
 // or, the leading argument is the first to be appended.
 func WithSuffix(suffixes []string) Option[Config] {
 	return func(config Config) Config {
-		config.suffix = suffixes
+		config.suffix = slices.Clone(suffixes)
+		config.sources.suffix = nil
 
-		return config
+		return config.untagged(ScopeSuffix)
 	}
 }
 
@@ -293,11 +1321,15 @@ func WithSuffix(suffixes []string) Option[Config] {
 // after processing.
 func WithSuffixItems(suffixes ...string) Option[Config] {
 	return func(config Config) Config {
-		if config.suffix == nil {
-			config.suffix = make([]string, 0, len(suffixes))
+		if config.sources.suffix != nil {
+			config.sources.suffix = appendSource(
+				config.sources.suffix, slices.Clone(suffixes),
+			)
+
+			return config
 		}
 
-		config.suffix = append(config.suffix, suffixes...)
+		config.suffix = config.claims.suffix.append(config.suffix, suffixes)
 
 		return config
 	}
@@ -317,9 +1349,7 @@ func WithReplace(replace map[string]string) Option[Config] {
 // substitution rule to apply after processing.
 func WithReplaceItem(from, to string) Option[Config] {
 	return func(config Config) Config {
-		if config.replace == nil {
-			config.replace = make(map[string]string)
-		}
+		config.replace = cloneReplace(config.replace)
 
 		config.replace[from] = to
 
@@ -327,6 +1357,16 @@ func WithReplaceItem(from, to string) Option[Config] {
 	}
 }
 
+// cloneReplace returns a copy of the replacement rules, to which rules can be
+// added without modifying those of other configurations sharing replace.
+func cloneReplace(replace map[string]string) map[string]string {
+	if replace == nil {
+		return make(map[string]string)
+	}
+
+	return maps.Clone(replace)
+}
+
 // WithReplaceEach returns an option that adds individual whole/fixed-string
 // substitution rules to apply after processing.
 //
@@ -334,9 +1374,7 @@ func WithReplaceItem(from, to string) Option[Config] {
 // the first string is the item to replace, and the second is the replacement.
 func WithReplaceEach(replacements iter.Seq2[string, string]) Option[Config] {
 	return func(config Config) Config {
-		if config.replace == nil {
-			config.replace = make(map[string]string)
-		}
+		config.replace = cloneReplace(config.replace)
 
 		maps.Insert(config.replace, replacements)
 
@@ -351,9 +1389,7 @@ func WithReplaceEach(replacements iter.Seq2[string, string]) Option[Config] {
 // each map's key is the item to replace, and the value is the replacement.
 func WithReplaceItems(replacements ...map[string]string) Option[Config] {
 	return func(config Config) Config {
-		if config.replace == nil {
-			config.replace = make(map[string]string)
-		}
+		config.replace = cloneReplace(config.replace)
 
 		for _, r := range replacements {
 			maps.Copy(config.replace, r)
@@ -365,9 +1401,335 @@ func WithReplaceItems(replacements ...map[string]string) Option[Config] {
 
 // WithFilter returns an option that sets the predicate function used to
 // select yielded strings.
+//
+// Any predicate function already set is replaced.
+// Use [WithFilterAnd] or [WithFilterOr] to combine predicate functions.
 func WithFilter(predicate func(string) bool) Option[Config] {
 	return func(config Config) Config {
 		config.predicate = predicate
+		config.predicateErr = nil
+		config.predicateCtx = nil
+		config.batch = nil
+
+		return config
+	}
+}
+
+// WithFilterErr is like [WithFilter] but sets a predicate function that may
+// fail to determine whether a string is selected.
+//
+// Strings for which the predicate returns an error are not selected.
+// Use [Config.FilteredErr] to receive the errors.
+func WithFilterErr(predicate func(string) (bool, error)) Option[Config] {
+	return func(config Config) Config {
+		config.predicate = nil
+		config.predicateErr = predicate
+		config.predicateCtx = nil
+		config.batch = nil
+
+		if predicate != nil {
+			config.predicate = func(s string) bool {
+				ok, err := predicate(s)
+
+				return ok && err == nil
+			}
+		}
+
+		return config
+	}
+}
+
+// WithFilterContext is like [WithFilter] but sets a predicate function that
+// receives a [context.Context], so that long-running predicates, e.g., network
+// checks, can be canceled or bounded by a deadline.
+//
+// The context is given by [Config.FilteredContext].
+// Otherwise, the predicate receives [context.Background].
+func WithFilterContext(
+	predicate func(context.Context, string) bool,
+) Option[Config] {
+	return func(config Config) Config {
+		config.predicate = nil
+		config.predicateErr = nil
+		config.predicateCtx = predicate
+		config.batch = nil
+
+		if predicate != nil {
+			config.predicate = func(s string) bool {
+				return predicate(context.Background(), s)
+			}
+		}
+
+		return config
+	}
+}
+
+// WithBatchFilter is like [WithFilter] but sets a predicate function that
+// selects strings in a single call, so that implementations can amortize
+// expensive setup, e.g., by spawning one process for all strings.
+//
+// The predicate receives every distinct item split from the prefix, subject,
+// and suffix strings that satisfies any built-in filters, and returns whether
+// each is selected, by index. Items without a corresponding result are not
+// selected.
+// The predicate is called at most once per call of [Config.Filtered],
+// and only once the sequence is realized.
+func WithBatchFilter(predicate func([]string) []bool) Option[Config] {
+	return func(config Config) Config {
+		config.predicate = nil
+		config.predicateErr = nil
+		config.predicateCtx = nil
+		config.batch = predicate
+
+		return config
+	}
+}
+
+// WithExclude returns an option that sets the predicate function used to
+// reject yielded strings.
+//
+// Strings that satisfy exclude are not selected, regardless of the predicate
+// function set with [WithFilter] or its variants, which is not called for
+// excluded strings. Like [WithFilter], exclusion only affects the filtered
+// sequences, e.g., [Config.Filtered].
+// Any exclusion predicate function already set is replaced.
+func WithExclude(exclude func(string) bool) Option[Config] {
+	return func(config Config) Config {
+		config.exclude = exclude
+
+		return config
+	}
+}
+
+// FilterCache stores the outcomes of a predicate function for each item.
+//
+// Implementations must be safe for concurrent use if the same FilterCache is
+// shared by configurations realized concurrently.
+type FilterCache interface {
+	// Load returns the outcome stored for item, if any.
+	Load(item string) (selected, ok bool)
+	// Store records the outcome for item.
+	Store(item string, selected bool)
+}
+
+// NewFilterCache returns an empty [FilterCache] that is safe for concurrent
+// use.
+func NewFilterCache() FilterCache { return &filterCache{m: map[string]bool{}} }
+
+// filterCache is the default [FilterCache], a mutex-guarded map.
+type filterCache struct {
+	mu sync.RWMutex
+	m  map[string]bool
+}
+
+func (f *filterCache) Load(item string) (selected, ok bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	selected, ok = f.m[item]
+
+	return selected, ok
+}
+
+func (f *filterCache) Store(item string, selected bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.m[item] = selected
+}
+
+// WithFilterCache returns an option that sets the cache used to memoize the
+// outcome of the predicate function for each item.
+//
+// By default, each item is evaluated by the predicate at most once per call
+// of [Config.Filtered], even if it appears in multiple sources.
+// A cache shared across calls, or across configurations using the same
+// predicate, avoids evaluating items again.
+// A nil cache restores the default.
+func WithFilterCache(cache FilterCache) Option[Config] {
+	return func(config Config) Config {
+		config.cache = cache
+
+		return config
+	}
+}
+
+// WithParallelFilter returns an option that evaluates the predicate function
+// for up to the given number of items concurrently.
+//
+// Items are still yielded in their original order, but every candidate item
+// is evaluated when the first item is selected, before any item is yielded.
+// Strings produced lazily, e.g., with [WithSubjectSeq], are collected before
+// any item is evaluated.
+// The predicate function must be safe for concurrent use.
+// A number less than 2 evaluates items serially (default).
+// Batch predicates set with [WithBatchFilter] are not affected.
+func WithParallelFilter(workers int) Option[Config] {
+	return func(config Config) Config {
+		config.workers = workers
+
+		return config
+	}
+}
+
+// ErrFilterTimeout is the error reported by [Config.FilteredErr] for items
+// whose predicate evaluation exceeded the duration set with
+// [WithFilterTimeoutErr].
+var ErrFilterTimeout = errors.New("filter timed out")
+
+// WithFilterTimeout returns an option that bounds the duration of each
+// evaluation of the predicate function. Items whose evaluation does not
+// complete in time are not selected.
+//
+// Predicates set with [WithFilterContext] receive a context with the
+// corresponding deadline. Other predicates are abandoned on timeout and
+// continue running in the background until they return.
+// A duration less than or equal to zero disables the timeout (default).
+func WithFilterTimeout(d time.Duration) Option[Config] {
+	return func(config Config) Config {
+		config.timeout, config.timeoutErr = d, false
+
+		return config
+	}
+}
+
+// WithFilterTimeoutErr is like [WithFilterTimeout] but reports each timeout as
+// [ErrFilterTimeout] by [Config.FilteredErr].
+func WithFilterTimeoutErr(d time.Duration) Option[Config] {
+	return func(config Config) Config {
+		config.timeout, config.timeoutErr = d, true
+
+		return config
+	}
+}
+
+// WithMap returns an option that sets the function used to rewrite every item
+// after splitting and before comparison and output.
+//
+// The mapping function is applied after all other item normalizations, e.g.,
+// [WithTrimSpace] and [WithCleanPaths].
+// Items of removal and replacement rules are rewritten the same way, but the
+// replacement strings themselves are not.
+// A nil mapping function leaves items unmodified.
+func WithMap(mapping func(string) string) Option[Config] {
+	return func(config Config) Config {
+		config.mapping = mapping
+
+		return config
+	}
+}
+
+// WithDedupeKeepLast returns an option that keeps the final occurrence of each
+// repeated item instead of the first.
+//
+// This models "later assignment overrides earlier" semantics.
+// Note that a prefix item which also appears in the subject or suffix
+// therefore loses its leading position to the later occurrence.
+//
+// The munged sequence is fully realized before the first item is yielded.
+func WithDedupeKeepLast() Option[Config] {
+	return func(config Config) Config {
+		config.dedupe = dedupeKeepLast
+
+		return config
+	}
+}
+
+// WithAllowDuplicates returns an option that disables deduplication entirely,
+// so that every occurrence of each item is yielded.
+//
+// This is useful for delimited values that are ordered multisets rather than
+// sets, such as repeated compiler flags.
+// Removal and replacement rules are still applied to every occurrence.
+func WithAllowDuplicates() Option[Config] {
+	return func(config Config) Config {
+		config.dedupe = dedupeNone
+
+		return config
+	}
+}
+
+// WithPositionPolicy returns an option that sets where an item that appears
+// in both the subject and suffix is yielded.
+//
+// By default, such items are moved to their position in the suffix.
+// The policy has no effect under [WithAllowDuplicates].
+func WithPositionPolicy(policy PositionPolicy) Option[Config] {
+	return func(config Config) Config {
+		config.placing = policy
+
+		return config
+	}
+}
+
+// WithPrependPolicy returns an option that sets how an item that appears
+// in both the prefix and subject is yielded.
+//
+// By default, such items are moved to their position in the prefix.
+// [PrependDuplicate] has no effect under [WithDedupeKeepLast], which keeps
+// only the final occurrence of each item.
+func WithPrependPolicy(policy PrependPolicy) Option[Config] {
+	return func(config Config) Config {
+		config.prepend = policy
+
+		return config
+	}
+}
+
+// WithCaseFold returns an option that compares items case-insensitively
+// when eliminating duplicates and matching removal and replacement rules.
+//
+// The original casing of each yielded item is preserved.
+func WithCaseFold() Option[Config] {
+	return func(config Config) Config {
+		config.fold = true
+
+		return config
+	}
+}
+
+// WithKeyFunc returns an option that sets the function used to derive the
+// comparison key of each item when eliminating duplicates and matching
+// removal and replacement rules. Items are equal if their keys are equal,
+// e.g., key may strip trailing slashes or resolve an item to its real path.
+//
+// The key function is applied to the key derived by the other comparison
+// settings, e.g., to the lower-cased item if [WithCaseFold] is also applied.
+// Yielded items are not modified; use [WithMap] to rewrite them instead.
+// A nil key function leaves keys unmodified.
+func WithKeyFunc(key func(string) string) Option[Config] {
+	return func(config Config) Config {
+		config.keyFunc = key
+
+		return config
+	}
+}
+
+// WithEqual returns an option that sets the function used to compare items
+// when eliminating duplicates and matching removal and replacement rules.
+//
+// The arguments given to equal are the items' comparison keys,
+// e.g., lower-cased if [WithCaseFold] is also applied, or as returned by the
+// function set with [WithKeyFunc].
+//
+// Since arbitrary equality functions cannot be hashed, each comparison is
+// made by linear search. Use [WithCaseFold] instead where it suffices.
+func WithEqual(equal func(a, b string) bool) Option[Config] {
+	return func(config Config) Config {
+		config.equal = equal
+
+		return config
+	}
+}
+
+// WithTrimSpace returns an option that removes leading and trailing white
+// space from every item before comparison and output.
+//
+// Items consisting only of white space are ignored.
+// Items of removal and replacement rules are trimmed the same way.
+func WithTrimSpace() Option[Config] {
+	return func(config Config) Config {
+		config.trim = true
 
 		return config
 	}
@@ -375,8 +1737,8 @@ func WithFilter(predicate func(string) bool) Option[Config] {
 
 // Reverse returns a copy of the given slice in reverse order.
 // The given slice is not modified.
-// Use [slices.reverse] to reverse a slice in-place.
-func reverse[T any](s []T) []T {
+// Use [slices.Reverse] to reverse a slice in-place.
+func Reverse[T any](s []T) []T {
 	r := make([]T, len(s))
 	for i := len(s) - 1; i >= 0; i-- {
 		r[len(s)-1-i] = s[i]
@@ -385,81 +1747,210 @@ func reverse[T any](s []T) []T {
 	return r
 }
 
-// Split returns a sequence of strings, split by the given delimiter,
+// SplitSeq returns a sequence of the non-empty items of each of the given
+// strings, split by delim, e.g., SplitSeq(":", "a::b", "c") yields "a", "b",
+// and "c". An empty delim splits each string into its UTF-8 sequences.
+//
+// Unlike the munged sequence of a [Config], the items are not otherwise
+// processed. Wrap the result in [Uniq] to elide duplicates.
+func SplitSeq(delim string, strs ...string) iter.Seq[string] {
+	return split(delim, strs)
+}
+
+// split returns a sequence of strings, split by the given delimiter,
 // from each of the given slices.
 //
-// Wrap the result in [unique] to elide duplicates.
+// Wrap the result in [Uniq] to elide duplicates.
 //
 // The given slices are not modified.
 func split(delim string, slices ...[]string) iter.Seq[string] {
 	return func(yield func(string) bool) {
 		for _, slice := range slices {
 			for _, str := range slice {
-				for s := range strings.SplitSeq(str, delim) {
-					if strings.ReplaceAll(s, delim, "") == "" { // skip empty elements
-						continue
-					}
-
-					if !yield(s) {
-						return
-					}
+				if !splitEach(str, delim, yield) {
+					return
 				}
 			}
 		}
 	}
 }
 
-func sumLen(each []string) int {
-	sum := 0
-	for _, s := range each {
-		sum += len(s)
+// splitEach calls yield with each non-empty item of s, split by delim,
+// returning false as soon as yield does. Items are substrings of s, so no
+// item is allocated.
+func splitEach(s, delim string, yield func(string) bool) bool {
+	if delim == "" {
+		for item := range strings.SplitSeq(s, "") {
+			if !yield(item) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for s != "" {
+		var item string
+
+		item, s, _ = strings.Cut(s, delim)
+		if item != "" && !yield(item) {
+			return false
+		}
 	}
 
-	return sum
+	return true
+}
+
+func hasKey[K comparable, V any](m map[K]V, key K) bool {
+	_, ok := m[key]
+
+	return ok
+}
+
+// set is a collection of distinct items.
+type set[T any] interface {
+	Contains(item T) bool
+	Add(item ...T)
+	Seen(item T) bool
 }
 
-type memo[T comparable] map[T]struct{}
+// Set is a collection of distinct comparable items, e.g., to track the items
+// already yielded by a sequence. The zero value is a nil map, so a Set must
+// be made with make, a composite literal, or [SetOf] before items are added.
+type Set[T comparable] map[T]struct{}
+
+// SetOf returns a new Set containing each item of the given sequence.
+// A nil sequence yields an empty Set.
+func SetOf[T comparable](items iter.Seq[T]) Set[T] {
+	m := Set[T]{}
+	if items != nil {
+		for item := range items {
+			m.Add(item)
+		}
+	}
+
+	return m
+}
 
-func (m memo[T]) contains(item T) bool {
+// Contains returns true if and only if item is in the receiver.
+func (m Set[T]) Contains(item T) bool {
 	_, ok := m[item]
 
 	return ok
 }
 
-func (m memo[T]) add(item ...T) {
+// Add adds each given item to the receiver.
+func (m Set[T]) Add(item ...T) {
 	for _, it := range item {
 		m[it] = struct{}{}
 	}
 }
 
-func (m memo[T]) seen(item T) bool {
-	if m.contains(item) {
+// Seen returns true if item is in the receiver; otherwise, it adds item to
+// the receiver and returns false, e.g., to filter duplicates from a sequence:
+//
+//	seen := mung.Set[string]{}
+//	for s := range items {
+//		if !seen.Seen(s) {
+//			fmt.Println(s)
+//		}
+//	}
+func (m Set[T]) Seen(item T) bool {
+	if m.Contains(item) {
 		return true
 	}
 
-	m.add(item)
+	m.Add(item)
 
 	return false
 }
 
-func memoize[T comparable](items iter.Seq[T]) memo[T] {
-	m := memo[T]{}
-	m.add(slices.Collect(uniq(items))...)
+// keySet is a set of items that are considered equal if their keys are equal.
+type keySet[T any, K comparable] struct {
+	key  func(T) K
+	memo Set[K]
+}
 
-	return m
+func (s keySet[T, K]) Contains(item T) bool {
+	return s.memo.Contains(s.key(item))
+}
+
+func (s keySet[T, K]) Add(item ...T) {
+	for _, it := range item {
+		s.memo.Add(s.key(it))
+	}
+}
+
+func (s keySet[T, K]) Seen(item T) bool { return s.memo.Seen(s.key(item)) }
+
+// eqSet is a set of items compared using an arbitrary equality function.
+// Membership is determined by linear search.
+type eqSet[T any] struct {
+	equal func(a, b T) bool
+	items []T
+}
+
+func (s *eqSet[T]) Contains(item T) bool {
+	return slices.ContainsFunc(s.items, func(it T) bool {
+		return s.equal(it, item)
+	})
+}
+
+func (s *eqSet[T]) Add(item ...T) {
+	for _, it := range item {
+		_ = s.Seen(it)
+	}
+}
+
+func (s *eqSet[T]) Seen(item T) bool {
+	if s.Contains(item) {
+		return true
+	}
+
+	s.items = append(s.items, item)
+
+	return false
+}
+
+// keepLast returns a sequence that yields only the final occurrence of each
+// item from the given sequence, preserving the relative order of survivors.
+// Items are compared using a new set returned from newSet.
+//
+// The given sequence is fully realized before the first item is yielded.
+func keepLast[T any](items iter.Seq[T], newSet func() set[T]) iter.Seq[T] {
+	if items == nil {
+		return func(func(T) bool) {}
+	}
+
+	return func(yield func(T) bool) {
+		all := slices.Collect(items)
+		keep := make([]bool, len(all))
+		prev := newSet()
+
+		for i := len(all) - 1; i >= 0; i-- {
+			keep[i] = !prev.Seen(all[i])
+		}
+
+		for i, item := range all {
+			if keep[i] && !yield(item) {
+				return
+			}
+		}
+	}
 }
 
-// uniq returns a sequence that yields only unique items
+// Uniq returns a sequence that yields only unique items
 // from the given sequence, preserving the order of first appearance.
-func uniq[T comparable](items iter.Seq[T]) iter.Seq[T] {
+// A nil sequence yields no items.
+func Uniq[T comparable](items iter.Seq[T]) iter.Seq[T] {
 	if items == nil {
 		return func(func(T) bool) {}
 	}
 
 	return func(yield func(T) bool) {
-		memo := memo[T]{}
+		memo := Set[T]{}
 		for item := range items {
-			if !memo.seen(item) {
+			if !memo.Seen(item) {
 				if !yield(item) {
 					return
 				}
@@ -1,10 +1,19 @@
 package mung
 
 import (
+	"context"
+	"errors"
 	"iter"
+	"maps"
 	"reflect"
+	"runtime"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // --- Option/Config construction tests ---
@@ -49,7 +58,7 @@ func TestMake(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := Make(tt.opts...)
-			if !configEqual(got, tt.want) {
+			if !got.Equal(tt.want) {
 				t.Errorf("Make() = %v, want %v", got, tt.want)
 			}
 		})
@@ -128,13 +137,333 @@ func TestWrap(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := Wrap(tt.init, tt.opts...)
-			if !configEqual(got, tt.expect) {
+			if !got.Equal(tt.expect) {
 				t.Errorf("Wrap() = %+v, want %+v", got, tt.expect)
 			}
 		})
 	}
 }
 
+func TestSliceOptionsCopy(t *testing.T) {
+	options := []struct {
+		name  string
+		set   func([]string) Option[Config]
+		add   func(...string) Option[Config]
+		slice func(Config) []string
+	}{
+		{"subject", WithSubject, WithSubjectItems, Config.Subject},
+		{"remove", WithRemove, WithRemoveItems, Config.Remove},
+		{"prefix", WithPrefix, WithPrefixItems, Config.Prefix},
+		{"suffix", WithSuffix, WithSuffixItems, Config.Suffix},
+	}
+
+	for _, o := range options {
+		t.Run(o.name, func(t *testing.T) {
+			arg := []string{"a", "b"}
+			base := Make(o.set(arg))
+
+			arg[0] = "x"
+			if got := o.slice(base); !slicesEqual(got, []string{"a", "b"}) {
+				t.Errorf("%s after caller mutation = %v, want [a b]", o.name, got)
+			}
+
+			// Appending to copies of base must not write into a shared array,
+			// even if it has spare capacity.
+			base = Wrap(base, o.add("c"))
+			one := Wrap(base, o.add("1"))
+			two := Wrap(base, o.add("2"))
+
+			if got := o.slice(one); !slicesEqual(got, []string{"a", "b", "c", "1"}) {
+				t.Errorf("%s of first copy = %v, want [a b c 1]", o.name, got)
+			}
+
+			if got := o.slice(two); !slicesEqual(got, []string{"a", "b", "c", "2"}) {
+				t.Errorf("%s of second copy = %v, want [a b c 2]", o.name, got)
+			}
+
+			if got := o.slice(base); !slicesEqual(got, []string{"a", "b", "c"}) {
+				t.Errorf("%s of base = %v, want [a b c]", o.name, got)
+			}
+		})
+	}
+}
+
+func TestSliceOptionsGrowth(t *testing.T) {
+	var (
+		config Config
+		arrays int
+		first  *string
+	)
+
+	for i := range 1000 {
+		config = Wrap(config, WithSubjectItems(strconv.Itoa(i)))
+		if p := &config.subject[0]; p != first {
+			first, arrays = p, arrays+1
+		}
+	}
+
+	// Appending one item at a time grows the array geometrically.
+	if arrays > 20 {
+		t.Errorf("subject reallocated %d times, want at most 20", arrays)
+	}
+
+	base := Wrap(config, WithSubjectItems("base"))
+	copies := make([]Config, 8)
+
+	var wg sync.WaitGroup
+
+	for i := range copies {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			copies[i] = Wrap(base, WithSubjectItems(strconv.Itoa(-i)))
+		}()
+	}
+
+	wg.Wait()
+
+	for i, c := range copies {
+		if got, want := c.subject[len(c.subject)-1], strconv.Itoa(-i); got != want {
+			t.Errorf("last subject of copy %d = %q, want %q", i, got, want)
+		}
+
+		if got := len(c.subject); got != len(base.subject)+1 {
+			t.Errorf("len(subject) of copy %d = %d, want %d",
+				i, got, len(base.subject)+1)
+		}
+	}
+}
+
+func TestReplaceOptionsSiblings(t *testing.T) {
+	base := Make(
+		WithSubjectItems("a:b:c"), WithDelim(":"), WithReplaceItem("a", "A"),
+	)
+
+	siblings := []Option[Config]{
+		WithReplaceItem("b", "B"),
+		WithReplaceEach(maps.All(map[string]string{"b": "B"})),
+		WithReplaceItems(map[string]string{"c": "C"}),
+	}
+
+	copies := make([]Config, len(siblings))
+
+	var wg sync.WaitGroup
+
+	for i, opt := range siblings {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			copies[i] = Wrap(base, opt)
+		}()
+	}
+
+	wg.Wait()
+
+	if got, want := base.String(), "A:b:c"; got != want {
+		t.Errorf("base String() = %q, want %q", got, want)
+	}
+
+	for i, want := range []string{"A:B:c", "A:B:c", "A:b:C"} {
+		if got := copies[i].String(); got != want {
+			t.Errorf("copy %d String() = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestAccessorsCopy(t *testing.T) {
+	accessors := []struct {
+		name  string
+		slice func(Config) []string
+	}{
+		{"Subject", Config.Subject},
+		{"Remove", Config.Remove},
+		{"Prefix", Config.Prefix},
+		{"Suffix", Config.Suffix},
+	}
+
+	config := Make(
+		WithSubject([]string{"a"}),
+		WithRemove([]string{"a"}),
+		WithPrefix([]string{"a"}),
+		WithSuffix([]string{"a"}),
+	)
+
+	for _, a := range accessors {
+		t.Run(a.name, func(t *testing.T) {
+			got := a.slice(config)
+			got[0] = "x"
+			_ = append(got[:0], "y")
+
+			if got := a.slice(config); !slicesEqual(got, []string{"a"}) {
+				t.Errorf("%s() after mutation = %v, want [a]", a.name, got)
+			}
+		})
+	}
+}
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option[Config]
+		want []string
+	}{
+		{
+			name: "compose_empty",
+			opt:  Compose[Config](),
+			want: []string{"a"},
+		},
+		{
+			name: "compose_order",
+			opt:  Compose(WithPrefixItems("p"), WithPrefix(nil), WithSuffixItems("s")),
+			want: []string{"a", "s"},
+		},
+		{
+			name: "when_true",
+			opt:  When(true, WithPrefixItems("p"), WithSuffixItems("s")),
+			want: []string{"p", "a", "s"},
+		},
+		{
+			name: "when_false",
+			opt:  When(false, WithPrefixItems("p"), WithSuffixItems("s")),
+			want: []string{"a"},
+		},
+		{
+			name: "unless_true",
+			opt:  Unless(true, WithPrefixItems("p")),
+			want: []string{"a"},
+		},
+		{
+			name: "unless_false",
+			opt:  Unless(false, WithPrefixItems("p")),
+			want: []string{"p", "a"},
+		},
+		{
+			name: "nested",
+			opt: Compose(
+				When(true, Unless(false, WithSuffixItems("s"))),
+				When(false, WithoutSuffix()),
+			),
+			want: []string{"a", "s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(WithSubject([]string{"a"}), tt.opt)
+
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryWrap(t *testing.T) {
+	errBad := errors.New("bad option")
+	fail := func(c Config) (Config, error) { return c, errBad }
+
+	tests := []struct {
+		name    string
+		init    Config
+		opts    []OptionE[Config]
+		expect  Config
+		wantErr error
+	}{
+		{
+			name:   "no_options",
+			init:   Config{delim: ":"},
+			expect: Config{delim: ":"},
+		},
+		{
+			name: "options",
+			init: Config{delim: ":"},
+			opts: []OptionE[Config]{
+				Try(WithSubjectItems("a"), WithPrefixItems("p")),
+				Try(WithSuffixItems("s")),
+			},
+			expect: Config{
+				delim:   ":",
+				subject: []string{"a"},
+				prefix:  []string{"p"},
+				suffix:  []string{"s"},
+			},
+		},
+		{
+			name: "stops_at_error",
+			init: Config{delim: ":"},
+			opts: []OptionE[Config]{
+				Try(WithSubjectItems("a")),
+				fail,
+				Try(WithSuffixItems("s")),
+			},
+			expect:  Config{delim: ":", subject: []string{"a"}},
+			wantErr: errBad,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TryWrap(tt.init, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TryWrap() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !got.Equal(tt.expect) {
+				t.Errorf("TryWrap() = %+v, want %+v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestTryMake(t *testing.T) {
+	got, err := TryMake(
+		Try(WithSubject([]string{"a,b;c"})),
+		WithDelimRegexp(`[,;]`),
+		Try(WithDelim(":")),
+	)
+	if err != nil {
+		t.Fatalf("TryMake() error = %v, want nil", err)
+	}
+
+	if s := got.String(); s != "a:b:c" {
+		t.Errorf("TryMake().String() = %q, want %q", s, "a:b:c")
+	}
+
+	got, err = TryMake(
+		Try(WithSubject([]string{"a,b;c"})),
+		WithDelimRegexp(`[,;`),
+	)
+	if err == nil {
+		t.Fatalf("TryMake() error = nil, want error")
+	}
+
+	if want := []string{"a,b;c"}; !slicesEqual(got.Subject(), want) {
+		t.Errorf("TryMake().Subject() = %v, want %v", got.Subject(), want)
+	}
+}
+
+func TestMustMake(t *testing.T) {
+	got := MustMake(Try(WithDelim(":")), WithDelimRegexp(`[,;]`))
+	if got.Delim() != ":" || got.pattern == nil {
+		t.Errorf("MustMake() = %+v, want delim and pattern set", got)
+	}
+
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "pattern") {
+			t.Errorf("MustMake() panic = %v, want delimiter pattern error", r)
+		}
+	}()
+
+	MustMake(WithDelimRegexp(`[,;`))
+	t.Errorf("MustMake() did not panic")
+}
+
 func TestCustomOptionType(t *testing.T) {
 	type CustomConfig struct {
 		Name  string
@@ -527,6 +856,31 @@ func TestConfigAllNilPredicate(t *testing.T) {
 	}
 }
 
+func TestConfigAll2(t *testing.T) {
+	c := Make(
+		WithSubjectItems("b", "a", "b", "c"), WithPrefixItems("p"),
+		WithFilter(func(s string) bool { return s != "a" }),
+	)
+
+	var (
+		indexes []int
+		items   []string
+	)
+
+	for i, s := range c.All2() {
+		indexes = append(indexes, i)
+		items = append(items, s)
+	}
+
+	if want := []int{0, 1, 2, 3}; !slices.Equal(indexes, want) {
+		t.Errorf("All2() indexes = %v, want %v", indexes, want)
+	}
+
+	if want := slices.Collect(c.All()); !slices.Equal(items, want) {
+		t.Errorf("All2() items = %q, want %q", items, want)
+	}
+}
+
 func TestConfigFilteredNilPredicate(t *testing.T) {
 	c := Config{subject: []string{"a", "b"}}
 	got := slices.Collect(c.Filtered())
@@ -601,7 +955,7 @@ func TestConfig_filter(t *testing.T) {
 						return
 					}
 				}
-			}))
+			}, ScopeSubject))
 			if !slicesEqual(got, tt.want) {
 				t.Errorf("filter() = %v, want %v", got, tt.want)
 			}
@@ -620,7 +974,7 @@ func TestConfig_filter(t *testing.T) {
 					return
 				}
 			}
-		})(func(s string) bool {
+		}, ScopeSubject)(func(s string) bool {
 			collected = append(collected, s)
 			return false // stop after first
 		})
@@ -1287,161 +1641,1475 @@ func TestWithPredicate(t *testing.T) {
 	}
 }
 
-// TestSplit tests the internal split function which is key to Config.Seq behavior
-func TestSplit(t *testing.T) {
+func TestWithFilterErr(t *testing.T) {
+	errBad := errors.New("bad item")
+	predicate := func(s string) (bool, error) {
+		switch s {
+		case "bad":
+			return false, errBad
+		case "worse":
+			return true, errBad
+		default:
+			return s != "skip", nil
+		}
+	}
+
+	type result struct {
+		item string
+		err  error
+	}
+
 	tests := []struct {
-		name  string
-		delim string
-		input []string
-		want  []string
+		name    string
+		initial Config
+		want    []result
 	}{
 		{
-			name:  "empty_input",
-			delim: ",",
-			input: []string{},
-			want:  []string{},
-		},
-		{
-			name:  "nil_input",
-			delim: ",",
-			input: nil,
-			want:  []string{},
-		},
-		{
-			name:  "single_item_no_delimiter",
-			delim: ",",
-			input: []string{"hello"},
-			want:  []string{"hello"},
-		},
-		{
-			name:  "single_item_with_delimiter",
-			delim: ",",
-			input: []string{"hello,world"},
-			want:  []string{"hello", "world"},
-		},
-		{
-			name:  "multiple_items_no_delimiters",
-			delim: ",",
-			input: []string{"hello", "world"},
-			want:  []string{"hello", "world"},
-		},
-		{
-			name:  "multiple_items_with_delimiters",
-			delim: ",",
-			input: []string{"hello,there", "world,wide,web"},
-			want:  []string{"hello", "there", "world", "wide", "web"},
-		},
-		{
-			name:  "empty_delimiter",
-			delim: "",
-			input: []string{"hello", "world"},
-			want:  []string{"h", "e", "l", "l", "o", "w", "o", "r", "l", "d"},
-		},
-		{
-			name:  "empty_elements_skipped",
-			delim: ",",
-			input: []string{"hello,,world", ",,,", "test"},
-			want:  []string{"hello", "world", "test"},
-		},
-		{
-			name:  "empty_strings_skipped",
-			delim: ",",
-			input: []string{"", "hello"},
-			want:  []string{"hello"},
+			name:    "no_errors",
+			initial: Config{subject: []string{"a,skip,b"}},
+			want:    []result{{"a", nil}, {"b", nil}},
 		},
 		{
-			name:  "only_delimiters_skipped",
-			delim: ",",
-			input: []string{",", ",,"},
-			want:  []string{},
+			name:    "errors_in_order",
+			initial: Config{subject: []string{"a,bad,b,worse"}},
+			want: []result{
+				{"a", nil}, {"bad", errBad}, {"b", nil}, {"worse", errBad},
+			},
 		},
 		{
-			name:  "duplicate_strings",
-			delim: ",",
-			input: []string{"hello", "hello", "world"},
-			want:  []string{"hello", "hello", "world"},
+			name: "prefix_and_suffix",
+			initial: Config{
+				subject: []string{"a"},
+				prefix:  []string{"bad"},
+				suffix:  []string{"b,worse"},
+			},
+			want: []result{
+				{"bad", errBad}, {"a", nil}, {"b", nil}, {"worse", errBad},
+			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := slices.Collect(split(tt.delim, tt.input))
+			config := Wrap(tt.initial, WithFilterErr(predicate), WithDelim(","))
+
+			var got []result
+			for s, err := range config.FilteredErr() {
+				got = append(got, result{s, err})
+			}
 			if !slicesEqual(got, tt.want) {
-				t.Errorf("split() = %v, want %v", got, tt.want)
+				t.Errorf("FilteredErr() = %v, want %v", got, tt.want)
+			}
+
+			// Filtered ignores errors and excludes the failed items.
+			var want []string
+			for _, r := range tt.want {
+				if r.err == nil {
+					want = append(want, r.item)
+				}
+			}
+			if got := slices.Collect(config.Filtered()); !slicesEqual(got, want) {
+				t.Errorf("Filtered() = %v, want %v", got, want)
 			}
 		})
 	}
 
-	// Test early termination
-	t.Run("early_termination", func(t *testing.T) {
-		result := []string{}
-		splitEach(",", "a,b,c,d,e")(func(s string) bool {
-			if s == "c" {
-				return false
+	t.Run("stop_on_error", func(t *testing.T) {
+		config := Make(WithSubjectItems("a,bad,b"), WithDelim(","), WithFilterErr(predicate))
+		var got []string
+		for s, err := range config.FilteredErr() {
+			if err != nil {
+				break
 			}
-			result = append(result, s)
-			return true
-		})
+			got = append(got, s)
+		}
+		if want := []string{"a"}; !slicesEqual(got, want) {
+			t.Errorf("FilteredErr() = %v, want %v", got, want)
+		}
+	})
 
-		want := []string{"a", "b"}
-		if !slicesEqual(result, want) {
-			t.Errorf("split() with early termination = %v, want %v", result, want)
+	t.Run("with_filter_replaces", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("a,bad"),
+			WithDelim(","),
+			WithFilterErr(predicate),
+			WithFilter(func(string) bool { return true }),
+		)
+		for s, err := range config.FilteredErr() {
+			if err != nil {
+				t.Errorf("FilteredErr() yielded (%q, %v), want no errors", s, err)
+			}
+		}
+	})
+
+	t.Run("nil_predicate", func(t *testing.T) {
+		config := Make(WithSubjectItems("a,b"), WithDelim(","), WithFilterErr(nil))
+		if config.predicate != nil || config.predicateErr != nil {
+			t.Errorf("WithFilterErr(nil) set a predicate")
 		}
 	})
 }
 
-func TestSplitEmptyDelimiterAndInput(t *testing.T) {
-	got := slices.Collect(split("", []string{}))
-	if len(got) != 0 {
-		t.Errorf("split(empty delimiter, empty input) = %v, want []", got)
+func TestWithFilterContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("context_passed", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "b")
+		config := Make(
+			WithSubjectItems("a,b,c"),
+			WithDelim(","),
+			WithFilterContext(func(ctx context.Context, s string) bool {
+				return ctx.Value(ctxKey{}) != s
+			}),
+		)
+		got := slices.Collect(config.FilteredContext(ctx))
+		if want := []string{"a", "c"}; !slicesEqual(got, want) {
+			t.Errorf("FilteredContext() = %v, want %v", got, want)
+		}
+		// Filtered uses the background context, which has no value.
+		got = slices.Collect(config.Filtered())
+		if want := []string{"a", "b", "c"}; !slicesEqual(got, want) {
+			t.Errorf("Filtered() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("cancel_stops_iteration", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var evaluated []string
+		config := Make(
+			WithSubjectItems("a,b,c,d"),
+			WithDelim(","),
+			WithFilterContext(func(_ context.Context, s string) bool {
+				evaluated = append(evaluated, s)
+				if s == "b" {
+					cancel()
+				}
+				return true
+			}),
+		)
+		got := slices.Collect(config.FilteredContext(ctx))
+		if want := []string{"a"}; !slicesEqual(got, want) {
+			t.Errorf("FilteredContext() = %v, want %v", got, want)
+		}
+		if want := []string{"a", "b"}; !slicesEqual(evaluated, want) {
+			t.Errorf("evaluated = %v, want %v", evaluated, want)
+		}
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("ctx.Err() = %v, want %v", ctx.Err(), context.Canceled)
+		}
+	})
+
+	t.Run("canceled_before_iteration", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		config := Make(WithSubjectItems("a,b"), WithDelim(","))
+		if got := slices.Collect(config.FilteredContext(ctx)); len(got) != 0 {
+			t.Errorf("FilteredContext() = %v, want []", got)
+		}
+	})
+
+	t.Run("plain_predicate", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("a,b"),
+			WithDelim(","),
+			WithFilterContext(func(context.Context, string) bool { return false }),
+			WithFilter(func(s string) bool { return s == "b" }),
+		)
+		got := slices.Collect(config.FilteredContext(context.Background()))
+		if want := []string{"b"}; !slicesEqual(got, want) {
+			t.Errorf("FilteredContext() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestWithBatchFilter(t *testing.T) {
+	var calls [][]string
+	predicate := func(items []string) []bool {
+		calls = append(calls, slices.Clone(items))
+		selected := make([]bool, len(items))
+		for i, s := range items {
+			selected[i] = s != "skip"
+		}
+		return selected
+	}
+
+	tests := []struct {
+		name      string
+		initial   Config
+		predicate func([]string) []bool
+		want      []string
+		calls     [][]string
+	}{
+		{
+			name: "single_call_distinct_items",
+			initial: Config{
+				subject: []string{"a,skip,b,a"},
+				prefix:  []string{"b"},
+				suffix:  []string{"skip,c"},
+			},
+			predicate: predicate,
+			want:      []string{"b", "a", "c"},
+			calls:     [][]string{{"b", "a", "skip", "c"}},
+		},
+		{
+			name:    "short_result",
+			initial: Config{subject: []string{"a,b,c"}},
+			predicate: func(items []string) []bool {
+				calls = append(calls, slices.Clone(items))
+				return []bool{true}
+			},
+			want:  []string{"a"},
+			calls: [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:      "empty",
+			initial:   Config{},
+			predicate: predicate,
+			want:      []string{},
+			calls:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			config := Wrap(tt.initial, WithBatchFilter(tt.predicate), WithDelim(","))
+			got := slices.Collect(config.Filtered())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Filtered() = %v, want %v", got, tt.want)
+			}
+			if len(calls) != len(tt.calls) {
+				t.Fatalf("predicate called %d times, want %d", len(calls), len(tt.calls))
+			}
+			for i := range calls {
+				if !slicesEqual(calls[i], tt.calls[i]) {
+					t.Errorf("predicate call %d = %v, want %v", i, calls[i], tt.calls[i])
+				}
+			}
+		})
+	}
+
+	t.Run("all_not_filtered", func(t *testing.T) {
+		calls = nil
+		config := Make(WithSubjectItems("a,skip"), WithDelim(","), WithBatchFilter(predicate))
+		got := slices.Collect(config.All())
+		if want := []string{"a", "skip"}; !slicesEqual(got, want) {
+			t.Errorf("All() = %v, want %v", got, want)
+		}
+		if len(calls) != 0 {
+			t.Errorf("predicate called %d times, want 0", len(calls))
+		}
+	})
+
+	t.Run("with_filter_replaces", func(t *testing.T) {
+		config := Make(
+			WithBatchFilter(predicate),
+			WithFilter(func(string) bool { return true }),
+		)
+		if config.batch != nil {
+			t.Errorf("WithFilter() did not clear the batch predicate")
+		}
+	})
+}
+
+func TestWithExclude(t *testing.T) {
+	hasA := func(s string) bool { return strings.Contains(s, "a") }
+	hasB := func(s string) bool { return strings.Contains(s, "b") }
+
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want []string
+	}{
+		{
+			name: "exclude",
+			opts: []Option[Config]{WithExclude(hasA)},
+			want: []string{"b", "c"},
+		},
+		{
+			name: "exclude_nil",
+			opts: []Option[Config]{WithExclude(nil)},
+			want: []string{"a", "b", "ab", "c"},
+		},
+		{
+			name: "exclude_replaces",
+			opts: []Option[Config]{WithExclude(hasA), WithExclude(hasB)},
+			want: []string{"a", "c"},
+		},
+		{
+			name: "exclude_and_filter",
+			opts: []Option[Config]{WithFilter(hasA), WithExclude(hasB)},
+			want: []string{"a"},
+		},
+		{
+			name: "filter_after_exclude",
+			opts: []Option[Config]{WithExclude(hasB), WithFilter(hasA)},
+			want: []string{"a"},
+		},
+		{
+			name: "exclude_and_batch",
+			opts: []Option[Config]{
+				WithExclude(hasB),
+				WithBatchFilter(func(items []string) []bool {
+					results := make([]bool, len(items))
+					for i := range results {
+						results[i] = true
+					}
+
+					return results
+				}),
+			},
+			want: []string{"a", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:ab:c"}),
+				WithDelim(":"),
+			}, tt.opts...)
+			config := Make(opts...)
+
+			if got := slices.Collect(config.Filtered()); !slicesEqual(got, tt.want) {
+				t.Errorf("Filtered() = %v, want %v", got, tt.want)
+			}
+
+			all := []string{"a", "b", "ab", "c"}
+			if got := slices.Collect(config.All()); !slicesEqual(got, all) {
+				t.Errorf("All() = %v, want %v", got, all)
+			}
+		})
+	}
+
+	t.Run("predicate_not_called", func(t *testing.T) {
+		var called []string
+
+		config := Make(
+			WithSubject([]string{"a:b:ab:c"}),
+			WithDelim(":"),
+			WithExclude(hasA),
+			WithFilter(func(s string) bool {
+				called = append(called, s)
+
+				return true
+			}),
+		)
+
+		_ = config.String()
+		if want := []string{"b", "c"}; !slicesEqual(called, want) {
+			t.Errorf("predicate called with %v, want %v", called, want)
+		}
+	})
+}
+
+func TestWithFilterCache(t *testing.T) {
+	var calls []string
+	predicate := func(s string) bool {
+		calls = append(calls, s)
+		return s != "skip"
+	}
+
+	t.Run("memoized_per_realization", func(t *testing.T) {
+		calls = nil
+		config := Make(
+			WithSubjectItems("a,skip,b,a"),
+			WithPrefixItems("b,skip"),
+			WithSuffixItems("a"),
+			WithDelim(","),
+			WithFilter(predicate),
+		)
+		got := slices.Collect(config.Filtered())
+		if want := []string{"b", "a"}; !slicesEqual(got, want) {
+			t.Errorf("Filtered() = %v, want %v", got, want)
+		}
+		if want := []string{"b", "skip", "a"}; !slicesEqual(calls, want) {
+			t.Errorf("predicate calls = %v, want %v", calls, want)
+		}
+		// Each realization starts with an empty cache by default.
+		calls = nil
+		_ = slices.Collect(config.Filtered())
+		if len(calls) != 3 {
+			t.Errorf("predicate calls = %v, want 3 calls", calls)
+		}
+	})
+
+	t.Run("shared_cache", func(t *testing.T) {
+		calls = nil
+		cache := NewFilterCache()
+		config := Make(
+			WithSubjectItems("a,skip"),
+			WithDelim(","),
+			WithFilter(predicate),
+			WithFilterCache(cache),
+		)
+		_ = slices.Collect(config.Filtered())
+		_ = slices.Collect(config.Filtered())
+		other := Wrap(config, WithSubjectItems("c"))
+		got := slices.Collect(other.Filtered())
+		if want := []string{"a", "c"}; !slicesEqual(got, want) {
+			t.Errorf("Filtered() = %v, want %v", got, want)
+		}
+		if want := []string{"a", "skip", "c"}; !slicesEqual(calls, want) {
+			t.Errorf("predicate calls = %v, want %v", calls, want)
+		}
+		if ok, found := cache.Load("skip"); ok || !found {
+			t.Errorf("Load(%q) = (%v, %v), want (false, true)", "skip", ok, found)
+		}
+	})
+
+	t.Run("errors_not_cached", func(t *testing.T) {
+		errBad := errors.New("bad")
+		cache := NewFilterCache()
+		config := Make(
+			WithSubjectItems("bad,a"),
+			WithDelim(","),
+			WithFilterErr(func(s string) (bool, error) {
+				if s == "bad" {
+					return false, errBad
+				}
+				return true, nil
+			}),
+			WithFilterCache(cache),
+		)
+		for range 2 {
+			var errs int
+			for _, err := range config.FilteredErr() {
+				if err != nil {
+					errs++
+				}
+			}
+			if errs != 1 {
+				t.Errorf("FilteredErr() yielded %d errors, want 1", errs)
+			}
+		}
+		if _, found := cache.Load("bad"); found {
+			t.Errorf("Load(%q) found an outcome, want none", "bad")
+		}
+	})
+
+	t.Run("canceled_not_cached", func(t *testing.T) {
+		cache := NewFilterCache()
+		ctx, cancel := context.WithCancel(context.Background())
+		config := Make(
+			WithSubjectItems("a,b"),
+			WithDelim(","),
+			WithFilterContext(func(_ context.Context, s string) bool {
+				if s == "b" {
+					cancel()
+				}
+				return true
+			}),
+			WithFilterCache(cache),
+		)
+		_ = slices.Collect(config.FilteredContext(ctx))
+		if _, found := cache.Load("a"); !found {
+			t.Errorf("Load(%q) found no outcome, want one", "a")
+		}
+		if _, found := cache.Load("b"); found {
+			t.Errorf("Load(%q) found an outcome, want none", "b")
+		}
+	})
+
+	t.Run("batch_skips_cached", func(t *testing.T) {
+		var batches [][]string
+		cache := NewFilterCache()
+		cache.Store("a", false)
+		config := Make(
+			WithSubjectItems("a,b,c"),
+			WithDelim(","),
+			WithBatchFilter(func(items []string) []bool {
+				batches = append(batches, slices.Clone(items))
+				return []bool{true, false}
+			}),
+			WithFilterCache(cache),
+		)
+		got := slices.Collect(config.Filtered())
+		if want := []string{"b"}; !slicesEqual(got, want) {
+			t.Errorf("Filtered() = %v, want %v", got, want)
+		}
+		_ = slices.Collect(config.Filtered())
+		if len(batches) != 1 || !slicesEqual(batches[0], []string{"b", "c"}) {
+			t.Errorf("batches = %v, want [[b c]]", batches)
+		}
+	})
+}
+
+func TestWithParallelFilter(t *testing.T) {
+	const n = 50
+
+	items := make([]string, n)
+	for i := range items {
+		items[i] = strconv.Itoa(i)
+	}
+
+	var (
+		mu      sync.Mutex
+		active  int
+		peak    int
+		calls   int
+		release = make(chan struct{})
+	)
+
+	predicate := func(s string) bool {
+		mu.Lock()
+		calls++
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+
+		<-release
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		i, _ := strconv.Atoi(s)
+		return i%2 == 0
+	}
+
+	config := Make(
+		WithSubjectItems(strings.Join(items, ",")),
+		WithSuffixItems("0,1"),
+		WithDelim(","),
+		WithFilter(predicate),
+		WithParallelFilter(4),
+	)
+
+	// Release the workers once all of them are blocked in the predicate.
+	go func() {
+		for {
+			mu.Lock()
+			full := active == 4
+			mu.Unlock()
+			if full {
+				close(release)
+				return
+			}
+			runtime.Gosched()
+		}
+	}()
+
+	got := slices.Collect(config.Filtered())
+
+	var want []string
+	for _, s := range items {
+		if i, _ := strconv.Atoi(s); i%2 == 0 && i != 0 {
+			want = append(want, s)
+		}
+	}
+	want = append(want, "0")
+
+	if !slicesEqual(got, want) {
+		t.Errorf("Filtered() = %v, want %v", got, want)
+	}
+	if peak != 4 {
+		t.Errorf("peak concurrency = %d, want 4", peak)
+	}
+	if calls != n {
+		t.Errorf("predicate calls = %d, want %d", calls, n)
+	}
+
+	t.Run("errors_in_order", func(t *testing.T) {
+		errOdd := errors.New("odd")
+		config := Make(
+			WithSubjectItems("1,2,3,4"),
+			WithDelim(","),
+			WithFilterErr(func(s string) (bool, error) {
+				if i, _ := strconv.Atoi(s); i%2 != 0 {
+					return false, errOdd
+				}
+				return true, nil
+			}),
+			WithParallelFilter(3),
+		)
+		var got []string
+		for s, err := range config.FilteredErr() {
+			if err != nil {
+				s = "!" + s
+			}
+			got = append(got, s)
+		}
+		if want := []string{"!1", "2", "!3", "4"}; !slicesEqual(got, want) {
+			t.Errorf("FilteredErr() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("serial", func(t *testing.T) {
+		var order []string
+		config := Make(
+			WithSubjectItems("c,b,a"),
+			WithDelim(","),
+			WithFilter(func(s string) bool { order = append(order, s); return true }),
+			WithParallelFilter(1),
+		)
+		_ = slices.Collect(config.Filtered())
+		if want := []string{"c", "b", "a"}; !slicesEqual(order, want) {
+			t.Errorf("predicate order = %v, want %v", order, want)
+		}
+	})
+}
+
+func TestWithParallelFilterStreamed(t *testing.T) {
+	for name, subject := range map[string]Option[Config]{
+		"seq":    WithSubjectSeq(slices.Values([]string{"a", "b"})),
+		"reader": WithSubjectReader(strings.NewReader("a:b")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var arrived atomic.Int32
+
+			both := make(chan struct{})
+
+			// Each item is selected only if both are evaluated concurrently.
+			predicate := func(string) bool {
+				if arrived.Add(1) == 2 {
+					close(both)
+				}
+
+				select {
+				case <-both:
+					return true
+				case <-time.After(5 * time.Second):
+					return false
+				}
+			}
+
+			config := Make(subject, WithDelim(":"),
+				WithFilter(predicate), WithParallelFilter(2))
+
+			if got, want := config.String(), "a:b"; got != want {
+				t.Errorf("String() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWithFilterTimeout(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+
+	block := make(chan struct{})
+	defer close(block)
+
+	predicate := func(s string) bool {
+		if s == "slow" {
+			<-block
+		}
+		return true
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("a,slow,b"),
+			WithDelim(","),
+			WithFilter(predicate),
+			WithFilterTimeout(timeout),
+		)
+		got := slices.Collect(config.Filtered())
+		if want := []string{"a", "b"}; !slicesEqual(got, want) {
+			t.Errorf("Filtered() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cache := NewFilterCache()
+		config := Make(
+			WithSubjectItems("a,slow,b"),
+			WithDelim(","),
+			WithFilter(predicate),
+			WithFilterTimeoutErr(timeout),
+			WithFilterCache(cache),
+		)
+		var got []string
+		for s, err := range config.FilteredErr() {
+			if errors.Is(err, ErrFilterTimeout) {
+				s = "!" + s
+			} else if err != nil {
+				t.Fatalf("FilteredErr() error = %v, want %v", err, ErrFilterTimeout)
+			}
+			got = append(got, s)
+		}
+		if want := []string{"a", "!slow", "b"}; !slicesEqual(got, want) {
+			t.Errorf("FilteredErr() = %v, want %v", got, want)
+		}
+		if _, found := cache.Load("slow"); found {
+			t.Errorf("Load(%q) found an outcome, want none", "slow")
+		}
+	})
+
+	t.Run("context_deadline", func(t *testing.T) {
+		var deadlines atomic.Int32
+		config := Make(
+			WithSubjectItems("a,slow"),
+			WithDelim(","),
+			WithFilterContext(func(ctx context.Context, s string) bool {
+				if _, ok := ctx.Deadline(); ok {
+					deadlines.Add(1)
+				}
+				if s == "slow" {
+					<-ctx.Done()
+				}
+				return true
+			}),
+			WithFilterTimeoutErr(timeout),
+		)
+		var got []string
+		for s, err := range config.FilteredErr() {
+			if err != nil {
+				s = "!" + s
+			}
+			got = append(got, s)
+		}
+		if want := []string{"a", "!slow"}; !slicesEqual(got, want) {
+			t.Errorf("FilteredErr() = %v, want %v", got, want)
+		}
+		if n := deadlines.Load(); n != 2 {
+			t.Errorf("predicate received %d deadlines, want 2", n)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		config := Make(WithFilterTimeout(timeout), WithFilterTimeout(0))
+		if config.timeout != 0 {
+			t.Errorf("timeout = %v, want 0", config.timeout)
+		}
+	})
+}
+
+func TestWithDedupeKeepLast(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		want    []string
+	}{
+		{
+			name:    "no_duplicates",
+			initial: Config{subject: []string{"a,b,c"}},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "last_occurrence_wins",
+			initial: Config{subject: []string{"a,b,a,c,b"}},
+			want:    []string{"a", "c", "b"},
+		},
+		{
+			name: "prefix_yields_to_subject",
+			initial: Config{
+				subject: []string{"a,b"},
+				prefix:  []string{"b,c"},
+			},
+			want: []string{"c", "a", "b"},
+		},
+		{
+			name: "suffix_still_trails",
+			initial: Config{
+				subject: []string{"a,b,c"},
+				suffix:  []string{"a"},
+			},
+			want: []string{"b", "c", "a"},
+		},
+		{
+			name: "remove_and_replace",
+			initial: Config{
+				subject: []string{"a,b,a,c"},
+				remove:  []string{"c"},
+				replace: map[string]string{"a": "A"},
+			},
+			want: []string{"b", "A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(","), WithDedupeKeepLast())
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithDedupeKeepLast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMap(t *testing.T) {
+	chroot := func(s string) string { return "/chroot" + s }
+
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		mapping func(string) string
+		want    []string
+	}{
+		{
+			name:    "lowercase_dedupe",
+			initial: Config{subject: []string{"/A:/a:/B"}},
+			mapping: strings.ToLower,
+			want:    []string{"/a", "/b"},
+		},
+		{
+			name: "remove_mapped",
+			initial: Config{
+				subject: []string{"/bin:/usr/bin"},
+				remove:  []string{"/bin"},
+			},
+			mapping: chroot,
+			want:    []string{"/chroot/usr/bin"},
+		},
+		{
+			name: "replace_mapped_not_result",
+			initial: Config{
+				subject: []string{"/bin"},
+				replace: map[string]string{"/bin": "/opt/bin"},
+			},
+			mapping: chroot,
+			want:    []string{"/opt/bin"},
+		},
+		{
+			name:    "after_clean",
+			initial: Config{subject: []string{"/usr//bin/"}},
+			opts:    []Option[Config]{WithCleanPaths()},
+			mapping: chroot,
+			want:    []string{"/chroot/usr/bin"},
+		},
+		{
+			name:    "nil_mapping",
+			initial: Config{subject: []string{"/A:/a"}},
+			want:    []string{"/A", "/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":"), WithMap(tt.mapping)}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithMap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAllowDuplicates(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		want    []string
+	}{
+		{
+			name:    "duplicates_preserved",
+			initial: Config{subject: []string{"-I,a,-I,b"}},
+			want:    []string{"-I", "a", "-I", "b"},
+		},
+		{
+			name: "sources_concatenated",
+			initial: Config{
+				subject: []string{"a,b"},
+				prefix:  []string{"b"},
+				suffix:  []string{"a"},
+			},
+			want: []string{"b", "a", "b", "a"},
+		},
+		{
+			name: "remove_and_replace_every_occurrence",
+			initial: Config{
+				subject: []string{"a,b,a,c,c"},
+				remove:  []string{"c"},
+				replace: map[string]string{"a": "A"},
+			},
+			want: []string{"A", "b", "A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(","), WithAllowDuplicates())
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithAllowDuplicates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPositionPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy PositionPolicy
+		opts   []Option[Config]
+		want   []string
+	}{
+		{
+			name: "default",
+			want: []string{"p", "a", "c", "b", "x"},
+		},
+		{
+			name:   "move_to_suffix",
+			policy: MoveToSuffix,
+			want:   []string{"p", "a", "c", "b", "x"},
+		},
+		{
+			name:   "keep_subject_position",
+			policy: KeepSubjectPosition,
+			want:   []string{"p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "keep_last_move_to_suffix",
+			policy: MoveToSuffix,
+			opts:   []Option[Config]{WithDedupeKeepLast()},
+			want:   []string{"p", "a", "c", "b", "x"},
+		},
+		{
+			name:   "keep_last_keep_subject_position",
+			policy: KeepSubjectPosition,
+			opts:   []Option[Config]{WithDedupeKeepLast()},
+			want:   []string{"p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "allow_duplicates",
+			policy: KeepSubjectPosition,
+			opts:   []Option[Config]{WithAllowDuplicates()},
+			want:   []string{"p", "a", "b", "c", "b", "x"},
+		},
+		{
+			name:   "keep_subject_position_removed_from_subject",
+			policy: KeepSubjectPosition,
+			opts: []Option[Config]{
+				WithRemove([]string{"b"}), WithRemoveScope(ScopeSubject),
+			},
+			want: []string{"p", "a", "c", "b", "x"},
+		},
+		{
+			name:   "keep_last_removed_from_subject",
+			policy: KeepSubjectPosition,
+			opts: []Option[Config]{
+				WithDedupeKeepLast(),
+				WithRemove([]string{"b"}), WithRemoveScope(ScopeSubject),
+			},
+			want: []string{"p", "a", "c", "b", "x"},
+		},
+		{
+			name:   "move_to_suffix_removed_from_suffix",
+			policy: MoveToSuffix,
+			opts: []Option[Config]{
+				WithRemove([]string{"b"}), WithRemoveScope(ScopeSuffix),
+			},
+			want: []string{"p", "a", "b", "c", "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithPrefix([]string{"p"}),
+				WithSuffix([]string{"b:x"}),
+				WithDelim(":"),
+				WithPositionPolicy(tt.policy),
+			}, tt.opts...)
+
+			got := slices.Collect(Make(opts...).All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPrependPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		policy PrependPolicy
+		opts   []Option[Config]
+		want   []string
+	}{
+		{
+			name: "default",
+			want: []string{"b", "p", "a", "c", "x"},
+		},
+		{
+			name:   "move",
+			policy: PrependMove,
+			want:   []string{"b", "p", "a", "c", "x"},
+		},
+		{
+			name:   "skip",
+			policy: PrependSkip,
+			want:   []string{"p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "duplicate",
+			policy: PrependDuplicate,
+			want:   []string{"b", "p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "duplicate_within_prefix",
+			prefix: "b:b:p:p",
+			policy: PrependDuplicate,
+			want:   []string{"b", "p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "skip_keep_last",
+			policy: PrependSkip,
+			opts:   []Option[Config]{WithDedupeKeepLast()},
+			want:   []string{"p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "duplicate_keep_last",
+			policy: PrependDuplicate,
+			opts:   []Option[Config]{WithDedupeKeepLast()},
+			want:   []string{"p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "skip_allow_duplicates",
+			policy: PrependSkip,
+			opts:   []Option[Config]{WithAllowDuplicates()},
+			want:   []string{"p", "a", "b", "c", "x"},
+		},
+		{
+			name:   "skip_removed_from_subject",
+			policy: PrependSkip,
+			opts: []Option[Config]{
+				WithRemove([]string{"b"}), WithRemoveScope(ScopeSubject),
+			},
+			want: []string{"b", "p", "a", "c", "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := tt.prefix
+			if prefix == "" {
+				prefix = "b:p"
+			}
+
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithPrefix([]string{prefix}),
+				WithSuffix([]string{"x"}),
+				WithDelim(":"),
+				WithPrependPolicy(tt.policy),
+			}, tt.opts...)
+
+			got := slices.Collect(Make(opts...).All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCaseFold(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		want    []string
+	}{
+		{
+			name:    "dedupe_preserves_first_casing",
+			initial: Config{subject: []string{`C:\Windows;c:\windows;C:\WINDOWS`}},
+			want:    []string{`C:\Windows`},
+		},
+		{
+			name: "remove_ignores_case",
+			initial: Config{
+				subject: []string{`C:\Windows;C:\Temp`},
+				remove:  []string{`c:\temp`},
+			},
+			want: []string{`C:\Windows`},
+		},
+		{
+			name: "replace_ignores_case",
+			initial: Config{
+				subject: []string{`C:\Windows;C:\Temp`},
+				replace: map[string]string{`c:\temp`: `D:\Temp`},
+			},
+			want: []string{`C:\Windows`, `D:\Temp`},
+		},
+		{
+			name: "replace_conflicting_rules_sorted",
+			initial: Config{
+				subject: []string{"abc"},
+				replace: map[string]string{"ABC": "upper", "abc": "lower"},
+			},
+			want: []string{"upper"},
+		},
+		{
+			name: "prefix_moves_subject_item",
+			initial: Config{
+				subject: []string{"a;B"},
+				prefix:  []string{"b"},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "suffix_moves_subject_item",
+			initial: Config{
+				subject: []string{"A;b"},
+				suffix:  []string{"a"},
+			},
+			want: []string{"b", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(";"), WithCaseFold())
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithCaseFold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("keep_last", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("a", "B", "A"),
+			WithCaseFold(),
+			WithDedupeKeepLast(),
+		)
+		got := slices.Collect(config.All())
+		if want := []string{"B", "A"}; !slicesEqual(got, want) {
+			t.Errorf("WithCaseFold() keep last = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestWithTrimSpace(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+	}{
+		{
+			name:    "dedupe_trimmed",
+			initial: Config{subject: []string{" /a : /b:/a\t:\n/b "}},
+			want:    []string{"/a", "/b"},
+		},
+		{
+			name:    "skip_blank_items",
+			initial: Config{subject: []string{"/a: :\t:/b"}},
+			want:    []string{"/a", "/b"},
+		},
+		{
+			name: "remove_and_replace_trimmed",
+			initial: Config{
+				subject: []string{" /a : /b : /c "},
+				remove:  []string{" /b"},
+				replace: map[string]string{"/c ": "/d"},
+			},
+			want: []string{"/a", "/d"},
+		},
+		{
+			name:    "quoted_empty_item",
+			initial: Config{subject: []string{`/a:"":/b`}},
+			opts:    []Option[Config]{WithQuotes()},
+			want:    []string{"/a", "", "/b"},
+		},
+		{
+			name:    "quoted_blank_item",
+			initial: Config{subject: []string{`/a:" ":/b`}},
+			opts:    []Option[Config]{WithQuotes()},
+			want:    []string{"/a", "/b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":"), WithTrimSpace()}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithTrimSpace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithEqual(t *testing.T) {
+	trimSlash := func(a, b string) bool {
+		return strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+	}
+
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+	}{
+		{
+			name:    "dedupe",
+			initial: Config{subject: []string{"/usr/bin/:/bin:/usr/bin"}},
+			want:    []string{"/usr/bin/", "/bin"},
+		},
+		{
+			name: "remove",
+			initial: Config{
+				subject: []string{"/usr/bin:/bin/"},
+				remove:  []string{"/bin"},
+			},
+			want: []string{"/usr/bin"},
+		},
+		{
+			name: "replace",
+			initial: Config{
+				subject: []string{"/usr/bin/:/bin"},
+				replace: map[string]string{"/usr/bin": "/opt/bin"},
+			},
+			want: []string{"/opt/bin", "/bin"},
+		},
+		{
+			name: "prefix_and_suffix",
+			initial: Config{
+				subject: []string{"/a/:/b/:/c/"},
+				prefix:  []string{"/b"},
+				suffix:  []string{"/a"},
+			},
+			want: []string{"/b", "/c/", "/a"},
+		},
+		{
+			name:    "keep_last",
+			initial: Config{subject: []string{"/a/:/b:/a"}},
+			opts:    []Option[Config]{WithDedupeKeepLast()},
+			want:    []string{"/b", "/a"},
+		},
+		{
+			name:    "with_case_fold",
+			initial: Config{subject: []string{"/A/:/a"}},
+			opts:    []Option[Config]{WithCaseFold()},
+			want:    []string{"/A/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":"), WithEqual(trimSlash)}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithEqual() = %v, want %v", got, tt.want)
+			}
+			// The sequence must be reusable.
+			seq := config.All()
+			_ = slices.Collect(seq)
+			if again := slices.Collect(seq); !slicesEqual(again, tt.want) {
+				t.Errorf("WithEqual() second realization = %v, want %v", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithKeyFunc(t *testing.T) {
+	trimSlash := func(s string) string { return strings.TrimRight(s, "/") }
+
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+	}{
+		{
+			name:    "dedupe",
+			initial: Config{subject: []string{"/usr/bin/:/bin:/usr/bin"}},
+			want:    []string{"/usr/bin/", "/bin"},
+		},
+		{
+			name: "remove",
+			initial: Config{
+				subject: []string{"/usr/bin:/bin/"},
+				remove:  []string{"/bin"},
+			},
+			want: []string{"/usr/bin"},
+		},
+		{
+			name: "replace",
+			initial: Config{
+				subject: []string{"/usr/bin/:/bin"},
+				replace: map[string]string{"/usr/bin": "/opt/bin"},
+			},
+			want: []string{"/opt/bin", "/bin"},
+		},
+		{
+			name: "prefix",
+			initial: Config{
+				subject: []string{"/a/:/b/"},
+				prefix:  []string{"/b"},
+			},
+			want: []string{"/b", "/a/"},
+		},
+		{
+			name:    "with_case_fold",
+			initial: Config{subject: []string{"/A/:/a"}},
+			opts:    []Option[Config]{WithCaseFold()},
+			want:    []string{"/A/"},
+		},
+		{
+			name:    "with_equal",
+			initial: Config{subject: []string{"/a/:/A"}},
+			opts: []Option[Config]{
+				WithEqual(strings.EqualFold),
+			},
+			want: []string{"/a/"},
+		},
+		{
+			name:    "nil",
+			initial: Config{subject: []string{"/a/:/a"}},
+			opts:    []Option[Config]{WithKeyFunc(nil)},
+			want:    []string{"/a/", "/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":"), WithKeyFunc(trimSlash)}, tt.opts...)
+			got := slices.Collect(Wrap(tt.initial, opts...).All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithKeyFunc() = %v, want %v", got, tt.want)
+			}
+		})
 	}
 }
 
-func TestSumLen(t *testing.T) {
+// TestSplit tests the internal split function which is key to Config.Seq behavior
+func TestSplit(t *testing.T) {
 	tests := []struct {
 		name  string
+		delim string
 		input []string
-		want  int
+		want  []string
 	}{
 		{
-			name:  "empty",
+			name:  "empty_input",
+			delim: ",",
 			input: []string{},
-			want:  0,
+			want:  []string{},
 		},
 		{
-			name:  "empty_string",
-			input: []string{""},
-			want:  0,
+			name:  "nil_input",
+			delim: ",",
+			input: nil,
+			want:  []string{},
 		},
 		{
-			name:  "single_string",
+			name:  "single_item_no_delimiter",
+			delim: ",",
 			input: []string{"hello"},
-			want:  5,
+			want:  []string{"hello"},
+		},
+		{
+			name:  "single_item_with_delimiter",
+			delim: ",",
+			input: []string{"hello,world"},
+			want:  []string{"hello", "world"},
+		},
+		{
+			name:  "multiple_items_no_delimiters",
+			delim: ",",
+			input: []string{"hello", "world"},
+			want:  []string{"hello", "world"},
+		},
+		{
+			name:  "multiple_items_with_delimiters",
+			delim: ",",
+			input: []string{"hello,there", "world,wide,web"},
+			want:  []string{"hello", "there", "world", "wide", "web"},
 		},
 		{
-			name:  "multiple_strings",
+			name:  "empty_delimiter",
+			delim: "",
 			input: []string{"hello", "world"},
-			want:  10,
+			want:  []string{"h", "e", "l", "l", "o", "w", "o", "r", "l", "d"},
+		},
+		{
+			name:  "empty_elements_skipped",
+			delim: ",",
+			input: []string{"hello,,world", ",,,", "test"},
+			want:  []string{"hello", "world", "test"},
+		},
+		{
+			name:  "empty_strings_skipped",
+			delim: ",",
+			input: []string{"", "hello"},
+			want:  []string{"hello"},
+		},
+		{
+			name:  "only_delimiters_skipped",
+			delim: ",",
+			input: []string{",", ",,"},
+			want:  []string{},
 		},
 		{
-			name:  "with_empty_strings",
-			input: []string{"", "hello", ""},
-			want:  5,
+			name:  "duplicate_strings",
+			delim: ",",
+			input: []string{"hello", "hello", "world"},
+			want:  []string{"hello", "hello", "world"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := sumLen(tt.input); got != tt.want {
-				t.Errorf("sumLen() = %v, want %v", got, tt.want)
+			got := slices.Collect(split(tt.delim, tt.input))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("split() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Test early termination
+	t.Run("early_termination", func(t *testing.T) {
+		result := []string{}
+		SplitSeq(",", "a,b,c,d,e")(func(s string) bool {
+			if s == "c" {
+				return false
+			}
+			result = append(result, s)
+			return true
+		})
+
+		want := []string{"a", "b"}
+		if !slicesEqual(result, want) {
+			t.Errorf("split() with early termination = %v, want %v", result, want)
+		}
+	})
+}
+
+func TestSplitSeq(t *testing.T) {
+	got := slices.Collect(SplitSeq(":", "a::b", "", ":", "c:a"))
+	if want := []string{"a", "b", "c", "a"}; !slicesEqual(got, want) {
+		t.Errorf("SplitSeq() = %v, want %v", got, want)
+	}
+
+	got = slices.Collect(Uniq(SplitSeq(":", "a::b", "c:a")))
+	if want := []string{"a", "b", "c"}; !slicesEqual(got, want) {
+		t.Errorf("Uniq(SplitSeq()) = %v, want %v", got, want)
+	}
+}
+
+func TestSplitAllocs(t *testing.T) {
+	allocs := func(str string) float64 {
+		return testing.AllocsPerRun(10, func() {
+			for s := range split("::", []string{str}) {
+				_ = s
 			}
 		})
 	}
+
+	small := allocs("a")
+	large := allocs(strings.Repeat("item::::", 1000))
+
+	if large > small {
+		t.Errorf("split() allocated %v times for 1000 items, want at most %v",
+			large, small)
+	}
 }
 
-func TestSumLenNilInput(t *testing.T) {
-	if sumLen(nil) != 0 {
-		t.Errorf("sumLen(nil) != 0")
+func TestSplitEmptyDelimiterAndInput(t *testing.T) {
+	got := slices.Collect(split("", []string{}))
+	if len(got) != 0 {
+		t.Errorf("split(empty delimiter, empty input) = %v, want []", got)
 	}
 }
 
@@ -1458,9 +3126,9 @@ func TestReverse(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := reverse(tt.in)
+			got := Reverse(tt.in)
 			if !slicesEqual(got, tt.want) {
-				t.Errorf("reverse(%v) = %v, want %v", tt.in, got, tt.want)
+				t.Errorf("Reverse(%v) = %v, want %v", tt.in, got, tt.want)
 			}
 			// Ensure input is not modified
 			if len(tt.in) > 1 && slicesEqual(tt.in, got) {
@@ -1520,9 +3188,9 @@ func TestUniq(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := slices.Collect(uniq(slices.Values(tt.input)))
+			got := slices.Collect(Uniq(slices.Values(tt.input)))
 			if !slicesEqual(got, tt.want) {
-				t.Errorf("uniq() = %v, want %v", got, tt.want)
+				t.Errorf("Uniq() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -1530,27 +3198,27 @@ func TestUniq(t *testing.T) {
 	t.Run("early_termination", func(t *testing.T) {
 		input := []string{"a", "b", "a", "c"}
 		collected := []string{}
-		uniq(slices.Values(input))(func(s string) bool {
+		Uniq(slices.Values(input))(func(s string) bool {
 			collected = append(collected, s)
 			return len(collected) < 2 // stop after two unique items
 		})
 		want := []string{"a", "b"}
 		if !slicesEqual(collected, want) {
-			t.Errorf("uniq() early termination = %v, want %v", collected, want)
+			t.Errorf("Uniq() early termination = %v, want %v", collected, want)
 		}
 	})
 }
 
 func TestUniqNilAndEarlyTermination(t *testing.T) {
 	// nil input
-	got := slices.Collect(uniq(nilSeq()))
+	got := slices.Collect(Uniq(nilSeq()))
 	if len(got) != 0 {
-		t.Errorf("uniq(nil) = %v, want []", got)
+		t.Errorf("Uniq(nil) = %v, want []", got)
 	}
 	// early termination
 	input := []string{"a", "b", "a", "c"}
 	collected := []string{}
-	uniq(slices.Values(input))(func(s string) bool {
+	Uniq(slices.Values(input))(func(s string) bool {
 		collected = append(collected, s)
 		return len(collected) < 1 // stop after one unique
 	})
@@ -1559,43 +3227,82 @@ func TestUniqNilAndEarlyTermination(t *testing.T) {
 	}
 }
 
-func TestMemoType(t *testing.T) {
+func TestKeepLast(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{"empty_input", []string{}, []string{}},
+		{"all_unique", []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{"all_duplicates", []string{"x", "x", "x"}, []string{"x"}},
+		{"interleaved", []string{"a", "b", "a", "c", "b"}, []string{"a", "c", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(keepLast(slices.Values(tt.input), newMemo))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("keepLast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("nil_input", func(t *testing.T) {
+		if got := slices.Collect(keepLast(nilSeq(), newMemo)); len(got) != 0 {
+			t.Errorf("keepLast(nil) = %v, want []", got)
+		}
+	})
+
+	t.Run("early_termination", func(t *testing.T) {
+		collected := []string{}
+		keepLast(slices.Values([]string{"a", "b", "a", "c"}), newMemo)(func(s string) bool {
+			collected = append(collected, s)
+			return false
+		})
+		if !slicesEqual(collected, []string{"b"}) {
+			t.Errorf("keepLast() early termination = %v, want [b]", collected)
+		}
+	})
+}
+
+func TestSet(t *testing.T) {
 	t.Run("empty_memo", func(t *testing.T) {
-		m := memo[string]{}
-		if m.contains("test") {
+		m := Set[string]{}
+		if m.Contains("test") {
 			t.Errorf("empty memo should not contain 'test'")
 		}
 	})
 
 	t.Run("add_and_contains", func(t *testing.T) {
-		m := memo[string]{}
-		m.add("test")
-		if !m.contains("test") {
+		m := Set[string]{}
+		m.Add("test")
+		if !m.Contains("test") {
 			t.Errorf("memo should contain 'test' after adding it")
 		}
 	})
 
 	t.Run("seen_first_call", func(t *testing.T) {
-		m := memo[string]{}
-		if m.seen("test") {
+		m := Set[string]{}
+		if m.Seen("test") {
 			t.Errorf("first call to seen() should return false")
 		}
-		if !m.contains("test") {
+		if !m.Contains("test") {
 			t.Errorf("memo should contain 'test' after seen() call")
 		}
 	})
 
 	t.Run("seen_second_call", func(t *testing.T) {
-		m := memo[string]{}
-		m.seen("test")
-		if !m.seen("test") {
+		m := Set[string]{}
+		m.Seen("test")
+		if !m.Seen("test") {
 			t.Errorf("second call to seen() should return true")
 		}
 	})
 
 	t.Run("memoizeItems_single_slice", func(t *testing.T) {
 		m := memoizeItems("a", "b", "c")
-		if !m.contains("a") || !m.contains("b") || !m.contains("c") {
+		if !m.Contains("a") || !m.Contains("b") || !m.Contains("c") {
 			t.Errorf("memoizeItems failed to properly initialize memo")
 		}
 	})
@@ -1625,6 +3332,50 @@ func TestMemoType(t *testing.T) {
 	})
 }
 
+func TestConfigMemoizeSeq(t *testing.T) {
+	for _, config := range []Config{
+		Make(WithCaseFold()),
+		Make(WithEqual(strings.EqualFold)),
+	} {
+		passes := 0
+		items := func(yield func(string) bool) {
+			passes++
+
+			for _, s := range []string{"a", "B", "A", "b", "c"} {
+				if !yield(s) {
+					return
+				}
+			}
+		}
+
+		m := config.memoizeSeq(items)
+		if passes != 1 {
+			t.Errorf("memoizeSeq() iterated %d times, want 1", passes)
+		}
+
+		for _, s := range []string{"A", "b", "C"} {
+			if !m.Contains(s) {
+				t.Errorf("memoizeSeq().Contains(%q) = false, want true", s)
+			}
+		}
+
+		if m.Contains("d") {
+			t.Errorf("memoizeSeq().Contains(%q) = true, want false", "d")
+		}
+	}
+}
+
+func TestSetOf(t *testing.T) {
+	m := SetOf(slices.Values([]int{3, 1, 3}))
+	if len(m) != 2 || !m.Contains(1) || !m.Contains(3) {
+		t.Errorf("SetOf() = %v, want {1, 3}", m)
+	}
+
+	if m := SetOf[string](nil); m == nil || len(m) != 0 {
+		t.Errorf("SetOf(nil) = %#v, want empty non-nil set", m)
+	}
+}
+
 func TestComplexWorkflow(t *testing.T) {
 	// Create a config with all options set
 	c := Make(
@@ -1733,34 +3484,10 @@ func mapsEqual[K, V comparable](a, b map[K]V) bool {
 	return true
 }
 
-func configEqual(a, b Config) bool {
-	if !slicesEqual(a.subject, b.subject) {
-		return false
-	}
-	if a.delim != b.delim {
-		return false
-	}
-	if !slicesEqual(a.remove, b.remove) {
-		return false
-	}
-	if !slicesEqual(a.prefix, b.prefix) {
-		return false
-	}
-	if !slicesEqual(a.suffix, b.suffix) {
-		return false
-	}
-	if (a.predicate == nil) != (b.predicate == nil) {
-		return false
-	}
-	return mapsEqual(a.replace, b.replace)
-}
-
 func nilSeq() iter.Seq[string] { return nil }
 
-func splitEach(delim string, strings ...string) iter.Seq[string] {
-	return split(delim, strings)
-}
+func newMemo() set[string] { return Set[string]{} }
 
-func memoizeItems[T comparable](items ...T) memo[T] {
-	return memoize(slices.Values(items))
+func memoizeItems[T comparable](items ...T) Set[T] {
+	return SetOf(slices.Values(items))
 }
@@ -0,0 +1,198 @@
+package mung
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// StageName identifies a built-in stage of the pipeline that produces the
+// munged sequence.
+type StageName int
+
+// Constant values of StageName.
+const (
+	// StageFilter selects items with the predicate function and any built-in
+	// filters, e.g., [WithFilter] and [WithExistingOnly].
+	StageFilter StageName = iota + 1
+	// StageRemove omits items matching the strings set with [WithRemove] or
+	// its variants.
+	StageRemove
+	// StageDedup eliminates duplicate items, e.g., [WithDedupeKeepLast].
+	StageDedup
+	// StageReplace replaces items matching the rules set with [WithReplace] or
+	// its variants.
+	StageReplace
+	// StageSort orders items, e.g., [WithPriority] and [WithSort].
+	StageSort
+	// StagePin moves items pinned with [WithPin].
+	StagePin
+)
+
+// defaultOrder is the order of the stages that can be reordered with
+// [WithStageOrder], unless reordered. Other stages cannot be reordered.
+var defaultOrder = []StageName{StageFilter, StageRemove, StageReplace}
+
+// String returns the name of the stage.
+func (n StageName) String() string {
+	switch n {
+	case StageFilter:
+		return "filter"
+	case StageRemove:
+		return "remove"
+	case StageDedup:
+		return "dedup"
+	case StageReplace:
+		return "replace"
+	case StageSort:
+		return "sort"
+	case StagePin:
+		return "pin"
+	default:
+		return fmt.Sprintf("StageName(%d)", int(n))
+	}
+}
+
+// WithStageOrder returns an option that sets the order in which items are
+// filtered, removed, and replaced, e.g., to match remove strings against
+// replaced items, so that removing "/new/bin" also removes each item replaced
+// with "/new/bin":
+//
+//	mung.WithStageOrder(mung.StageReplace, mung.StageRemove)
+//
+// The given stages are applied first, in order, followed by the stages not
+// given, in the default order: [StageFilter], [StageRemove], and then
+// [StageReplace]. Other, unknown, and repeated stages are ignored.
+//
+// The order only changes which string each stage inspects. Duplicates are
+// always eliminated by the original items, and replacement always changes
+// the yielded item after duplicates are eliminated. Items are filtered and
+// removed as if replaced when [StageReplace] precedes them, in which case the
+// predicate function and remove strings are matched against the replacement
+// of each item instead of the item itself.
+func WithStageOrder(order ...StageName) Option[Config] {
+	return func(config Config) Config {
+		config.order = nil
+
+		for _, n := range order {
+			if slices.Contains(defaultOrder, n) &&
+				!slices.Contains(config.order, n) {
+				config.order = append(config.order, n)
+			}
+		}
+
+		for _, n := range defaultOrder {
+			if !slices.Contains(config.order, n) {
+				config.order = append(config.order, n)
+			}
+		}
+
+		if slices.Equal(config.order, defaultOrder) {
+			config.order = nil
+		}
+
+		return config
+	}
+}
+
+// StageOrder returns the order in which items are filtered, removed, and
+// replaced, as set with [WithStageOrder].
+func (c Config) StageOrder() []StageName {
+	if c.order == nil {
+		return slices.Clone(defaultOrder)
+	}
+
+	return slices.Clone(c.order)
+}
+
+// before returns true if and only if stage a precedes stage b in the
+// receiver's stage order.
+func (c Config) before(a, b StageName) bool {
+	order := c.order
+	if order == nil {
+		order = defaultOrder
+	}
+
+	return slices.Index(order, a) < slices.Index(order, b)
+}
+
+// viewer returns a function that returns the normalized replacement of an
+// item, or the item itself if it is not replaced, for use by the receiver's
+// remove and filter stages, or nil if neither follows [StageReplace].
+func (c Config) viewer(
+	replace func(string) (string, bool),
+) func(string) string {
+	if len(c.replace) == 0 || (!c.before(StageReplace, StageRemove) &&
+		!c.before(StageReplace, StageFilter)) {
+		return nil
+	}
+
+	return func(s string) string {
+		if r, ok := replace(s); ok {
+			return c.normalize(r)
+		}
+
+		return s
+	}
+}
+
+// viewFor returns the string inspected by the given stage for item s, which
+// is the replacement of s if stage follows [StageReplace].
+func (c Config) viewFor(stage StageName, s string) string {
+	if c.view == nil || !c.before(StageReplace, stage) {
+		return s
+	}
+
+	return c.view(s)
+}
+
+// viewSet is a set of items whose membership is tested by the view of each
+// item instead of the item itself.
+type viewSet struct {
+	set[string]
+
+	view func(string) string
+}
+
+// Contains returns true if and only if the view of item is in the set.
+func (s viewSet) Contains(item string) bool {
+	return s.set.Contains(s.view(item))
+}
+
+// viewOf returns the string matched against remove for item s.
+func viewOf(remove set[string], s string) string {
+	if v, ok := remove.(viewSet); ok {
+		return v.view(s)
+	}
+
+	return s
+}
+
+// removing returns remove, or a set matching the items of remove against the
+// replacement of each item if [StageReplace] precedes [StageRemove].
+func (c Config) removing(remove set[string]) set[string] {
+	if c.view == nil || !c.before(StageReplace, StageRemove) {
+		return remove
+	}
+
+	return viewSet{set: remove, view: c.view}
+}
+
+// omitting returns a sequence that yields the items of seq that are not
+// omitted, reporting each omitted item from source to the receiver's observer.
+func (c Config) omitting(
+	seq iter.Seq[string], source Scope, remove set[string],
+	omit func(string) bool,
+) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s := range seq {
+			if !omit(s) {
+				if !yield(s) {
+					return
+				}
+			} else if c.observe != nil {
+				c.observe(c.dropped(s, source, remove))
+			}
+		}
+	}
+}
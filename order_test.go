@@ -0,0 +1,162 @@
+package mung
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWithStageOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+	}{
+		{
+			name: "default",
+			want: "/a:/new/bin:/b",
+		},
+		{
+			name: "replace_before_remove",
+			opts: []Option[Config]{WithStageOrder(StageReplace, StageRemove)},
+			want: "/a:/b",
+		},
+		{
+			name: "replace_before_filter",
+			opts: []Option[Config]{
+				WithStageOrder(StageReplace),
+				WithFilter(func(s string) bool { return s != "/new/bin" }),
+				WithRemove(nil),
+			},
+			want: "/a:/b",
+		},
+		{
+			name: "filter_before_replace",
+			opts: []Option[Config]{
+				WithFilter(func(s string) bool { return s != "/new/bin" }),
+				WithRemove(nil),
+			},
+			want: "/a:/new/bin:/b",
+		},
+		{
+			name: "default_order_given",
+			opts: []Option[Config]{
+				WithStageOrder(StageFilter, StageRemove, StageReplace),
+			},
+			want: "/a:/new/bin:/b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubjectItems("/a", "/old/bin", "/b"), WithDelim(":"),
+				WithReplaceItem("/old/bin", "/new/bin"), WithRemoveItems("/new/bin"),
+			}, tt.opts...)
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithStageOrderRemoveFirst(t *testing.T) {
+	var called []string
+
+	opts := []Option[Config]{
+		WithSubjectItems("/a", "/b", "/c"), WithDelim(":"),
+		WithRemoveItems("/b"),
+		WithFilter(func(s string) bool {
+			called = append(called, s)
+
+			return true
+		}),
+	}
+
+	if got := Make(opts...).String(); got != "/a:/c" {
+		t.Errorf("String() = %q, want %q", got, "/a:/c")
+	}
+
+	if want := []string{"/a", "/b", "/c"}; !slices.Equal(called, want) {
+		t.Errorf("default: predicate called with %q, want %q", called, want)
+	}
+
+	called = nil
+	config := Make(append(opts, WithStageOrder(StageRemove))...)
+
+	if got := config.String(); got != "/a:/c" {
+		t.Errorf("String() = %q, want %q", got, "/a:/c")
+	}
+
+	if want := []string{"/a", "/c"}; !slices.Equal(called, want) {
+		t.Errorf("remove first: predicate called with %q, want %q", called, want)
+	}
+}
+
+func TestWithStageOrderRules(t *testing.T) {
+	var rules []string
+
+	config := Make(
+		WithSubjectItems("/a", "/old/bin"), WithDelim(":"),
+		WithReplaceItem("/old/bin", "/new/bin/"), WithRemoveItems("/new/bin"),
+		WithCleanPaths(), WithStageOrder(StageReplace),
+		WithOnRemove(func(item, rule string) {
+			rules = append(rules, item+"="+rule)
+		}),
+	)
+
+	got, report := config.StringReport()
+	if got != "/a" {
+		t.Errorf("String() = %q, want %q", got, "/a")
+	}
+
+	if len(report.Remove) != 0 || len(report.Replace) != 0 {
+		t.Errorf("StringReport() report = %+v, want none unmatched", report)
+	}
+
+	if want := []string{"/old/bin=/new/bin"}; !slices.Equal(rules, want) {
+		t.Errorf("OnRemove() called with %q, want %q", rules, want)
+	}
+}
+
+func TestStageOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []StageName
+		want  []StageName
+	}{
+		{
+			name: "default",
+			want: []StageName{StageFilter, StageRemove, StageReplace},
+		},
+		{
+			name:  "partial",
+			order: []StageName{StageReplace},
+			want:  []StageName{StageReplace, StageFilter, StageRemove},
+		},
+		{
+			name:  "repeated_and_unknown",
+			order: []StageName{StageRemove, 0, StageRemove, StageName(9)},
+			want:  []StageName{StageRemove, StageFilter, StageReplace},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(WithStageOrder(tt.order...))
+
+			if got := config.StageOrder(); !slices.Equal(got, tt.want) {
+				t.Errorf("StageOrder() = %v, want %v", got, tt.want)
+			}
+
+			if got := config.Equal(Config{}); got != (tt.name == "default") {
+				t.Errorf("Equal(Config{}) = %v", got)
+			}
+		})
+	}
+
+	if got := StageName(9).String(); !strings.HasPrefix(got, "StageName(") {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,51 @@
+package mung
+
+import (
+	"io"
+	"sync"
+)
+
+// AppendTo appends the munged strings joined like [Config.String] to dst and
+// returns the extended buffer, e.g., to reuse a buffer across calls instead
+// of allocating a new string each time.
+func (c Config) AppendTo(dst []byte) []byte {
+	d, ok := c.Tokenizer().(DelimTokenizer)
+	if !ok {
+		return append(dst, c.tok.Join(c.quoted(c.Filtered()))...)
+	}
+
+	d.join(func(s string) { dst = append(dst, s...) }, c.quoted(c.Filtered()))
+
+	return dst
+}
+
+// CollectInto appends the munged items yielded by [Config.Filtered] to dst
+// and returns the extended slice, e.g., to reuse a backing array across
+// calls instead of allocating a new slice with [slices.Collect] each time.
+func (c Config) CollectInto(dst []string) []string {
+	for s := range c.Filtered() {
+		dst = append(dst, s)
+	}
+
+	return dst
+}
+
+// outputPool holds buffers reused by [Config.WriteTo].
+var outputPool = sync.Pool{New: func() any { return new([]byte) }}
+
+// WriteTo implements [io.WriterTo], writing the munged strings joined like
+// [Config.String] to w with a single call to its Write method.
+// It returns the number of bytes written and any error encountered.
+func (c Config) WriteTo(w io.Writer) (int64, error) {
+	buf, _ := outputPool.Get().(*[]byte)
+
+	*buf = c.AppendTo((*buf)[:0])
+	n, err := w.Write(*buf)
+
+	// Very large buffers are not reused, as by [DelimTokenizer.Join].
+	if cap(*buf) <= maxPooledJoin {
+		outputPool.Put(buf)
+	}
+
+	return int64(n), err
+}
@@ -0,0 +1,144 @@
+package mung
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// upperTokenizer is a [Tokenizer] that joins items in upper case.
+type upperTokenizer struct{ DelimTokenizer }
+
+func (u upperTokenizer) Join(items iter.Seq[string]) string {
+	return strings.ToUpper(u.DelimTokenizer.Join(items))
+}
+
+func TestConfigAppendTo(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		dst  string
+	}{
+		{name: "empty"},
+		{
+			name: "items",
+			opts: []Option[Config]{WithSubject([]string{"a:b:a"}), WithDelim(":")},
+		},
+		{
+			name: "prefix",
+			opts: []Option[Config]{WithSubject([]string{"a:b"}), WithDelim(":")},
+			dst:  "PATH=",
+		},
+		{
+			name: "output_delim",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b"}), WithDelim(":"), WithOutputDelim(";"),
+			},
+		},
+		{
+			name: "leading_empty",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b"}), WithDelim(":"),
+				WithReplaceItem("a", ""),
+			},
+			dst: "x",
+		},
+		{
+			name: "tokenizer",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b"}),
+				WithTokenizer(upperTokenizer{DelimTokenizer{Delim: ":"}}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(tt.opts...)
+			want := tt.dst + config.String()
+
+			if got := config.AppendTo([]byte(tt.dst)); string(got) != want {
+				t.Errorf("AppendTo(%q) = %q, want %q", tt.dst, got, want)
+			}
+
+			var buf bytes.Buffer
+
+			n, err := config.WriteTo(&buf)
+			if err != nil || n != int64(len(config.String())) {
+				t.Errorf("WriteTo() = %d, %v, want %d, nil",
+					n, err, len(config.String()))
+			}
+
+			if got := buf.String(); got != config.String() {
+				t.Errorf("WriteTo() wrote %q, want %q", got, config.String())
+			}
+		})
+	}
+}
+
+// errWriter is an [io.Writer] that accepts at most n bytes.
+type errWriter struct{ n int }
+
+func (w errWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return w.n, io.ErrShortWrite
+	}
+
+	return len(p), nil
+}
+
+func TestConfigWriteToError(t *testing.T) {
+	config := Make(WithSubject([]string{"a:b:c"}), WithDelim(":"))
+
+	var _ io.WriterTo = config
+
+	n, err := config.WriteTo(errWriter{n: 2})
+	if n != 2 || !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("WriteTo() = %d, %v, want 2, %v", n, err, io.ErrShortWrite)
+	}
+}
+
+func TestConfigCollectInto(t *testing.T) {
+	config := Make(
+		WithSubject([]string{"a:b:a:c"}), WithDelim(":"), WithRemoveItems("c"),
+		WithFilter(func(s string) bool { return s != "b" }),
+	)
+
+	buf := make([]string, 1, 8)
+	buf[0] = "x"
+
+	got := config.CollectInto(buf)
+	if want := []string{"x", "a"}; !slicesEqual(got, want) {
+		t.Errorf("CollectInto() = %q, want %q", got, want)
+	}
+
+	if &got[0] != &buf[0] {
+		t.Error("CollectInto() reallocated a slice with enough capacity")
+	}
+
+	if got := config.CollectInto(nil); !slicesEqual(got, []string{"a"}) {
+		t.Errorf("CollectInto(nil) = %q, want %q", got, []string{"a"})
+	}
+
+	if got := Make(WithDelim(":")).CollectInto(buf[:0]); len(got) != 0 {
+		t.Errorf("CollectInto() of empty configuration = %q, want empty", got)
+	}
+}
+
+func BenchmarkConfigWriteTo(b *testing.B) {
+	for _, n := range []int{10, 1000, 10000} {
+		config := Make(WithSubject(benchItems(n)), WithDelim(":"))
+
+		b.Run("items="+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				_, _ = config.WriteTo(io.Discard)
+			}
+		})
+	}
+}
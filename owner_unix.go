@@ -0,0 +1,19 @@
+//go:build unix
+
+package mung
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileOwner returns the user ID of the owner of the file described by info,
+// and whether it is known.
+func fileOwner(info fs.FileInfo) (int, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	return int(st.Uid), true
+}
@@ -0,0 +1,173 @@
+package mung
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithListSeparator returns an option that sets the string tokenizing
+// delimiter to the list separator of the running operating system,
+// [filepath.ListSeparator].
+func WithListSeparator() Option[Config] {
+	return WithDelim(string(filepath.ListSeparator))
+}
+
+// WithListSeparatorFor is like [WithListSeparator] but uses the list separator
+// of the operating system named goos, using the same names as [runtime.GOOS].
+//
+// The list separator is ";" on "windows", NUL on "plan9", and ":" otherwise.
+func WithListSeparatorFor(goos string) Option[Config] {
+	return WithDelim(listSeparator(goos))
+}
+
+// listSeparator returns the list separator of the operating system named goos.
+func listSeparator(goos string) string {
+	switch goos {
+	case "windows":
+		return ";"
+	case "plan9":
+		return "\x00"
+	default:
+		return ":"
+	}
+}
+
+// WithCleanPaths returns an option that rewrites every item to the shortest
+// equivalent path name before comparison and output.
+//
+// Items are cleaned with [filepath.Clean], which collapses repeated
+// separators and "." and ".." elements and removes trailing separators.
+// Items of removal and replacement rules are cleaned the same way.
+func WithCleanPaths() Option[Config] {
+	return func(config Config) Config {
+		config.clean = true
+
+		return config
+	}
+}
+
+// WithExpandEnv returns an option that expands "$VAR" and "${VAR}"
+// references in every item to the value of the corresponding environment
+// variable before splitting, comparison, and output.
+//
+// Undefined variables are replaced with the empty string.
+// Removal and replacement rules are expanded the same way.
+func WithExpandEnv() Option[Config] { return WithExpandEnvFunc(os.Getenv) }
+
+// WithExpandEnvFunc is like [WithExpandEnv] but replaces each variable
+// reference with the value returned by mapping instead of the environment.
+//
+// The mapping function has the same semantics as with [os.Expand].
+func WithExpandEnvFunc(mapping func(string) string) Option[Config] {
+	return func(config Config) Config {
+		config.expand = mapping
+
+		return config
+	}
+}
+
+// expandEnv returns s with variable references replaced by the receiver's
+// expansion mapping, if any.
+func (c Config) expandEnv(s string) string {
+	if c.expand == nil {
+		return s
+	}
+
+	return os.Expand(s, c.expand)
+}
+
+// WithExpandTilde returns an option that expands a leading "~" or "~user"
+// in every item to the corresponding home directory before comparison and
+// output.
+//
+// Items of removal and replacement rules are expanded the same way.
+// Items are left unmodified if the home directory cannot be determined.
+func WithExpandTilde() Option[Config] {
+	return func(config Config) Config {
+		config.tilde = true
+
+		return config
+	}
+}
+
+// expandTilde returns s with a leading "~" or "~user" replaced by the home
+// directory of the current user or the named user, respectively.
+//
+// If s does not begin with "~" or the home directory cannot be determined,
+// s is returned unmodified.
+func expandTilde(s string) string {
+	if !strings.HasPrefix(s, "~") {
+		return s
+	}
+
+	name, rest := s[1:], ""
+	if i := strings.IndexAny(name, "/"+string(filepath.Separator)); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+
+	var (
+		home string
+		err  error
+	)
+
+	if name == "" {
+		home, err = os.UserHomeDir()
+	} else {
+		var u *user.User
+		if u, err = user.Lookup(name); err == nil {
+			home = u.HomeDir
+		}
+	}
+
+	if err != nil || home == "" {
+		return s
+	}
+
+	return home + rest
+}
+
+// unicodeMode identifies how items are normalized to a Unicode normal form.
+type unicodeMode int
+
+const (
+	// unicodeNone does not normalize items (default).
+	unicodeNone unicodeMode = iota
+	// unicodeCompare normalizes items only for comparison.
+	unicodeCompare
+	// unicodeRewrite normalizes items for comparison and output.
+	unicodeRewrite
+)
+
+// WithNormalizeUnicode returns an option that compares items by their
+// Unicode normal form when eliminating duplicates and matching removal and
+// replacement rules.
+//
+// For example, with [norm.NFC], the precomposed "é" (U+00E9) and decomposed
+// "é" (U+0065 U+0301) spellings of a path name are considered duplicates.
+// macOS file systems commonly return decomposed names.
+// The original spelling of each yielded item is preserved.
+// Use [WithRewriteUnicode] to yield the normalized items instead.
+func WithNormalizeUnicode(form norm.Form) Option[Config] {
+	return func(config Config) Config {
+		config.unicode, config.form = unicodeCompare, form
+
+		return config
+	}
+}
+
+// WithRewriteUnicode returns an option that replaces every item with its
+// Unicode normal form.
+//
+// This is like [WithNormalizeUnicode], except the normalized form of each
+// item is yielded instead of its original spelling.
+func WithRewriteUnicode(form norm.Form) Option[Config] {
+	return func(config Config) Config {
+		config.unicode, config.form = unicodeRewrite, form
+
+		return config
+	}
+}
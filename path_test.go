@@ -0,0 +1,316 @@
+package mung
+
+import (
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestWithListSeparator(t *testing.T) {
+	config := Make(WithListSeparator())
+	if got, want := config.Delim(), string(filepath.ListSeparator); got != want {
+		t.Errorf("Delim() = %q, want %q", got, want)
+	}
+	if got, want := listSeparator(runtime.GOOS), string(filepath.ListSeparator); got != want {
+		t.Errorf("listSeparator(%q) = %q, want %q", runtime.GOOS, got, want)
+	}
+}
+
+func TestWithListSeparatorFor(t *testing.T) {
+	tests := []struct {
+		goos string
+		want string
+	}{
+		{"windows", ";"},
+		{"plan9", "\x00"},
+		{"linux", ":"},
+		{"darwin", ":"},
+		{"", ":"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			config := Make(WithDelims(",", ";"), WithListSeparatorFor(tt.goos))
+			if got := config.Delim(); got != tt.want {
+				t.Errorf("Delim() = %q, want %q", got, tt.want)
+			}
+			if got := config.Delims(); len(got) != 1 {
+				t.Errorf("Delims() = %q, want [%q]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCleanPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		want    []string
+	}{
+		{
+			name:    "collapse_duplicates",
+			initial: Config{subject: []string{"/usr/bin/:/usr//bin:/usr/./bin"}},
+			want:    []string{"/usr/bin"},
+		},
+		{
+			name:    "parent_elements",
+			initial: Config{subject: []string{"/opt/app/../bin:/opt/bin"}},
+			want:    []string{"/opt/bin"},
+		},
+		{
+			name: "remove_is_cleaned",
+			initial: Config{
+				subject: []string{"/usr/bin:/bin"},
+				remove:  []string{"/bin/"},
+			},
+			want: []string{"/usr/bin"},
+		},
+		{
+			name: "replace_is_cleaned",
+			initial: Config{
+				subject: []string{"/usr/bin/:/bin"},
+				replace: map[string]string{"/usr//bin": "/opt/bin"},
+			},
+			want: []string{"/opt/bin", "/bin"},
+		},
+		{
+			name: "prefix_and_suffix",
+			initial: Config{
+				subject: []string{"/a/:/b/:/c/"},
+				prefix:  []string{"/b"},
+				suffix:  []string{"/a//"},
+			},
+			want: []string{"/b", "/c", "/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(":"), WithCleanPaths())
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithCleanPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithExpandTilde(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+
+	tests := []struct {
+		name    string
+		initial Config
+		want    []string
+	}{
+		{
+			name:    "expand_home",
+			initial: Config{subject: []string{"~/.local/bin:/usr/bin"}},
+			want:    []string{"/home/me/.local/bin", "/usr/bin"},
+		},
+		{
+			name:    "collapse_expanded_duplicates",
+			initial: Config{subject: []string{"~/bin:/home/me/bin"}},
+			want:    []string{"/home/me/bin"},
+		},
+		{
+			name: "remove_is_expanded",
+			initial: Config{
+				subject: []string{"/home/me/bin:/usr/bin"},
+				remove:  []string{"~/bin"},
+			},
+			want: []string{"/usr/bin"},
+		},
+		{
+			name:    "interior_tilde_unmodified",
+			initial: Config{subject: []string{"/opt/~/bin"}},
+			want:    []string{"/opt/~/bin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(":"), WithExpandTilde())
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithExpandTilde() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandTilde(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no_tilde", "/usr/bin", "/usr/bin"},
+		{"tilde_only", "~", "/home/me"},
+		{"tilde_slash", "~/bin", "/home/me/bin"},
+		{"unknown_user", "~no-such-user-mung/bin", "~no-such-user-mung/bin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandTilde(tt.in); got != tt.want {
+				t.Errorf("expandTilde(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("named_user", func(t *testing.T) {
+		u, err := user.Current()
+		if err != nil || u.HomeDir == "" {
+			t.Skip("current user unavailable")
+		}
+		want := u.HomeDir + "/bin"
+		if got := expandTilde("~" + u.Username + "/bin"); got != want {
+			t.Errorf("expandTilde() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("unset_home", func(t *testing.T) {
+		t.Setenv("HOME", "")
+		if got := expandTilde("~/bin"); got != "~/bin" {
+			t.Errorf("expandTilde() = %q, want %q", got, "~/bin")
+		}
+	})
+}
+
+func TestWithExpandEnv(t *testing.T) {
+	t.Setenv("MUNG_TEST_HOME", "/home/me")
+	t.Setenv("MUNG_TEST_PATH", "/usr/bin:/bin")
+
+	tests := []struct {
+		name    string
+		initial Config
+		want    []string
+	}{
+		{
+			name:    "expand_item",
+			initial: Config{subject: []string{"$MUNG_TEST_HOME/bin:${MUNG_TEST_HOME}/sbin"}},
+			want:    []string{"/home/me/bin", "/home/me/sbin"},
+		},
+		{
+			name:    "expand_before_split",
+			initial: Config{subject: []string{"/opt/bin:$MUNG_TEST_PATH:/bin"}},
+			want:    []string{"/opt/bin", "/usr/bin", "/bin"},
+		},
+		{
+			name: "rules_are_expanded",
+			initial: Config{
+				subject: []string{"/home/me/bin:/usr/bin:/bin"},
+				remove:  []string{"$MUNG_TEST_HOME/bin"},
+				replace: map[string]string{"/bin": "$MUNG_TEST_HOME/sbin"},
+			},
+			want: []string{"/usr/bin", "/home/me/sbin"},
+		},
+		{
+			name:    "undefined_is_empty",
+			initial: Config{subject: []string{"$MUNG_TEST_UNDEFINED:/bin"}},
+			want:    []string{"/bin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(":"), WithExpandEnv())
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("WithExpandEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("custom_mapping", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("$ROOT/bin", "/usr/bin"),
+			WithDelim(":"),
+			WithRemoveItems("$ROOT/usr/bin"),
+			WithExpandEnvFunc(func(name string) string {
+				if name == "ROOT" {
+					return "/chroot"
+				}
+				return ""
+			}),
+		)
+		got := slices.Collect(config.All())
+		if want := []string{"/chroot/bin", "/usr/bin"}; !slicesEqual(got, want) {
+			t.Errorf("WithExpandEnvFunc() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestWithNormalizeUnicode(t *testing.T) {
+	const (
+		nfc = "/Users/jos\u00e9/bin"  // precomposed
+		nfd = "/Users/jose\u0301/bin" // decomposed
+	)
+
+	tests := []struct {
+		name    string
+		initial Config
+		opt     Option[Config]
+		want    []string
+	}{
+		{
+			name:    "compare_keeps_first_spelling",
+			initial: Config{subject: []string{nfd + ":" + nfc}},
+			opt:     WithNormalizeUnicode(norm.NFC),
+			want:    []string{nfd},
+		},
+		{
+			name: "compare_remove",
+			initial: Config{
+				subject: []string{nfd + ":/bin"},
+				remove:  []string{nfc},
+			},
+			opt:  WithNormalizeUnicode(norm.NFC),
+			want: []string{"/bin"},
+		},
+		{
+			name: "compare_replace",
+			initial: Config{
+				subject: []string{nfc + ":/bin"},
+				replace: map[string]string{nfd: "/opt/bin"},
+			},
+			opt:  WithNormalizeUnicode(norm.NFD),
+			want: []string{"/opt/bin", "/bin"},
+		},
+		{
+			name:    "rewrite_nfc",
+			initial: Config{subject: []string{nfd + ":" + nfc}},
+			opt:     WithRewriteUnicode(norm.NFC),
+			want:    []string{nfc},
+		},
+		{
+			name:    "rewrite_nfd",
+			initial: Config{subject: []string{nfc + ":" + nfd}},
+			opt:     WithRewriteUnicode(norm.NFD),
+			want:    []string{nfd},
+		},
+		{
+			name:    "disabled",
+			initial: Config{subject: []string{nfd + ":" + nfc}},
+			opt:     WithDelim(":"),
+			want:    []string{nfd, nfc},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(":"), tt.opt)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package mung
+
+import (
+	"iter"
+	"slices"
+)
+
+// pin is an item forced into a fixed position of the munged sequence.
+type pin struct {
+	item  string
+	index int
+}
+
+// WithPin returns an option that forces item into the given position of the
+// munged sequence, regardless of where, or whether, it appears in the input,
+// e.g., to keep a directory of shims first in PATH.
+//
+// A negative index counts from the end of the sequence, so that -1 is the
+// last position, and an index beyond either end is clamped to that end.
+// If the position is taken by an item pinned before, the item is pinned to
+// the next free position.
+//
+// The item is normalized like the items of replacement rules and is compared
+// with other items using the receiver's comparison rules, so that it occurs
+// exactly once. Pinned items are inserted after every other setting has been
+// applied, including [WithStages], and so are never removed, filtered, or
+// replaced. Pinning an item again replaces its previous position.
+// Pinned items are described by [Config.Plan] with [OpPin] and by
+// [Config.Explain] with [Origin.Pinned].
+func WithPin(item string, index int) Option[Config] {
+	return func(config Config) Config {
+		config.pins = slices.DeleteFunc(slices.Clone(config.pins),
+			func(p pin) bool { return p.item == item })
+		config.pins = append(config.pins, pin{item: item, index: index})
+
+		return config
+	}
+}
+
+// Pins returns a copy of the items pinned with [WithPin], keyed by item.
+func (c Config) Pins() map[string]int {
+	pins := make(map[string]int, len(c.pins))
+	for _, p := range c.pins {
+		pins[p.item] = p.index
+	}
+
+	return pins
+}
+
+// pinStage returns a [Stage] that moves or inserts each of the receiver's
+// pinned items into its position, or nil if there are none.
+//
+// Each pinned item, and each other item collected and yielded, is reported
+// to the receiver's observer.
+func (c Config) pinStage() Stage {
+	if len(c.pins) == 0 {
+		return nil
+	}
+
+	return func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			// Pins are visited last to first, so that the last of several pins
+			// of equal items wins.
+			pinned := c.newSet()
+			pins := make([]pin, 0, len(c.pins))
+
+			for _, p := range slices.Backward(c.pins) {
+				p.item = c.normalize(c.expandEnv(p.item))
+				if p.item != "" && !pinned.Seen(p.item) {
+					pins = append(pins, p)
+				}
+			}
+
+			slices.Reverse(pins)
+
+			var rest []string
+
+			for s := range items {
+				if !pinned.Contains(s) {
+					c.emit(event{op: opHeld, item: s, stage: StagePin})
+
+					rest = append(rest, s)
+				}
+			}
+
+			out := make([]string, len(rest)+len(pins))
+			taken := make([]bool, len(out))
+
+			for _, p := range pins {
+				i := p.index
+				if i < 0 {
+					i += len(out)
+				}
+
+				i = max(0, min(i, len(out)-1))
+				for taken[i] {
+					i = (i + 1) % len(out)
+				}
+
+				out[i], taken[i] = p.item, true
+			}
+
+			for i := range out {
+				if !taken[i] {
+					out[i], rest = rest[0], rest[1:]
+				}
+			}
+
+			// Items that are not pinned are yielded in the order collected.
+			held := 0
+
+			for i, s := range out {
+				if taken[i] {
+					c.emit(event{op: OpPin, item: s})
+				} else {
+					c.emit(event{op: opReleased, item: s, index: held,
+						stage: StagePin})
+					held++
+				}
+
+				if !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package mung
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestWithPin(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+	}{
+		{
+			name: "move_first",
+			opts: []Option[Config]{WithPin("c", 0)},
+			want: "c:a:b:d",
+		},
+		{
+			name: "insert_first",
+			opts: []Option[Config]{WithPin("shim", 0)},
+			want: "shim:a:b:c:d",
+		},
+		{
+			name: "last",
+			opts: []Option[Config]{WithPin("a", -1)},
+			want: "b:c:d:a",
+		},
+		{
+			name: "clamped",
+			opts: []Option[Config]{WithPin("a", 9), WithPin("d", -9)},
+			want: "d:b:c:a",
+		},
+		{
+			name: "taken",
+			opts: []Option[Config]{WithPin("d", 1), WithPin("c", 1)},
+			want: "a:d:c:b",
+		},
+		{
+			name: "repinned",
+			opts: []Option[Config]{WithPin("d", 0), WithPin("d", 2)},
+			want: "a:b:d:c",
+		},
+		{
+			name: "after_prefix",
+			opts: []Option[Config]{WithPrefixItems("p"), WithPin("shim", 0)},
+			want: "shim:p:a:b:c:d",
+		},
+		{
+			name: "not_removed",
+			opts: []Option[Config]{WithRemoveItems("b"), WithPin("b", 0)},
+			want: "b:a:c:d",
+		},
+		{
+			name: "compared_by_key",
+			opts: []Option[Config]{WithCaseFold(), WithPin("B", 0)},
+			want: "B:a:c:d",
+		},
+		{
+			name: "empty",
+			opts: []Option[Config]{WithPin("", 0)},
+			want: "a:b:c:d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubjectItems("a:b:c:d"), WithDelim(":"),
+			}, tt.opts...)
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPinOnly(t *testing.T) {
+	config := Make(WithDelim(":"), WithPin("b", -1), WithPin("a", 5))
+
+	if got, want := config.String(), "a:b"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestWithPinMerge(t *testing.T) {
+	base := Make(WithPin("a", 0), WithPin("b", 1))
+	merged := base.Merge(Make(WithPin("a", 2)))
+
+	if got, want := merged.Pins(), map[string]int{"a": 2, "b": 1}; !maps.Equal(got, want) {
+		t.Errorf("Merge() Pins() = %v, want %v", got, want)
+	}
+
+	if got, want := base.Pins(), map[string]int{"a": 0, "b": 1}; !maps.Equal(got, want) {
+		t.Errorf("Merge() modified receiver: Pins() = %v, want %v", got, want)
+	}
+
+	if base.Equal(merged) {
+		t.Error("Equal() = true for configurations with different pins")
+	}
+}
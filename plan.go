@@ -0,0 +1,96 @@
+package mung
+
+import "slices"
+
+// Step describes an operation applied to an item of a [Plan].
+type Step struct {
+	// Op is the operation applied to the item.
+	Op Op
+	// Item is the item as split from its source and normalized.
+	Item string
+	// Source identifies the strings the item was split from.
+	Source Scope
+	// Rule is the remove rule matched by the item, for [OpRemove],
+	// or the key of the replacement rule matched by the item, for [OpReplace].
+	Rule string
+	// Replacement is the item yielded instead, for [OpReplace].
+	Replacement string
+}
+
+// Plan describes the operations applied to each item of a munged sequence,
+// e.g., to preview or audit a configuration before applying it.
+type Plan struct {
+	// Steps holds one step for each item split from the prefix, subject, and
+	// suffix strings, in the order the items are evaluated, followed by one
+	// step for each item pinned with [WithPin].
+	Steps []Step
+
+	items []string
+	tok   Tokenizer
+}
+
+// Plan returns a [Plan] of the operations applied to realize the munged
+// sequence of [Config.Filtered], without joining the result into a string.
+//
+// The predicate function and any built-in filters are evaluated once, when
+// the plan is made. Stages set with [WithStages] are applied to the result
+// but are not described by the plan.
+func (c Config) Plan() Plan {
+	var p Plan
+
+	// passed holds the index of the step of each kept or inserted item, and
+	// pending holds those that are not yet output by the replacement stage.
+	var passed, pending []int
+
+	c.observe = func(ev event) {
+		switch ev.op {
+		case OpKeep, OpInsert:
+			passed = append(passed, len(p.Steps))
+			pending = append(pending, len(p.Steps))
+
+		case opSuperseded:
+			p.Steps[passed[ev.index]].Op = OpDuplicate
+
+			return
+
+		case opHeld, opReleased:
+			// Reordering does not change the steps of the items.
+			return
+
+		case OpReplace, opOutput:
+			for len(pending) > 0 && p.Steps[pending[0]].Op == OpDuplicate {
+				pending = pending[1:]
+			}
+
+			if len(pending) > 0 && ev.op == OpReplace {
+				step := &p.Steps[pending[0]]
+				step.Op, step.Rule, step.Replacement = OpReplace, ev.rule, ev.to
+			}
+
+			if len(pending) > 0 {
+				pending = pending[1:]
+			}
+
+			return
+		}
+
+		p.Steps = append(p.Steps, Step{
+			Op: ev.op, Item: ev.item, Source: ev.source, Rule: ev.rule,
+		})
+	}
+
+	p.items = slices.Collect(c.quoted(c.Filtered()))
+	p.tok = c.Tokenizer()
+
+	return p
+}
+
+// Apply returns the munged strings of the plan joined with the [Tokenizer]
+// of the configuration that made it, like [Config.String].
+func (p Plan) Apply() string {
+	if p.tok == nil {
+		return ""
+	}
+
+	return p.tok.Join(slices.Values(p.items))
+}
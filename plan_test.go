@@ -0,0 +1,153 @@
+package mung
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigPlan(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option[Config]
+		steps []Step
+		want  string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "operations",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:z:c:b:x"}),
+				WithPrefix([]string{"p"}),
+				WithSuffix([]string{"c"}),
+				WithRemove([]string{"x"}),
+				WithReplace(map[string]string{"b": "B"}),
+				WithFilter(func(s string) bool { return s != "z" }),
+			},
+			steps: []Step{
+				{Op: OpInsert, Item: "p", Source: ScopePrefix},
+				{Op: OpKeep, Item: "a", Source: ScopeSubject},
+				{
+					Op: OpReplace, Item: "b", Source: ScopeSubject,
+					Rule: "b", Replacement: "B",
+				},
+				{Op: OpFilter, Item: "z", Source: ScopeSubject},
+				{Op: OpDuplicate, Item: "c", Source: ScopeSubject},
+				{Op: OpDuplicate, Item: "b", Source: ScopeSubject},
+				{Op: OpRemove, Item: "x", Source: ScopeSubject, Rule: "x"},
+				{Op: OpInsert, Item: "c", Source: ScopeSuffix},
+			},
+			want: "p:a:B:c",
+		},
+		{
+			name: "keep_last",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:a"}),
+				WithPrefix([]string{"b"}),
+				WithReplace(map[string]string{"a": "A"}),
+				WithDedupeKeepLast(),
+			},
+			steps: []Step{
+				{Op: OpDuplicate, Item: "b", Source: ScopePrefix},
+				{Op: OpDuplicate, Item: "a", Source: ScopeSubject},
+				{Op: OpKeep, Item: "b", Source: ScopeSubject},
+				{
+					Op: OpReplace, Item: "a", Source: ScopeSubject,
+					Rule: "a", Replacement: "A",
+				},
+			},
+			want: "b:A",
+		},
+		{
+			name: "case_fold_rules",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:B"}),
+				WithRemove([]string{"A"}),
+				WithReplace(map[string]string{"b": "c"}),
+				WithCaseFold(),
+			},
+			steps: []Step{
+				{Op: OpRemove, Item: "a", Source: ScopeSubject, Rule: "A"},
+				{
+					Op: OpReplace, Item: "B", Source: ScopeSubject,
+					Rule: "b", Replacement: "c",
+				},
+			},
+			want: "c",
+		},
+		{
+			name: "sort",
+			opts: []Option[Config]{
+				WithSubject([]string{"b:a"}),
+				WithReplace(map[string]string{"b": "c"}),
+				WithSort(strings.Compare),
+			},
+			steps: []Step{
+				{
+					Op: OpReplace, Item: "b", Source: ScopeSubject,
+					Rule: "b", Replacement: "c",
+				},
+				{Op: OpKeep, Item: "a", Source: ScopeSubject},
+			},
+			want: "a:c",
+		},
+		{
+			name: "pin",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b"}),
+				WithPin("b", 0),
+				WithPin("x", -1),
+			},
+			steps: []Step{
+				{Op: OpKeep, Item: "a", Source: ScopeSubject},
+				{Op: OpKeep, Item: "b", Source: ScopeSubject},
+				{Op: OpPin, Item: "b"},
+				{Op: OpPin, Item: "x"},
+			},
+			want: "b:a:x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":")}, tt.opts...)
+			config := Make(opts...)
+
+			plan := config.Plan()
+			if !reflect.DeepEqual(plan.Steps, tt.steps) {
+				t.Errorf("Plan().Steps = %+v, want %+v", plan.Steps, tt.steps)
+			}
+
+			if got := plan.Apply(); got != tt.want {
+				t.Errorf("Plan().Apply() = %q, want %q", got, tt.want)
+			}
+
+			if got, want := plan.Apply(), config.String(); got != want {
+				t.Errorf("Plan().Apply() = %q, String() = %q", got, want)
+			}
+		})
+	}
+
+	if got := (Plan{}).Apply(); got != "" {
+		t.Errorf("Plan{}.Apply() = %q, want empty", got)
+	}
+}
+
+func TestOpString(t *testing.T) {
+	for op, want := range map[Op]string{
+		OpKeep:      "keep",
+		OpInsert:    "insert",
+		OpDuplicate: "duplicate",
+		OpRemove:    "remove",
+		OpReplace:   "replace",
+		OpFilter:    "filter",
+		OpPin:       "pin",
+		opOutput:    "unknown",
+	} {
+		if got := op.String(); got != want {
+			t.Errorf("Op(%d).String() = %q, want %q", op, got, want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package mung
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownOwner is the error of a [Violation] of [Policy.Owners] by an item
+// whose owner cannot be determined, e.g., on systems without user IDs.
+var ErrUnknownOwner = errors.New("unknown owner")
+
+// Policy is a set of requirements that each munged item must meet, e.g., to
+// remove unsafe entries from PATH in a hardened environment.
+// See [StrictPolicy] for a policy suitable for PATH.
+//
+// The zero value permits every item. Each requirement is enforced by
+// [Policy.Enforce] independently, so an item may violate several, except
+// that an item that cannot be stat'ed violates only DenyMissing of the
+// requirements of the file it refers to.
+type Policy struct {
+	// Allow, if not empty, permits only the items that satisfy any of its
+	// predicate functions, e.g., as returned by [Matcher].
+	Allow []func(string) bool
+	// Deny forbids the items that satisfy any of its predicate functions.
+	Deny []func(string) bool
+	// DenyRelative forbids items that are not absolute paths, such as ".".
+	DenyRelative bool
+	// DenyMissing forbids items that do not refer to an existing directory.
+	DenyMissing bool
+	// DenyPerm forbids items that refer to a file with any of the given
+	// permission bits set, e.g., 0o002 to forbid world-writable directories.
+	DenyPerm fs.FileMode
+	// Owners, if not nil, permits only the items that refer to a file owned
+	// by a user with one of the given IDs, e.g., 0 for root.
+	Owners []int
+	// Owned, if not empty, limits the requirement of Owners to the items that
+	// satisfy any of its predicate functions, e.g., system directories.
+	Owned []func(string) bool
+}
+
+// StrictPolicy returns a policy that forbids relative items, items that refer
+// to world-writable files, and items under /bin, /sbin, or /usr that are not
+// owned by root.
+//
+// Note that some package managers install into directories under /usr/local
+// owned by an ordinary user, which this policy forbids.
+func StrictPolicy() Policy {
+	system := func(s string) bool {
+		for _, dir := range []string{"/bin", "/sbin", "/usr"} {
+			if s == dir || strings.HasPrefix(s, dir+"/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return Policy{
+		DenyRelative: true,
+		DenyPerm:     0o002,
+		Owners:       []int{0},
+		Owned:        []func(string) bool{system},
+	}
+}
+
+// ViolationKind identifies the requirement of a [Policy] violated by an item.
+type ViolationKind int
+
+// Constant values of ViolationKind.
+const (
+	// ViolationAllow is an item that satisfies none of [Policy.Allow].
+	ViolationAllow ViolationKind = iota
+	// ViolationDeny is an item that satisfies any of [Policy.Deny].
+	ViolationDeny
+	// ViolationRelative is an item that violates [Policy.DenyRelative].
+	ViolationRelative
+	// ViolationMissing is an item that violates [Policy.DenyMissing].
+	ViolationMissing
+	// ViolationPerm is an item that violates [Policy.DenyPerm].
+	ViolationPerm
+	// ViolationOwner is an item that violates [Policy.Owners].
+	ViolationOwner
+)
+
+// String returns a short description of the kind of violation.
+func (k ViolationKind) String() string {
+	switch k {
+	case ViolationAllow:
+		return "not allowed"
+	case ViolationDeny:
+		return "denied"
+	case ViolationRelative:
+		return "relative"
+	case ViolationMissing:
+		return "missing"
+	case ViolationPerm:
+		return "forbidden permissions"
+	case ViolationOwner:
+		return "forbidden owner"
+	default:
+		return fmt.Sprintf("ViolationKind(%d)", int(k))
+	}
+}
+
+// Violation describes a requirement of a [Policy] violated by an item.
+type Violation struct {
+	// Kind identifies the requirement violated.
+	Kind ViolationKind
+	// Item is the munged item.
+	Item string
+	// Err is the error that caused the violation, if any, e.g., the error
+	// returned by stat under [ViolationMissing].
+	Err error
+}
+
+// Error returns a description of the violation.
+func (v Violation) Error() string {
+	if v.Err != nil {
+		return fmt.Sprintf("%q: %s: %v", v.Item, v.Kind, v.Err)
+	}
+
+	return fmt.Sprintf("%q: %s", v.Item, v.Kind)
+}
+
+// Unwrap returns the error that caused the violation, if any.
+func (v Violation) Unwrap() error { return v.Err }
+
+// Enforce returns a configuration like c that does not yield the munged items
+// of c that violate the policy, and the violations of each such item, in the
+// order the items are yielded by [Config.Filtered].
+//
+// The items are checked once, when Enforce is called, against the file
+// system as configured by [WithFS] or [WithStatFunc]. The returned
+// configuration removes the violating items after every other setting of c
+// has been applied, including [WithPin], so that items introduced by
+// replacement rules or pinned are also enforced.
+func (p Policy) Enforce(c Config) (Config, []Violation) {
+	var violations []Violation
+
+	denied := Set[string]{}
+	prepared := c.prepare()
+
+	for s := range c.Filtered() {
+		if denied.Contains(s) {
+			continue
+		}
+
+		if v := p.check(prepared, s); len(v) > 0 {
+			violations = append(violations, v...)
+			denied.Add(s)
+		}
+	}
+
+	if len(denied) == 0 {
+		return c, nil
+	}
+
+	return Wrap(c, WithStageAfter(StagePin, func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for s := range items {
+				if !denied.Contains(s) && !yield(s) {
+					return
+				}
+			}
+		}
+	})), violations
+}
+
+// check returns the violations of the policy by item s.
+func (p Policy) check(c Config, s string) []Violation {
+	var violations []Violation
+
+	report := func(kind ViolationKind, err error) {
+		violations = append(violations, Violation{Kind: kind, Item: s, Err: err})
+	}
+
+	if len(p.Allow) > 0 && !slices.ContainsFunc(p.Allow, satisfies(s)) {
+		report(ViolationAllow, nil)
+	}
+
+	if slices.ContainsFunc(p.Deny, satisfies(s)) {
+		report(ViolationDeny, nil)
+	}
+
+	// Rooted items are absolute within a file system set with WithFS.
+	if p.DenyRelative && !filepath.IsAbs(s) && !strings.HasPrefix(s, "/") {
+		report(ViolationRelative, nil)
+	}
+
+	owned := p.Owners != nil &&
+		(len(p.Owned) == 0 || slices.ContainsFunc(p.Owned, satisfies(s)))
+
+	if !p.DenyMissing && p.DenyPerm == 0 && !owned {
+		return violations
+	}
+
+	info, err := c.statItem(s)
+
+	switch {
+	case err != nil:
+		if p.DenyMissing {
+			report(ViolationMissing, err)
+		}
+
+		return violations
+
+	case p.DenyMissing && !info.IsDir():
+		report(ViolationMissing, fmt.Errorf("%w: not a directory", fs.ErrInvalid))
+	}
+
+	if perm := info.Mode().Perm() & p.DenyPerm; perm != 0 {
+		report(ViolationPerm, fmt.Errorf("mode %v", info.Mode().Perm()))
+	}
+
+	if owned {
+		switch uid, ok := fileOwner(info); {
+		case !ok:
+			report(ViolationOwner, ErrUnknownOwner)
+		case !slices.Contains(p.Owners, uid):
+			report(ViolationOwner, fmt.Errorf("owner %d", uid))
+		}
+	}
+
+	return violations
+}
+
+// satisfies returns a function that reports whether s satisfies a predicate
+// function. Nil predicate functions are never satisfied.
+func satisfies(s string) func(func(string) bool) bool {
+	return func(predicate func(string) bool) bool {
+		return predicate != nil && predicate(s)
+	}
+}
@@ -0,0 +1,190 @@
+package mung
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPolicyEnforce(t *testing.T) {
+	fsys := fstest.MapFS{
+		"usr/bin":    {Mode: fs.ModeDir | 0o755},
+		"opt/bin":    {Mode: fs.ModeDir | 0o755},
+		"tmp":        {Mode: fs.ModeDir | 0o777},
+		"etc/passwd": {Mode: 0o644},
+	}
+
+	hasOpt := func(s string) bool { return strings.HasPrefix(s, "/opt/") }
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   string
+		kinds  []ViolationKind
+	}{
+		{
+			name: "zero",
+			want: "/usr/bin:.:/tmp:/opt/bin:/etc/passwd:/missing",
+		},
+		{
+			name:   "allow",
+			policy: Policy{Allow: []func(string) bool{nil, hasOpt}},
+			want:   "/opt/bin",
+			kinds: []ViolationKind{
+				ViolationAllow, ViolationAllow, ViolationAllow,
+				ViolationAllow, ViolationAllow,
+			},
+		},
+		{
+			name:   "deny",
+			policy: Policy{Deny: []func(string) bool{hasOpt}},
+			want:   "/usr/bin:.:/tmp:/etc/passwd:/missing",
+			kinds:  []ViolationKind{ViolationDeny},
+		},
+		{
+			name:   "relative",
+			policy: Policy{DenyRelative: true},
+			want:   "/usr/bin:/tmp:/opt/bin:/etc/passwd:/missing",
+			kinds:  []ViolationKind{ViolationRelative},
+		},
+		{
+			name:   "missing",
+			policy: Policy{DenyMissing: true},
+			want:   "/usr/bin:.:/tmp:/opt/bin",
+			kinds:  []ViolationKind{ViolationMissing, ViolationMissing},
+		},
+		{
+			name:   "perm",
+			policy: Policy{DenyPerm: 0o002},
+			want:   "/usr/bin:.:/opt/bin:/etc/passwd:/missing",
+			kinds:  []ViolationKind{ViolationPerm},
+		},
+		{
+			name:   "unknown_owner",
+			policy: Policy{Owners: []int{0}, Owned: []func(string) bool{hasOpt}},
+			want:   "/usr/bin:.:/tmp:/etc/passwd:/missing",
+			kinds:  []ViolationKind{ViolationOwner},
+		},
+		{
+			name:   "strict",
+			policy: StrictPolicy(),
+			want:   "/opt/bin:/etc/passwd:/missing",
+			kinds:  []ViolationKind{ViolationOwner, ViolationRelative, ViolationPerm},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(
+				WithSubjectItems("/usr/bin:.:/tmp:/opt/bin:/etc/passwd:/missing"),
+				WithDelim(":"),
+				WithFS(fsys),
+			)
+
+			enforced, violations := tt.policy.Enforce(config)
+			if got := enforced.String(); got != tt.want {
+				t.Errorf("Enforce() String() = %q, want %q", got, tt.want)
+			}
+
+			var kinds []ViolationKind
+			for _, v := range violations {
+				kinds = append(kinds, v.Kind)
+			}
+
+			if !slices.Equal(kinds, tt.kinds) {
+				t.Errorf("Enforce() violations = %v, want %v", violations, tt.kinds)
+			}
+		})
+	}
+}
+
+func TestPolicyEnforceReplaced(t *testing.T) {
+	config := Make(
+		WithSubjectItems("/a:/b"),
+		WithDelim(":"),
+		WithReplaceItems(map[string]string{"/b": "."}),
+	)
+
+	enforced, violations := Policy{DenyRelative: true}.Enforce(config)
+	if got, want := enforced.String(), "/a"; got != want {
+		t.Errorf("Enforce() String() = %q, want %q", got, want)
+	}
+
+	if len(violations) != 1 || violations[0].Item != "." {
+		t.Errorf("Enforce() violations = %v, want one of %q", violations, ".")
+	}
+
+	if got, want := config.String(), "/a:."; got != want {
+		t.Errorf("Enforce() modified receiver: String() = %q, want %q", got, want)
+	}
+}
+
+func TestPolicyEnforcePinned(t *testing.T) {
+	config := Make(
+		WithDelim(":"), WithSubject([]string{"/usr/bin:."}), WithPin(".", 0),
+	)
+
+	enforced, violations := Policy{DenyRelative: true}.Enforce(config)
+	if got, want := enforced.String(), "/usr/bin"; got != want {
+		t.Errorf("Enforce() String() = %q, want %q", got, want)
+	}
+
+	if len(violations) != 1 || violations[0].Kind != ViolationRelative {
+		t.Errorf("Enforce() violations = %v, want one %v", violations,
+			ViolationRelative)
+	}
+
+	// A pin of an item not in the subject is enforced as well.
+	config = Make(WithDelim(":"), WithSubject([]string{"/usr/bin"}),
+		WithPin("bin", -1))
+
+	enforced, _ = Policy{DenyRelative: true}.Enforce(config)
+	if got, want := enforced.String(), "/usr/bin"; got != want {
+		t.Errorf("Enforce() String() = %q, want %q", got, want)
+	}
+}
+
+func TestPolicyOwners(t *testing.T) {
+	uid := os.Getuid()
+	if uid < 0 {
+		t.Skip("user IDs unsupported")
+	}
+
+	dir := t.TempDir()
+	config := Make(WithSubjectItems(dir), WithDelim(string(os.PathListSeparator)))
+
+	if _, v := (Policy{Owners: []int{uid}}).Enforce(config); len(v) != 0 {
+		t.Errorf("Enforce() owned by %d = %v, want none", uid, v)
+	}
+
+	_, v := Policy{Owners: []int{uid + 1}}.Enforce(config)
+	if len(v) != 1 || v[0].Kind != ViolationOwner {
+		t.Errorf("Enforce() owned by %d = %v, want %v", uid+1, v, ViolationOwner)
+	}
+
+	_, v = Policy{DenyMissing: true}.Enforce(Wrap(config,
+		WithSubjectItems(filepath.Join(dir, "missing"))))
+	if len(v) != 1 || !errors.Is(v[0], fs.ErrNotExist) {
+		t.Errorf("Enforce() missing = %v, want %v", v, fs.ErrNotExist)
+	}
+}
+
+func TestViolationError(t *testing.T) {
+	v := Violation{Kind: ViolationOwner, Item: "/a", Err: ErrUnknownOwner}
+	if got, want := v.Error(), `"/a": forbidden owner: unknown owner`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	if !errors.Is(v, ErrUnknownOwner) {
+		t.Errorf("errors.Is(%v, %v) = false", v, ErrUnknownOwner)
+	}
+
+	if got, want := (Violation{Kind: ViolationDeny, Item: "."}).Error(), `".": denied`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package mung
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// WithPriority returns an option that orders the munged sequence by the score
+// of each item, as returned by priority, with lower scores first, e.g., to
+// place user directories before vendor directories before system directories.
+//
+// Items are sorted stably, so that items of equal score keep their relative
+// order, after elimination of duplicates and replacement, and before the
+// stages set with [WithStages]. Prefix and suffix items are sorted along with
+// the subject items; give them the lowest and highest scores, respectively,
+// to keep them in place.
+//
+// Since no item can be yielded before every score is known, the sequence is
+// collected once per realization. A nil priority function disables sorting
+// (default).
+func WithPriority(priority func(string) int) Option[Config] {
+	return func(config Config) Config {
+		config.priority = priority
+
+		return config
+	}
+}
+
+// WithSort returns an option that orders the munged sequence with compare,
+// which returns a negative number if a sorts before b, a positive number if
+// a sorts after b, and zero otherwise, e.g., [strings.Compare] for byte order
+// or a function returned by [Collation] for a language's conventional order.
+//
+// Items are sorted stably, along with prefix and suffix items, at the same
+// point as with [WithPriority]. If both are set, items are sorted by score
+// first, and items of equal score are sorted with compare, e.g., to sort the
+// items of each group of [WithGroups]. A nil compare function disables
+// sorting (default).
+func WithSort(compare func(a, b string) int) Option[Config] {
+	return func(config Config) Config {
+		config.compare = compare
+
+		return config
+	}
+}
+
+// orderStage returns a [Stage] that stably sorts items by the score returned
+// by the receiver's priority function and then with its compare function,
+// ignoring either if nil, or nil if both are nil.
+//
+// Each item collected and yielded is reported to the receiver's observer,
+// so that the origins of the items can be reordered along with them.
+func (c Config) orderStage() Stage {
+	priority, compare := c.priority, c.compare
+	if priority == nil && compare == nil {
+		return nil
+	}
+
+	if priority == nil {
+		priority = func(string) int { return 0 }
+	}
+
+	if compare == nil {
+		compare = func(string, string) int { return 0 }
+	}
+
+	type scored struct {
+		item  string
+		score int
+		index int
+	}
+
+	return func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			var all []scored
+			for s := range items {
+				c.emit(event{op: opHeld, item: s, stage: StageSort})
+
+				all = append(all,
+					scored{item: s, score: priority(s), index: len(all)})
+			}
+
+			slices.SortStableFunc(all, func(a, b scored) int {
+				return cmp.Or(cmp.Compare(a.score, b.score), compare(a.item, b.item))
+			})
+
+			for _, s := range all {
+				c.emit(event{op: opReleased, item: s.item, index: s.index,
+					stage: StageSort})
+
+				if !yield(s.item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// WithGroups returns an option that orders the munged sequence by group,
+// where the group of each item is the first of groups it satisfies, and items
+// that satisfy none form a final group, e.g., to place items under
+// ~/.local before items under /usr/local before all other items.
+//
+// Items of the same group keep their relative order. Use [Matcher] to obtain
+// group predicate functions from glob, regular expression, or prefix
+// patterns.
+//
+// WithGroups is equivalent to [WithPriority] with a function returning the
+// index of the group of each item, and so replaces any priority function set
+// before it. Nil predicate functions are never satisfied.
+func WithGroups(groups ...func(string) bool) Option[Config] {
+	groups = slices.Clone(groups)
+
+	return WithPriority(func(s string) int {
+		for i, group := range groups {
+			if group != nil && group(s) {
+				return i
+			}
+		}
+
+		return len(groups)
+	})
+}
+
+// Matcher returns a function that reports whether an item matches value
+// according to match, as the value of a [Rule] would, e.g., to define the
+// groups of [WithGroups].
+//
+// The error wraps [ErrInvalidRule] if match is unknown or value is not a valid
+// pattern.
+func Matcher(match Match, value string) (func(string) bool, error) {
+	m, _, err := Rule{Match: match, Value: value}.matcher()
+
+	return m, err
+}
@@ -0,0 +1,211 @@
+package mung
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWithPriority(t *testing.T) {
+	// home < /usr/local < everything else.
+	policy := func(s string) int {
+		switch {
+		case strings.HasPrefix(s, "/home/"):
+			return 0
+		case strings.HasPrefix(s, "/usr/local/"):
+			return 1
+		}
+
+		return 2
+	}
+
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+	}{
+		{
+			name: "stable",
+			want: "/home/u/bin:/home/u/go/bin:/usr/local/bin:/usr/bin:/bin",
+		},
+		{
+			name: "after_dedupe_keep_last",
+			opts: []Option[Config]{WithDedupeKeepLast()},
+			want: "/home/u/go/bin:/home/u/bin:/usr/local/bin:/bin:/usr/bin",
+		},
+		{
+			name: "replaced",
+			opts: []Option[Config]{
+				WithReplaceItems(map[string]string{"/bin": "/home/u/sbin"}),
+			},
+			want: "/home/u/bin:/home/u/sbin:/home/u/go/bin:/usr/local/bin:/usr/bin",
+		},
+		{
+			name: "prefix_sorted",
+			opts: []Option[Config]{WithPrefixItems("/opt/bin")},
+			want: "/home/u/bin:/home/u/go/bin:/usr/local/bin:/opt/bin:/usr/bin:/bin",
+		},
+		{
+			name: "stages_after",
+			opts: []Option[Config]{
+				WithStages(func(items iter.Seq[string]) iter.Seq[string] {
+					return slices.Values(Reverse(slices.Collect(items)))
+				}),
+			},
+			want: "/bin:/usr/bin:/usr/local/bin:/home/u/go/bin:/home/u/bin",
+		},
+		{
+			name: "nil",
+			opts: []Option[Config]{WithPriority(nil)},
+			want: "/usr/bin:/home/u/bin:/bin:/usr/local/bin:/home/u/go/bin",
+		},
+	}
+
+	subject := "/usr/bin:/home/u/bin:/bin:/usr/bin:/usr/local/bin:/home/u/go/bin:/home/u/bin"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{subject}), WithDelim(":"), WithPriority(policy),
+			}, tt.opts...)
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithGroups(t *testing.T) {
+	bin := func(s string) bool { return strings.HasSuffix(s, "/bin") }
+	usr := func(s string) bool { return strings.HasPrefix(s, "/usr/") }
+
+	tests := []struct {
+		name   string
+		groups []func(string) bool
+		want   string
+	}{
+		{
+			name: "none",
+			want: "/usr/lib:/bin:/opt/x:/usr/bin",
+		},
+		{
+			name:   "first_match_wins",
+			groups: []func(string) bool{bin, usr},
+			want:   "/bin:/usr/bin:/usr/lib:/opt/x",
+		},
+		{
+			name:   "order_of_groups",
+			groups: []func(string) bool{usr, bin},
+			want:   "/usr/lib:/usr/bin:/bin:/opt/x",
+		},
+		{
+			name:   "nil_group",
+			groups: []func(string) bool{nil, usr},
+			want:   "/usr/lib:/usr/bin:/bin:/opt/x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(
+				WithSubjectItems("/usr/lib:/bin:/opt/x:/usr/bin"),
+				WithDelim(":"),
+				WithGroups(tt.groups...),
+			)
+
+			if got := config.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		match   Match
+		value   string
+		item    string
+		want    bool
+		wantErr bool
+	}{
+		{match: MatchExact, value: "/bin", item: "/bin", want: true},
+		{match: MatchExact, value: "/bin", item: "/bin/x"},
+		{match: MatchGlob, value: "/opt/*", item: "/opt/bin", want: true},
+		{match: MatchGlob, value: "[", wantErr: true},
+		{match: MatchRegex, value: "^/usr", item: "/usr/bin", want: true},
+		{match: MatchRegex, value: "(", wantErr: true},
+		{match: MatchPrefix, value: "/usr/", item: "/usr/bin", want: true},
+		{match: Match(-1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.match.String()+"_"+tt.value, func(t *testing.T) {
+			match, err := Matcher(tt.match, tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRule) {
+					t.Fatalf("Matcher() error = %v, want %v", err, ErrInvalidRule)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Matcher() error = %v", err)
+			}
+
+			if got := match(tt.item); got != tt.want {
+				t.Errorf("match(%q) = %v, want %v", tt.item, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithSort(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+	}{
+		{
+			name: "bytes",
+			opts: []Option[Config]{WithSort(strings.Compare)},
+			want: "/a:/b:/usr/lib:/usr/x",
+		},
+		{
+			name: "stable",
+			opts: []Option[Config]{WithSort(func(a, b string) int {
+				return cmp.Compare(len(a), len(b))
+			})},
+			want: "/b:/a:/usr/x:/usr/lib",
+		},
+		{
+			name: "within_groups",
+			opts: []Option[Config]{
+				WithSort(strings.Compare),
+				WithGroups(func(s string) bool { return strings.HasPrefix(s, "/usr/") }),
+			},
+			want: "/usr/lib:/usr/x:/a:/b",
+		},
+		{
+			name: "nil",
+			opts: []Option[Config]{WithSort(strings.Compare), WithSort(nil)},
+			want: "/usr/x:/b:/usr/lib:/a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubjectItems("/usr/x:/b:/usr/lib:/a"), WithDelim(":"),
+			}, tt.opts...)
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package mung
+
+import (
+	"iter"
+	"slices"
+)
+
+// Contains returns true if and only if the munged sequence of
+// [Config.Filtered] contains item.
+//
+// Item is normalized like the items split from the subject strings, and it
+// is compared with each munged string like items are compared to detect
+// duplicates, e.g., ignoring case under [WithCaseFold].
+// The sequence is only realized until item is found.
+func (c Config) Contains(item string) bool { return c.Index(item) >= 0 }
+
+// Index returns the position of item in the munged sequence of
+// [Config.Filtered], or -1 if it is not present.
+// Item is compared like by [Config.Contains].
+func (c Config) Index(item string) int {
+	p := c.prepare()
+	item = p.normalize(item)
+
+	i := 0
+
+	for s := range c.Filtered() {
+		if p.equalKeys(s, item) {
+			return i
+		}
+
+		i++
+	}
+
+	return -1
+}
+
+// Len returns the number of strings in the munged sequence of
+// [Config.Filtered].
+func (c Config) Len() int {
+	n := 0
+
+	for range c.Filtered() {
+		n++
+	}
+
+	return n
+}
+
+// Counts returns a sequence that yields each distinct item split from the
+// prefix, subject, and suffix strings, and the number of times it occurs
+// among them, e.g., to report items that are repeated in a PATH-like value.
+//
+// Items are counted before duplicates, removed items, and filtered items are
+// dropped, and they are compared like items are compared to detect
+// duplicates. Each item is yielded as its first occurrence, in the order
+// items are evaluated, i.e., prefix items first.
+func (c Config) Counts() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		p := c.prepare().materialize()
+
+		var (
+			items  []string
+			counts []int
+		)
+
+		index := map[string]int{}
+
+		for s := range p.split(Reverse(p.prefix), p.subject, p.suffix) {
+			var (
+				i  int
+				ok bool
+			)
+
+			if p.linear() {
+				i = slices.IndexFunc(items, func(t string) bool {
+					return p.equalKeys(t, s)
+				})
+				ok = i >= 0
+			} else {
+				i, ok = index[p.key(s)]
+			}
+
+			if ok {
+				counts[i]++
+
+				continue
+			}
+
+			if !p.linear() {
+				index[p.key(s)] = len(items)
+			}
+
+			items = append(items, s)
+			counts = append(counts, 1)
+		}
+
+		for i, s := range items {
+			if !yield(s, counts[i]) {
+				return
+			}
+		}
+	}
+}
+
+// First returns the first string of the munged sequence of [Config.Filtered]
+// and true, or the empty string and false if the sequence is empty.
+// Only the first string of the sequence is realized.
+func (c Config) First() (string, bool) { return c.Nth(0) }
+
+// Last returns the last string of the munged sequence of [Config.Filtered]
+// and true, or the empty string and false if the sequence is empty.
+func (c Config) Last() (string, bool) {
+	var (
+		last string
+		ok   bool
+	)
+
+	for s := range c.Filtered() {
+		last, ok = s, true
+	}
+
+	return last, ok
+}
+
+// Nth returns the string at position i of the munged sequence of
+// [Config.Filtered] and true, or the empty string and false if i is negative
+// or not less than the length of the sequence.
+// Only the first i+1 strings of the sequence are realized.
+func (c Config) Nth(i int) (string, bool) {
+	if i < 0 {
+		return "", false
+	}
+
+	for s := range c.Filtered() {
+		if i == 0 {
+			return s, true
+		}
+
+		i--
+	}
+
+	return "", false
+}
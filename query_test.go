@@ -0,0 +1,264 @@
+package mung
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfigIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option[Config]
+		item  string
+		index int
+	}{
+		{
+			name:  "empty",
+			opts:  []Option[Config]{WithSubject(nil)},
+			item:  "a",
+			index: -1,
+		},
+		{
+			name:  "first",
+			item:  "p",
+			index: 0,
+		},
+		{
+			name:  "subject",
+			item:  "b",
+			index: 2,
+		},
+		{
+			name:  "missing",
+			item:  "x",
+			index: -1,
+		},
+		{
+			name:  "removed",
+			opts:  []Option[Config]{WithRemoveItems("b")},
+			item:  "b",
+			index: -1,
+		},
+		{
+			name:  "replaced",
+			opts:  []Option[Config]{WithReplaceItem("b", "B")},
+			item:  "B",
+			index: 2,
+		},
+		{
+			name:  "filtered",
+			opts:  []Option[Config]{WithFilter(func(s string) bool { return s != "a" })},
+			item:  "b",
+			index: 1,
+		},
+		{
+			name:  "case_fold",
+			opts:  []Option[Config]{WithCaseFold()},
+			item:  "C",
+			index: 3,
+		},
+		{
+			name:  "normalized",
+			opts:  []Option[Config]{WithTrimSpace()},
+			item:  " c ",
+			index: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithPrefixItems("p"),
+				WithDelim(":"),
+			}, tt.opts...)
+			config := Make(opts...)
+
+			if got := config.Index(tt.item); got != tt.index {
+				t.Errorf("Index(%q) = %d, want %d", tt.item, got, tt.index)
+			}
+
+			if got := config.Contains(tt.item); got != (tt.index >= 0) {
+				t.Errorf("Contains(%q) = %v, want %v", tt.item, got, tt.index >= 0)
+			}
+		})
+	}
+}
+
+func TestConfigIndexEarly(t *testing.T) {
+	var evaluated []string
+
+	config := Make(
+		WithSubject([]string{"a:b:c:d"}),
+		WithDelim(":"),
+		WithFilter(func(s string) bool {
+			evaluated = append(evaluated, s)
+
+			return true
+		}),
+	)
+
+	if !config.Contains("b") {
+		t.Fatal("Contains(b) = false, want true")
+	}
+
+	if got := strings.Join(evaluated, ":"); got != "a:b" {
+		t.Errorf("Contains(b) evaluated %q, want %q", got, "a:b")
+	}
+}
+
+func TestConfigLen(t *testing.T) {
+	tests := []struct {
+		opts []Option[Config]
+		want int
+	}{
+		{nil, 0},
+		{[]Option[Config]{WithSubject([]string{"a:b:a"})}, 2},
+		{[]Option[Config]{WithSubject([]string{"a:b:a"}), WithAllowDuplicates()}, 3},
+		{[]Option[Config]{WithSubject([]string{"a:b"}), WithPrefixItems("c")}, 3},
+	}
+
+	for _, tt := range tests {
+		opts := append([]Option[Config]{WithDelim(":")}, tt.opts...)
+		config := Make(opts...)
+
+		if got := config.Len(); got != tt.want {
+			t.Errorf("Len() of %q = %d, want %d", config.String(), got, tt.want)
+		}
+	}
+}
+
+func TestConfigCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option[Config]
+		items  []string
+		counts []int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "distinct",
+			opts:   []Option[Config]{WithSubject([]string{"a:b"})},
+			items:  []string{"a", "b"},
+			counts: []int{1, 1},
+		},
+		{
+			name: "sources",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:a:c:a"}),
+				WithPrefixItems("c", "b"),
+				WithSuffixItems("a"),
+				WithRemoveItems("c"),
+			},
+			items:  []string{"b", "c", "a"},
+			counts: []int{2, 2, 4},
+		},
+		{
+			name: "case_fold",
+			opts: []Option[Config]{
+				WithSubject([]string{"A:b:a:B"}),
+				WithCaseFold(),
+			},
+			items:  []string{"A", "b"},
+			counts: []int{2, 2},
+		},
+		{
+			name: "equal",
+			opts: []Option[Config]{
+				WithSubject([]string{"a1:b1:a2"}),
+				WithEqual(func(a, b string) bool { return a[0] == b[0] }),
+			},
+			items:  []string{"a1", "b1"},
+			counts: []int{2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":")}, tt.opts...)
+
+			var (
+				items  []string
+				counts []int
+			)
+
+			for s, n := range Make(opts...).Counts() {
+				items = append(items, s)
+				counts = append(counts, n)
+			}
+
+			if !slicesEqual(items, tt.items) || !slices.Equal(counts, tt.counts) {
+				t.Errorf("Counts() = %v %v, want %v %v",
+					items, counts, tt.items, tt.counts)
+			}
+		})
+	}
+}
+
+func TestConfigNth(t *testing.T) {
+	config := Make(
+		WithSubject([]string{"a:b:a:c"}),
+		WithPrefixItems("p"),
+		WithDelim(":"),
+	)
+
+	tests := []struct {
+		i    int
+		want string
+		ok   bool
+	}{
+		{-1, "", false},
+		{0, "p", true},
+		{1, "a", true},
+		{3, "c", true},
+		{4, "", false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := config.Nth(tt.i); got != tt.want || ok != tt.ok {
+			t.Errorf("Nth(%d) = %q, %v, want %q, %v", tt.i, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got, ok := config.First(); got != "p" || !ok {
+		t.Errorf("First() = %q, %v, want %q, true", got, ok, "p")
+	}
+
+	if got, ok := config.Last(); got != "c" || !ok {
+		t.Errorf("Last() = %q, %v, want %q, true", got, ok, "c")
+	}
+
+	var empty Config
+	if got, ok := empty.First(); got != "" || ok {
+		t.Errorf("First() of empty = %q, %v, want empty, false", got, ok)
+	}
+
+	if got, ok := empty.Last(); got != "" || ok {
+		t.Errorf("Last() of empty = %q, %v, want empty, false", got, ok)
+	}
+}
+
+func TestConfigFirstEarly(t *testing.T) {
+	var evaluated []string
+
+	config := Make(
+		WithSubject([]string{"a:b:c"}),
+		WithDelim(":"),
+		WithFilter(func(s string) bool {
+			evaluated = append(evaluated, s)
+
+			return s != "a"
+		}),
+	)
+
+	if got, ok := config.First(); got != "b" || !ok {
+		t.Errorf("First() = %q, %v, want %q, true", got, ok, "b")
+	}
+
+	if got := strings.Join(evaluated, ":"); got != "a:b" {
+		t.Errorf("First() evaluated %q, want %q", got, "a:b")
+	}
+}
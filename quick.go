@@ -0,0 +1,47 @@
+package mung
+
+// Prepend returns value with item moved or added to the front, where the
+// items of value and item are delimited by delim, e.g.,
+// Prepend(os.Getenv("PATH"), "/usr/local/bin", ":").
+//
+// Prepend, like [Append], [Remove], and [Clean], also removes empty and
+// duplicate items from value. For anything else, use [Make] with options.
+func Prepend(value, item, delim string) string {
+	return Make(
+		WithSubject([]string{value}), WithDelim(delim), WithPrefixItems(item),
+	).String()
+}
+
+// Append returns value with item moved or added to the end, where the items
+// of value and item are delimited by delim.
+func Append(value, item, delim string) string {
+	return Make(
+		WithSubject([]string{value}), WithDelim(delim), WithSuffixItems(item),
+	).String()
+}
+
+// Remove returns value without item, where the items of value and item are
+// delimited by delim.
+func Remove(value, item, delim string) string {
+	return Make(
+		WithSubject([]string{value}), WithDelim(delim), WithRemoveItems(item),
+	).String()
+}
+
+// Clean returns value without empty and duplicate items, where the items of
+// value are delimited by delim. The first occurrence of each item is kept.
+func Clean(value, delim string) string {
+	return Make(WithSubject([]string{value}), WithDelim(delim)).String()
+}
+
+// Has returns true if and only if value contains item, where the items of
+// value are delimited by delim.
+func Has(value, item, delim string) bool {
+	for s := range Make(WithSubject([]string{value}), WithDelim(delim)).All() {
+		if s == item {
+			return true
+		}
+	}
+
+	return false
+}
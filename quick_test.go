@@ -0,0 +1,48 @@
+package mung
+
+import "testing"
+
+func TestQuick(t *testing.T) {
+	const value = "/usr/bin::/bin:/usr/bin:/sbin"
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"prepend_new", Prepend(value, "/usr/local/bin", ":"), "/usr/local/bin:/usr/bin:/bin:/sbin"},
+		{"prepend_existing", Prepend(value, "/sbin", ":"), "/sbin:/usr/bin:/bin"},
+		{"prepend_empty", Prepend("", "/bin", ":"), "/bin"},
+		{"append_new", Append(value, "/opt/bin", ":"), "/usr/bin:/bin:/sbin:/opt/bin"},
+		{"append_existing", Append(value, "/usr/bin", ":"), "/bin:/sbin:/usr/bin"},
+		{"remove", Remove(value, "/usr/bin", ":"), "/bin:/sbin"},
+		{"remove_missing", Remove(value, "/opt/bin", ":"), "/usr/bin:/bin:/sbin"},
+		{"clean", Clean(value, ":"), "/usr/bin:/bin:/sbin"},
+		{"delim", Prepend("a;b", "c:d", ";"), "c:d;a;b"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestHas(t *testing.T) {
+	tests := []struct {
+		value, item, delim string
+		want               bool
+	}{
+		{"/usr/bin:/bin", "/bin", ":", true},
+		{"/usr/bin:/bin", "/usr", ":", false},
+		{"/usr/bin:/bin", "", ":", false},
+		{"", "/bin", ":", false},
+		{"a;b", "b", ";", true},
+	}
+
+	for _, tt := range tests {
+		if got := Has(tt.value, tt.item, tt.delim); got != tt.want {
+			t.Errorf("Has(%q, %q) = %v, want %v", tt.value, tt.item, got, tt.want)
+		}
+	}
+}
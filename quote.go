@@ -0,0 +1,51 @@
+package mung
+
+import (
+	"iter"
+	"strings"
+)
+
+// WithQuote returns an option that sets the function used to quote each item
+// when items are joined into a string, e.g., with [QuoteShell] for output
+// evaluated by a shell, or with a function escaping items for a Dockerfile
+// or YAML document.
+//
+// Items are quoted only when joined, e.g., by [Config.String],
+// [Config.AppendTo], and [Plan.Apply], immediately before the [Tokenizer]
+// joins them. Items are compared, and yielded by [Config.All] and its
+// variants, unquoted. A nil quote function leaves items unquoted (default).
+func WithQuote(quote func(string) string) Option[Config] {
+	return func(config Config) Config {
+		config.quote = quote
+
+		return config
+	}
+}
+
+// QuoteShell returns s quoted for a POSIX shell, so that the shell reads it
+// as a single word equal to s, e.g., to paste it into a command given to
+// sh -c.
+func QuoteShell(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	// Close quote, insert escaped single quote, reopen: '"'"'
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// quoted returns a sequence that yields each of items quoted with the
+// receiver's quote function, or items itself if there is none.
+func (c Config) quoted(items iter.Seq[string]) iter.Seq[string] {
+	if c.quote == nil {
+		return items
+	}
+
+	return func(yield func(string) bool) {
+		for s := range items {
+			if !yield(c.quote(s)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package mung
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestWithQuote(t *testing.T) {
+	config := Make(
+		WithSubjectItems("/a b:/c's:/a b"),
+		WithDelim(":"),
+		WithOutputDelim(" "),
+		WithRemoveItems("/x"),
+		WithQuote(QuoteShell),
+	)
+
+	want := `'/a b' '/c'"'"'s'`
+
+	if got := config.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if got, err := config.StringErr(); got != want || err != nil {
+		t.Errorf("StringErr() = %q, %v, want %q, nil", got, err, want)
+	}
+
+	if got := string(config.AppendTo(nil)); got != want {
+		t.Errorf("AppendTo() = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if _, err := config.WriteTo(&buf); err != nil || buf.String() != want {
+		t.Errorf("WriteTo() = %q, %v, want %q, nil", buf.String(), err, want)
+	}
+
+	if got, _ := config.Run(); got != want {
+		t.Errorf("Run() = %q, want %q", got, want)
+	}
+
+	if got := config.Plan().Apply(); got != want {
+		t.Errorf("Plan().Apply() = %q, want %q", got, want)
+	}
+
+	// Items are compared and yielded unquoted.
+	if got, want := slices.Collect(config.All()), []string{"/a b", "/c's"}; !slicesEqual(got, want) {
+		t.Errorf("All() = %q, want %q", got, want)
+	}
+
+	quoted := Wrap(config, WithQuote(strconv.Quote))
+	if got, want := quoted.String(), `"/a b" "/c's"`; got != want {
+		t.Errorf("String() with strconv.Quote = %q, want %q", got, want)
+	}
+
+	if got, want := Wrap(config, WithQuote(nil)).String(), "/a b /c's"; got != want {
+		t.Errorf("String() without quoting = %q, want %q", got, want)
+	}
+}
+
+func TestQuoteShell(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{in: "", want: "''"},
+		{in: "a", want: "'a'"},
+		{in: "a b", want: "'a b'"},
+		{in: "it's", want: `'it'"'"'s'`},
+		{in: "$HOME", want: "'$HOME'"},
+	}
+
+	for _, tt := range tests {
+		if got := QuoteShell(tt.in); got != tt.want {
+			t.Errorf("QuoteShell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
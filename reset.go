@@ -0,0 +1,61 @@
+package mung
+
+// WithoutRemove returns an option that clears the strings to remove, and the
+// rules of [WithRules] removing items by pattern, e.g., to specialize a base
+// configuration that removes items a caller wants to keep.
+//
+// Items excluded by [WithExclude] are still excluded; use WithExclude(nil) to
+// clear them.
+func WithoutRemove() Option[Config] {
+	return func(config Config) Config {
+		config.remove, config.sources.remove = nil, nil
+		config.removals = nil
+
+		return config
+	}
+}
+
+// WithoutPrefix returns an option that clears the strings to prepend.
+func WithoutPrefix() Option[Config] {
+	return func(config Config) Config {
+		config.prefix, config.sources.prefix = nil, nil
+
+		return config.untagged(ScopePrefix)
+	}
+}
+
+// WithoutSuffix returns an option that clears the strings to append.
+func WithoutSuffix() Option[Config] {
+	return func(config Config) Config {
+		config.suffix, config.sources.suffix = nil, nil
+
+		return config.untagged(ScopeSuffix)
+	}
+}
+
+// WithoutReplace returns an option that clears the replacement rules,
+// including the rules of [WithRules] replacing items by pattern.
+func WithoutReplace() Option[Config] {
+	return func(config Config) Config {
+		config.replace, config.rewrites = nil, nil
+
+		return config
+	}
+}
+
+// WithoutFilter returns an option that clears the predicate function set with
+// [WithFilter] or any of its variants, including [WithFilterErr],
+// [WithFilterContext], and [WithBatchFilter], so that every item is
+// selected unless rejected by the built-in filters.
+//
+// The exclusion predicate function set with [WithExclude] is not cleared.
+func WithoutFilter() Option[Config] {
+	return func(config Config) Config {
+		config.predicate = nil
+		config.predicateErr = nil
+		config.predicateCtx = nil
+		config.batch = nil
+
+		return config
+	}
+}
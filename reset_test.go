@@ -0,0 +1,85 @@
+package mung
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWithout(t *testing.T) {
+	base := Make(
+		WithSubject([]string{"a:b:c:ab"}),
+		WithDelim(":"),
+		WithRemoveItems("c"),
+		WithPrefixItems("p"),
+		WithSuffixSeq(slices.Values([]string{"s"})),
+		WithReplaceItem("a", "x"),
+		WithFilter(func(s string) bool { return s != "b" }),
+		WithExclude(func(s string) bool { return strings.HasPrefix(s, "ab") }),
+	)
+
+	tests := []struct {
+		name string
+		opt  Option[Config]
+		want []string
+	}{
+		{
+			name: "base",
+			opt:  func(c Config) Config { return c },
+			want: []string{"p", "x", "s"},
+		},
+		{
+			name: "remove",
+			opt:  WithoutRemove(),
+			want: []string{"p", "x", "c", "s"},
+		},
+		{
+			name: "prefix",
+			opt:  WithoutPrefix(),
+			want: []string{"x", "s"},
+		},
+		{
+			name: "suffix",
+			opt:  WithoutSuffix(),
+			want: []string{"p", "x"},
+		},
+		{
+			name: "replace",
+			opt:  WithoutReplace(),
+			want: []string{"p", "a", "s"},
+		},
+		{
+			name: "filter",
+			opt:  WithoutFilter(),
+			want: []string{"p", "x", "b", "s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := base.Clone()
+
+			got := slices.Collect(Wrap(base, tt.opt).Filtered())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Filtered() = %q, want %q", got, tt.want)
+			}
+
+			if !base.Equal(before) {
+				t.Errorf("option modified base = %+v, want %+v", base, before)
+			}
+		})
+	}
+}
+
+func TestWithoutEqual(t *testing.T) {
+	got := Make(
+		WithRemoveItems("r"), WithPrefixItems("p"), WithSuffixItems("s"),
+		WithReplaceItem("a", "b"), WithBatchFilter(func([]string) []bool { return nil }),
+		WithoutRemove(), WithoutPrefix(), WithoutSuffix(), WithoutReplace(),
+		WithoutFilter(),
+	)
+
+	if want := (Config{}); !got.Equal(want) {
+		t.Errorf("Make() = %+v, want %+v", got, want)
+	}
+}
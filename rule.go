@@ -0,0 +1,483 @@
+package mung
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidRule is wrapped by errors reporting a [Rule] that cannot be
+// compiled into options.
+var ErrInvalidRule = errors.New("invalid rule")
+
+// Action identifies the operation of a [Rule].
+type Action int
+
+// Constant values of Action.
+const (
+	// ActionPrepend prepends the rule's value, as if by [WithPrefixItems].
+	ActionPrepend Action = iota + 1
+	// ActionAppend appends the rule's value, as if by [WithSuffixItems].
+	ActionAppend
+	// ActionRemove removes the items matched by the rule.
+	ActionRemove
+	// ActionReplace replaces the items matched by the rule with the rule's
+	// replacement.
+	ActionReplace
+	// ActionEnsure appends the rule's value unless it is already yielded.
+	ActionEnsure
+)
+
+// String returns the name of the action.
+func (a Action) String() string {
+	switch a {
+	case ActionPrepend:
+		return "prepend"
+	case ActionAppend:
+		return "append"
+	case ActionRemove:
+		return "remove"
+	case ActionReplace:
+		return "replace"
+	case ActionEnsure:
+		return "ensure"
+	default:
+		return fmt.Sprintf("Action(%d)", int(a))
+	}
+}
+
+// MarshalText implements [encoding.TextMarshaler], encoding the action as its
+// name.
+func (a Action) MarshalText() ([]byte, error) {
+	if a < ActionPrepend || a > ActionEnsure {
+		return nil, fmt.Errorf("%w: unknown action %s", ErrInvalidRule, a)
+	}
+
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], decoding the action
+// from its name.
+func (a *Action) UnmarshalText(text []byte) error {
+	for v := ActionPrepend; v <= ActionEnsure; v++ {
+		if v.String() == string(text) {
+			*a = v
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown action %q", ErrInvalidRule, text)
+}
+
+// Match identifies how the value of a [Rule] matches items.
+type Match int
+
+// Constant values of Match.
+const (
+	// MatchExact matches items equal to the value (default).
+	MatchExact Match = iota
+	// MatchGlob matches items with the shell pattern value.
+	// See [path.Match].
+	MatchGlob
+	// MatchRegex matches items containing a match of the regular expression
+	// value. See [regexp.Compile].
+	MatchRegex
+	// MatchPrefix matches items that begin with the value.
+	MatchPrefix
+)
+
+// String returns the name of the match kind.
+func (m Match) String() string {
+	switch m {
+	case MatchExact:
+		return "exact"
+	case MatchGlob:
+		return "glob"
+	case MatchRegex:
+		return "regex"
+	case MatchPrefix:
+		return "prefix"
+	default:
+		return fmt.Sprintf("Match(%d)", int(m))
+	}
+}
+
+// MarshalText implements [encoding.TextMarshaler], encoding the match kind as
+// its name.
+func (m Match) MarshalText() ([]byte, error) {
+	if m < MatchExact || m > MatchPrefix {
+		return nil, fmt.Errorf("%w: unknown match %s", ErrInvalidRule, m)
+	}
+
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], decoding the match
+// kind from its name. Empty text decodes as [MatchExact].
+func (m *Match) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = MatchExact
+
+		return nil
+	}
+
+	for v := MatchExact; v <= MatchPrefix; v++ {
+		if v.String() == string(text) {
+			*m = v
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown match %q", ErrInvalidRule, text)
+}
+
+// Rule describes a single munge operation as data, e.g., to express a policy
+// loaded at runtime instead of assembling options in code.
+//
+// Rules are compiled into options with [WithRules].
+// Items are matched by Value according to Match, which must be [MatchExact]
+// for [ActionPrepend], [ActionAppend], and [ActionEnsure].
+//
+// Rules are encoded as JSON objects with the fields "action", "match",
+// "value", and "replacement", where the action and match kind are encoded
+// by name, e.g., {"action": "remove", "match": "glob", "value": "/opt/*"}.
+// See [ParseRules].
+type Rule struct {
+	// Action is the operation of the rule.
+	Action Action `json:"action"`
+	// Match is how Value matches items, for [ActionRemove] and
+	// [ActionReplace].
+	Match Match `json:"match,omitempty"`
+	// Value is the item to prepend, append, or ensure, or the pattern of the
+	// items to remove or replace.
+	Value string `json:"value"`
+	// Replacement is the item yielded instead of each item matched, for
+	// [ActionReplace].
+	//
+	// Under [MatchPrefix], only the matched prefix of each item is replaced.
+	// Under [MatchRegex], each match of the regular expression is replaced,
+	// and Replacement may refer to submatches as in [regexp.Regexp.Expand].
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// ParseRules decodes a JSON array of rules from r, e.g., to load a policy from
+// a configuration file. See [Rule] for the encoding of each rule.
+//
+// Each rule is validated as if by [WithRules], and unknown fields are
+// rejected. Errors identify the offending rule by its index in the array.
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+
+	rules := make([]Rule, len(raw))
+
+	for i, msg := range raw {
+		dec := json.NewDecoder(bytes.NewReader(msg))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(&rules[i]); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		if _, err := rules[i].option(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return rules, nil
+}
+
+// WithRules returns an option that compiles each rule into the existing
+// options, in order, failing with an error wrapping [ErrInvalidRule] that
+// identifies the first rule that cannot be compiled.
+//
+// Rules matching items exactly are compiled as if by [WithPrefixItems],
+// [WithSuffixItems], [WithRemoveItems], and [WithReplaceItem], and so are
+// subject to the same normalization and comparison as those options.
+// Otherwise, items removed by pattern are removed along with the strings set
+// with [WithRemove], in the scope set with [WithRemoveScope], and items
+// replaced by pattern are rewritten, in order, immediately after the
+// replacement rules set with [WithReplace] are applied.
+// Ensured items are normalized and compared like other items, and are
+// appended after ordering by [WithPriority] and [WithSort], and before the
+// stages set with [WithStages].
+//
+// Items removed by [MatchPrefix] rules are matched against all such rules at
+// once using a radix tree, in time proportional to the length of the item
+// rather than the number of rules, e.g., to apply generated lists of
+// thousands of excluded directories.
+func WithRules(rules ...Rule) OptionE[Config] {
+	return func(config Config) (Config, error) {
+		opts := make([]Option[Config], 0, len(rules))
+
+		var prefixes []Rule
+
+		for i, rule := range rules {
+			opt, err := rule.option()
+			if err != nil {
+				return config, fmt.Errorf("rule %d: %w", i, err)
+			}
+
+			if rule.Action == ActionRemove && rule.Match == MatchPrefix {
+				prefixes = append(prefixes, rule)
+
+				continue
+			}
+
+			opts = append(opts, opt)
+		}
+
+		if len(prefixes) > 0 {
+			tree := newPrefixTree()
+			for _, rule := range prefixes {
+				tree.insert(rule.Value)
+			}
+
+			opts = append(opts, removeMatching(removal{
+				rules: prefixes, match: tree.prefixOf,
+			}))
+		}
+
+		return Wrap(config, opts...), nil
+	}
+}
+
+// option returns the option that applies the receiver.
+func (r Rule) option() (Option[Config], error) {
+	if r.Value == "" {
+		return nil, fmt.Errorf("%w: empty value", ErrInvalidRule)
+	}
+
+	switch r.Action {
+	case ActionPrepend, ActionAppend, ActionEnsure:
+		if r.Match != MatchExact {
+			return nil, fmt.Errorf("%w: %s does not support %s match",
+				ErrInvalidRule, r.Action, r.Match)
+		}
+	case ActionRemove, ActionReplace:
+	default:
+		return nil, fmt.Errorf("%w: unknown action %s", ErrInvalidRule, r.Action)
+	}
+
+	switch r.Action {
+	case ActionPrepend:
+		return WithPrefixItems(r.Value), nil
+
+	case ActionAppend:
+		return WithSuffixItems(r.Value), nil
+
+	case ActionEnsure:
+		return ensuring(r.Value), nil
+	}
+
+	if r.Match == MatchExact {
+		if r.Action == ActionRemove {
+			return WithRemoveItems(r.Value), nil
+		}
+
+		return WithReplaceItem(r.Value, r.Replacement), nil
+	}
+
+	match, rewrite, err := r.matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Action == ActionRemove {
+		return removeMatching(removal{
+			rules: []Rule{r},
+			match: func(s string) (string, bool) { return r.Value, match(s) },
+		}), nil
+	}
+
+	return rewriting(rewriter{rule: r, match: match, rewrite: rewrite}), nil
+}
+
+// matcher returns a function that matches items with the receiver's value,
+// and a function that rewrites each matched item with the receiver's
+// replacement.
+func (r Rule) matcher() (
+	match func(string) bool, rewrite func(string) string, err error,
+) {
+	replace := func(string) string { return r.Replacement }
+
+	switch r.Match {
+	case MatchGlob:
+		if _, err := path.Match(r.Value, ""); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+
+		return func(s string) bool {
+			ok, _ := path.Match(r.Value, s)
+
+			return ok
+		}, replace, nil
+
+	case MatchRegex:
+		re, err := regexp.Compile(r.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+
+		return re.MatchString, func(s string) string {
+			return re.ReplaceAllString(s, r.Replacement)
+		}, nil
+
+	case MatchPrefix:
+		match = func(s string) bool { return strings.HasPrefix(s, r.Value) }
+		rewrite = func(s string) string {
+			return r.Replacement + strings.TrimPrefix(s, r.Value)
+		}
+
+		return match, rewrite, nil
+
+	case MatchExact:
+		return func(s string) bool { return s == r.Value }, replace, nil
+
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown match %s", ErrInvalidRule, r.Match)
+	}
+}
+
+// removal holds rules of [WithRules] that remove items by pattern.
+type removal struct {
+	rules []Rule
+	// match returns the pattern of the rule removing item s and true, or
+	// false if no rule matches s.
+	match func(s string) (rule string, ok bool)
+}
+
+// removeMatching returns an option that also removes the items matched by
+// the rules of r.
+func removeMatching(r removal) Option[Config] {
+	return func(config Config) Config {
+		config.removals = append(slices.Clip(config.removals), r)
+
+		return config
+	}
+}
+
+// removalOf returns the pattern of the first rule removing item s by pattern
+// and true, or false if no such rule matches s.
+func (c Config) removalOf(s string) (string, bool) {
+	for _, r := range c.removals {
+		if rule, ok := r.match(s); ok {
+			return rule, true
+		}
+	}
+
+	return "", false
+}
+
+// removalSet is a set of strings to remove that also contains each item
+// removed by pattern.
+type removalSet struct {
+	set[string]
+
+	match func(string) (string, bool)
+}
+
+// Contains returns true if and only if item is in the set or is removed by
+// pattern.
+func (s removalSet) Contains(item string) bool {
+	if s.set.Contains(item) {
+		return true
+	}
+
+	_, ok := s.match(item)
+
+	return ok
+}
+
+// rewriter is a rule of [WithRules] that replaces items by pattern.
+type rewriter struct {
+	rule    Rule
+	match   func(string) bool
+	rewrite func(string) string
+}
+
+// rewriting returns an option that also replaces the items matched by the
+// rule of r.
+func rewriting(r rewriter) Option[Config] {
+	return func(config Config) Config {
+		config.rewrites = append(slices.Clip(config.rewrites), r)
+
+		return config
+	}
+}
+
+// rewriteStage returns a [Stage] that rewrites each item matched by a rule
+// replacing items by pattern, in order, or nil if there are none.
+func (c Config) rewriteStage() Stage {
+	if len(c.rewrites) == 0 {
+		return nil
+	}
+
+	return func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for s := range items {
+				for _, r := range c.rewrites {
+					if r.match(s) {
+						s = r.rewrite(s)
+					}
+				}
+
+				if !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ensuring returns an option that appends the items of str unless they are
+// already yielded.
+func ensuring(str string) Option[Config] {
+	return func(config Config) Config {
+		config.ensure = append(slices.Clip(config.ensure), str)
+
+		return config
+	}
+}
+
+// ensureStage returns a [Stage] that appends each item of the receiver's
+// ensured strings unless it is already yielded, or nil if there are none.
+//
+// Ensured items are normalized and compared with yielded items like the
+// receiver's other items.
+func (c Config) ensureStage() Stage {
+	if len(c.ensure) == 0 {
+		return nil
+	}
+
+	return func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			seen := c.newSet()
+
+			for s := range items {
+				seen.Add(s)
+
+				if !yield(s) {
+					return
+				}
+			}
+
+			for s := range c.split(c.ensure) {
+				if !seen.Seen(s) && !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package mung
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWithRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option[Config]
+		rules []Rule
+		want  string
+	}{
+		{
+			name: "none",
+			want: "/usr/bin:/bin:/old/bin",
+		},
+		{
+			name: "prepend_append",
+			rules: []Rule{
+				{Action: ActionPrepend, Value: "/usr/local/bin"},
+				{Action: ActionAppend, Value: "/opt/bin"},
+				{Action: ActionAppend, Value: "/usr/bin"},
+			},
+			want: "/usr/local/bin:/bin:/old/bin:/opt/bin:/usr/bin",
+		},
+		{
+			name:  "remove_exact",
+			rules: []Rule{{Action: ActionRemove, Value: "/old/bin"}},
+			want:  "/usr/bin:/bin",
+		},
+		{
+			name: "remove_patterns",
+			opts: []Option[Config]{WithSubjectItems("/usr/sbin", "/opt/x/bin")},
+			rules: []Rule{
+				{Action: ActionRemove, Match: MatchGlob, Value: "/*/sbin"},
+				{Action: ActionRemove, Match: MatchPrefix, Value: "/old/"},
+				{Action: ActionRemove, Match: MatchRegex, Value: `^/opt/.+/bin$`},
+			},
+			want: "/usr/bin:/bin",
+		},
+		{
+			name: "remove_pattern_with_exclude",
+			opts: []Option[Config]{
+				WithExclude(func(s string) bool { return s == "/bin" }),
+			},
+			rules: []Rule{{Action: ActionRemove, Match: MatchGlob, Value: "/old/*"}},
+			want:  "/usr/bin",
+		},
+		{
+			name: "replace_exact",
+			rules: []Rule{
+				{Action: ActionReplace, Value: "/bin", Replacement: "/sbin"},
+			},
+			want: "/usr/bin:/sbin:/old/bin",
+		},
+		{
+			name: "replace_patterns",
+			rules: []Rule{
+				{
+					Action: ActionReplace, Match: MatchPrefix,
+					Value: "/usr", Replacement: "/opt",
+				},
+				{
+					Action: ActionReplace, Match: MatchRegex,
+					Value: `^/(\w+)/bin$`, Replacement: "/new/$1",
+				},
+				{
+					Action: ActionReplace, Match: MatchGlob,
+					Value: "/new/op?", Replacement: "/glob",
+				},
+			},
+			want: "/glob:/bin:/new/old",
+		},
+		{
+			name: "ensure",
+			rules: []Rule{
+				{Action: ActionEnsure, Value: "/bin"},
+				{Action: ActionEnsure, Value: "/sbin"},
+			},
+			want: "/usr/bin:/bin:/old/bin:/sbin",
+		},
+		{
+			name: "ensure_normalized",
+			opts: []Option[Config]{WithCaseFold(), WithCleanPaths()},
+			rules: []Rule{
+				{Action: ActionEnsure, Value: "/USR/bin/"},
+				{Action: ActionEnsure, Value: "/sbin/"},
+				{Action: ActionEnsure, Value: "/SBIN"},
+			},
+			want: "/usr/bin:/bin:/old/bin:/sbin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"/usr/bin:/bin:/old/bin"}),
+				WithDelim(":"),
+			}, tt.opts...)
+
+			config, err := TryWrap(Make(opts...), WithRules(tt.rules...))
+			if err != nil {
+				t.Fatalf("WithRules() error = %v", err)
+			}
+
+			if got := config.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+		want  string
+	}{
+		{
+			name:  "empty_value",
+			rules: []Rule{{Action: ActionRemove}},
+			want:  "rule 0: invalid rule: empty value",
+		},
+		{
+			name:  "unknown_action",
+			rules: []Rule{{Value: "a"}},
+			want:  "rule 0: invalid rule: unknown action Action(0)",
+		},
+		{
+			name: "unknown_match",
+			rules: []Rule{
+				{Action: ActionRemove, Value: "a"},
+				{Action: ActionRemove, Match: Match(9), Value: "a"},
+			},
+			want: "rule 1: invalid rule: unknown match Match(9)",
+		},
+		{
+			name:  "unsupported_match",
+			rules: []Rule{{Action: ActionPrepend, Match: MatchGlob, Value: "a*"}},
+			want:  "rule 0: invalid rule: prepend does not support glob match",
+		},
+		{
+			name:  "bad_glob",
+			rules: []Rule{{Action: ActionRemove, Match: MatchGlob, Value: "["}},
+			want:  "rule 0: invalid rule: syntax error in pattern",
+		},
+		{
+			name:  "bad_regex",
+			rules: []Rule{{Action: ActionRemove, Match: MatchRegex, Value: "("}},
+			want:  "rule 0: invalid rule: error parsing regexp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := TryMake(WithRules(tt.rules...))
+			if !errors.Is(err, ErrInvalidRule) {
+				t.Fatalf("WithRules() error = %v, want %v", err, ErrInvalidRule)
+			}
+
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("WithRules() error = %q, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	input := `[
+		{"action": "prepend", "value": "/usr/local/bin"},
+		{"action": "remove", "match": "glob", "value": "/opt/*"},
+		{"action": "replace", "match": "prefix", "value": "/usr",
+		 "replacement": "/opt"},
+		{"action": "ensure", "match": "", "value": "/bin"}
+	]`
+
+	want := []Rule{
+		{Action: ActionPrepend, Value: "/usr/local/bin"},
+		{Action: ActionRemove, Match: MatchGlob, Value: "/opt/*"},
+		{
+			Action: ActionReplace, Match: MatchPrefix,
+			Value: "/usr", Replacement: "/opt",
+		},
+		{Action: ActionEnsure, Value: "/bin"},
+	}
+
+	rules, err := ParseRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("ParseRules() = %+v, want %+v", rules, want)
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	again, err := ParseRules(strings.NewReader(string(data)))
+	if err != nil || !reflect.DeepEqual(again, want) {
+		t.Errorf("ParseRules(%s) = %+v, %v, want %+v", data, again, err, want)
+	}
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		rule  bool
+	}{
+		{
+			name:  "syntax",
+			input: `[{"action": "remove"`,
+			want:  "rules: ",
+		},
+		{
+			name:  "not_array",
+			input: `{"action": "remove", "value": "a"}`,
+			want:  "rules: ",
+		},
+		{
+			name:  "unknown_action",
+			input: `[{"action": "remove", "value": "a"}, {"action": "drop"}]`,
+			want:  `rule 1: invalid rule: unknown action "drop"`,
+			rule:  true,
+		},
+		{
+			name:  "unknown_match",
+			input: `[{"action": "remove", "match": "fuzzy", "value": "a"}]`,
+			want:  `rule 0: invalid rule: unknown match "fuzzy"`,
+			rule:  true,
+		},
+		{
+			name:  "unknown_field",
+			input: `[{"action": "remove", "value": "a", "scope": "all"}]`,
+			want:  `rule 0: json: unknown field "scope"`,
+		},
+		{
+			name: "invalid_rule",
+			input: `[{"action": "append", "value": "a"},
+				{"action": "append", "match": "regex", "value": "a"}]`,
+			want: "rule 1: invalid rule: append does not support regex match",
+			rule: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("ParseRules() error = nil, want error")
+			}
+
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("ParseRules() error = %q, want prefix %q", err, tt.want)
+			}
+
+			if errors.Is(err, ErrInvalidRule) != tt.rule {
+				t.Errorf("ParseRules() error = %v, is %v = %v",
+					err, ErrInvalidRule, !tt.rule)
+			}
+		})
+	}
+}
+
+func TestRuleMarshalInvalid(t *testing.T) {
+	for _, rule := range []Rule{
+		{Value: "a"},
+		{Action: ActionRemove, Match: Match(-1), Value: "a"},
+	} {
+		if _, err := json.Marshal(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("json.Marshal(%+v) error = %v, want %v",
+				rule, err, ErrInvalidRule)
+		}
+	}
+}
+
+func TestWithRulesReused(t *testing.T) {
+	opt := WithRules(Rule{Action: ActionRemove, Match: MatchGlob, Value: "/opt/*"})
+
+	a, errA := TryMake(Try(WithExclude(func(s string) bool { return s == "/a" })), opt)
+	b, errB := TryMake(Try(WithSubject([]string{"/a:/b:/opt/x"}), WithDelim(":")), opt)
+
+	if errA != nil || errB != nil {
+		t.Fatalf("TryMake() errors = %v, %v", errA, errB)
+	}
+
+	if a.exclude == nil || !a.exclude("/a") || a.exclude("/opt/x") {
+		t.Errorf("exclude() changed by remove rule")
+	}
+
+	if got, want := b.String(), "/a:/b"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestWithRulesRemovePattern(t *testing.T) {
+	rules := []Rule{
+		{Action: ActionRemove, Match: MatchGlob, Value: "/opt/*"},
+		{Action: ActionRemove, Match: MatchPrefix, Value: "/tmp/"},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+	}{
+		{
+			name: "all",
+			want: "/p:/usr/bin",
+		},
+		{
+			name: "filter_scope",
+			opts: []Option[Config]{WithFilterScope(ScopePrefix)},
+			want: "/p:/usr/bin",
+		},
+		{
+			name: "remove_scope",
+			opts: []Option[Config]{WithRemoveScope(ScopePrefix)},
+			want: "/p:/opt/a:/usr/bin:/tmp/x:/opt/b",
+		},
+		{
+			name: "without_remove",
+			opts: []Option[Config]{WithoutRemove()},
+			want: "/p:/opt/a:/usr/bin:/tmp/x:/opt/b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := TryMake(
+				Try(
+					WithSubjectItems("/opt/a", "/usr/bin", "/tmp/x", "/opt/b"),
+					WithPrefixItems("/p"), WithDelim(":"),
+				),
+				WithRules(rules...), Try(tt.opts...),
+			)
+			if err != nil {
+				t.Fatalf("TryMake() error = %v", err)
+			}
+
+			got := strings.Join(slices.Collect(config.All()), ":")
+			if got != tt.want {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var removed []string
+
+	config, err := TryMake(
+		Try(
+			WithSubjectItems("/opt/a", "/usr/bin", "/tmp/x"), WithDelim(":"),
+			WithOnRemove(func(item, rule string) {
+				removed = append(removed, item+"="+rule)
+			}),
+		),
+		WithRules(rules...),
+	)
+	if err != nil {
+		t.Fatalf("TryMake() error = %v", err)
+	}
+
+	_ = config.String()
+
+	want := []string{"/opt/a=/opt/*", "/tmp/x=/tmp/"}
+	if !slices.Equal(removed, want) {
+		t.Errorf("OnRemove() called with %q, want %q", removed, want)
+	}
+}
@@ -0,0 +1,121 @@
+package mung
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrUnmatchedRule is reported under [WithStrict] for each remove or replace
+// rule that matched no items.
+var ErrUnmatchedRule = errors.New("rule matched no items")
+
+// WithStrict returns an option that reports an error wrapping
+// [ErrUnmatchedRule] for each remove or replace rule that matched no items,
+// e.g., due to a typo in a list of items to remove.
+//
+// Errors are reported by [Config.FilteredErr], paired with the unmatched rule,
+// and by [Config.StringErr], once the munged sequence is fully realized.
+// Remove rules match items only if they satisfy the predicate function and
+// any built-in filters.
+func WithStrict() Option[Config] {
+	return func(config Config) Config {
+		config.strict = true
+
+		return config
+	}
+}
+
+// ruleTracker records the items matched by remove and replace rules during
+// a single realization of the munged sequence.
+type ruleTracker struct {
+	removed  set[string]
+	replaced set[string]
+}
+
+// newRuleTracker returns a ruleTracker comparing items like the receiver.
+func (c Config) newRuleTracker() *ruleTracker {
+	return &ruleTracker{removed: c.newSet(), replaced: c.newSet()}
+}
+
+// removing returns a function like omit that also records each item in
+// remove as matched.
+func (t *ruleTracker) removing(
+	remove set[string], omit func(string) bool,
+) func(string) bool {
+	return func(s string) bool {
+		if remove.Contains(s) {
+			t.removed.Add(viewOf(remove, s))
+		}
+
+		return omit(s)
+	}
+}
+
+// replacing returns a function like replace that also records each item
+// replaced as matched.
+func (t *ruleTracker) replacing(
+	replace func(string) (string, bool),
+) func(string) (string, bool) {
+	return func(s string) (string, bool) {
+		r, ok := replace(s)
+		if ok {
+			t.replaced.Add(s)
+		}
+
+		return r, ok
+	}
+}
+
+// Report describes the rules that matched no items during a realization of
+// the munged sequence.
+type Report struct {
+	// Remove holds each item removed with [WithRemove] or its variants that
+	// matched no items.
+	Remove []string
+	// Replace holds each key of the replacement map set with [WithReplace] or
+	// its variants that matched no items, in sorted order.
+	Replace []string
+}
+
+// errs returns an error wrapping [ErrUnmatchedRule] for each rule in the
+// receiver, paired with the rule.
+func (r Report) errs() []itemErr {
+	errs := make([]itemErr, 0, len(r.Remove)+len(r.Replace))
+
+	for _, s := range r.Remove {
+		errs = append(errs, itemErr{
+			item: s, err: fmt.Errorf("remove: %w", ErrUnmatchedRule),
+		})
+	}
+
+	for _, s := range r.Replace {
+		errs = append(errs, itemErr{
+			item: s, err: fmt.Errorf("replace: %w", ErrUnmatchedRule),
+		})
+	}
+
+	return errs
+}
+
+// unmatched returns a Report of the receiver configuration's remove and
+// replace rules that have not matched any item.
+func (c Config) unmatched(t *ruleTracker) Report {
+	var r Report
+
+	seen := c.newSet()
+	for s := range c.split(c.remove) {
+		if !seen.Seen(s) && !t.removed.Contains(s) {
+			r.Remove = append(r.Remove, s)
+		}
+	}
+
+	for _, from := range slices.Sorted(maps.Keys(c.replace)) {
+		if !t.replaced.Contains(c.normalize(c.expandEnv(from))) {
+			r.Replace = append(r.Replace, from)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,210 @@
+package mung
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestWithStrict(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option[Config]
+		want      string
+		unmatched []string
+	}{
+		{
+			name: "not_strict",
+			opts: []Option[Config]{
+				WithRemove([]string{"x"}),
+				WithReplace(map[string]string{"y": "Y"}),
+			},
+			want: "a:b:c",
+		},
+		{
+			name: "all_matched",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithRemove([]string{"a"}),
+				WithReplace(map[string]string{"b": "B"}),
+			},
+			want: "B:c",
+		},
+		{
+			name: "unmatched_remove",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithRemove([]string{"a:x:b:x"}),
+			},
+			want:      "c",
+			unmatched: []string{"x"},
+		},
+		{
+			name: "unmatched_replace",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithReplace(map[string]string{"y": "Y", "c": "C", "x": "X"}),
+			},
+			want:      "a:b:C",
+			unmatched: []string{"x", "y"},
+		},
+		{
+			name: "remove_prefix",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithPrefix([]string{"p"}),
+				WithRemove([]string{"p"}),
+			},
+			want: "a:b:c",
+		},
+		{
+			name: "remove_out_of_scope",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithPrefix([]string{"p"}),
+				WithRemove([]string{"p"}),
+				WithRemoveScope(ScopeSubject),
+			},
+			want:      "p:a:b:c",
+			unmatched: []string{"p"},
+		},
+		{
+			name: "remove_filtered",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithRemove([]string{"a"}),
+				WithExclude(func(s string) bool { return s == "a" }),
+			},
+			want:      "b:c",
+			unmatched: []string{"a"},
+		},
+		{
+			name: "case_fold",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithCaseFold(),
+				WithRemove([]string{"A"}),
+				WithReplace(map[string]string{"B": "x"}),
+			},
+			want: "x:c",
+		},
+		{
+			name: "equal",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithEqual(func(a, b string) bool { return a == b }),
+				WithRemove([]string{"a:z"}),
+			},
+			want:      "b:c",
+			unmatched: []string{"z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithDelim(":"),
+			}, tt.opts...)
+			config := Make(opts...)
+
+			var unmatched []string
+
+			for item, err := range config.FilteredErr() {
+				if err == nil {
+					continue
+				}
+
+				if !errors.Is(err, ErrUnmatchedRule) {
+					t.Fatalf("FilteredErr() error = %v, want %v", err, ErrUnmatchedRule)
+				}
+
+				unmatched = append(unmatched, item)
+			}
+
+			if !slicesEqual(unmatched, tt.unmatched) {
+				t.Errorf("FilteredErr() unmatched = %v, want %v",
+					unmatched, tt.unmatched)
+			}
+
+			got, err := config.StringErr()
+			if got != tt.want {
+				t.Errorf("StringErr() = %q, want %q", got, tt.want)
+			}
+
+			if (err != nil) != (len(tt.unmatched) > 0) ||
+				(err != nil && !errors.Is(err, ErrUnmatchedRule)) {
+				t.Errorf("StringErr() error = %v, want unmatched %v",
+					err, tt.unmatched)
+			}
+
+			if s := config.String(); s != tt.want {
+				t.Errorf("String() = %q, want %q", s, tt.want)
+			}
+
+			all := slices.Collect(config.Filtered())
+			if s := config.Tokenizer().Join(slices.Values(all)); s != tt.want {
+				t.Errorf("Filtered() = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringReport(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+		rep  Report
+	}{
+		{
+			name: "no_rules",
+			want: "a:b:c",
+		},
+		{
+			name: "all_matched",
+			opts: []Option[Config]{
+				WithRemove([]string{"a"}),
+				WithReplace(map[string]string{"b": "B"}),
+			},
+			want: "B:c",
+		},
+		{
+			name: "unmatched",
+			opts: []Option[Config]{
+				WithRemove([]string{"x:a:y"}),
+				WithReplace(map[string]string{"z": "Z", "b": "B"}),
+			},
+			want: "B:c",
+			rep:  Report{Remove: []string{"x", "y"}, Replace: []string{"z"}},
+		},
+		{
+			name: "strict",
+			opts: []Option[Config]{
+				WithStrict(),
+				WithRemove([]string{"x"}),
+			},
+			want: "a:b:c",
+			rep:  Report{Remove: []string{"x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithDelim(":"),
+			}, tt.opts...)
+
+			got, rep := Make(opts...).StringReport()
+			if got != tt.want {
+				t.Errorf("StringReport() = %q, want %q", got, tt.want)
+			}
+
+			if !slicesEqual(rep.Remove, tt.rep.Remove) ||
+				!slicesEqual(rep.Replace, tt.rep.Replace) {
+				t.Errorf("StringReport() report = %+v, want %+v", rep, tt.rep)
+			}
+		})
+	}
+}
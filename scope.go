@@ -0,0 +1,58 @@
+package mung
+
+// Scope identifies the sources of items that an option applies to.
+//
+// Scopes may be combined with bitwise OR, e.g., ScopePrefix | ScopeSuffix.
+type Scope int
+
+// Constant values of Scope.
+const (
+	// ScopePrefix identifies items added with [WithPrefix] or its variants.
+	ScopePrefix Scope = 1 << iota
+	// ScopeSubject identifies items added with [WithSubject] or its variants.
+	ScopeSubject
+	// ScopeSuffix identifies items added with [WithSuffix] or its variants.
+	ScopeSuffix
+
+	// ScopeAll identifies items from all sources.
+	ScopeAll = ScopePrefix | ScopeSubject | ScopeSuffix
+)
+
+// has returns true if and only if the receiver includes every source in s.
+// The zero value of the receiver is treated as [ScopeAll].
+func (scope Scope) has(s Scope) bool {
+	if scope == 0 {
+		scope = ScopeAll
+	}
+
+	return scope&s == s
+}
+
+// WithFilterScope returns an option that limits the predicate functions set
+// with [WithFilter], its variants, and [WithExclude] to items from the given
+// sources, e.g., to avoid evaluating prefix items that are already known to
+// be valid. Items from other sources are selected unless rejected by any
+// built-in filters.
+//
+// By default, or if scope is 0, predicate functions apply to [ScopeAll].
+func WithFilterScope(scope Scope) Option[Config] {
+	return func(config Config) Config {
+		config.filterScope = scope
+
+		return config
+	}
+}
+
+// WithRemoveScope returns an option that limits the strings set with
+// [WithRemove] or its variants to removing items from the given sources,
+// e.g., to remove stale subject items without also removing the same items
+// added with [WithPrefix] or [WithSuffix].
+//
+// By default, or if scope is 0, items are removed from [ScopeAll].
+func WithRemoveScope(scope Scope) Option[Config] {
+	return func(config Config) Config {
+		config.removeScope = scope
+
+		return config
+	}
+}
@@ -0,0 +1,182 @@
+package mung
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScopeHas(t *testing.T) {
+	tests := []struct {
+		scope Scope
+		s     Scope
+		want  bool
+	}{
+		{0, ScopePrefix, true},
+		{0, ScopeAll, true},
+		{ScopeAll, ScopeSuffix, true},
+		{ScopeSubject, ScopeSubject, true},
+		{ScopeSubject, ScopePrefix, false},
+		{ScopePrefix | ScopeSuffix, ScopeSuffix, true},
+		{ScopePrefix | ScopeSuffix, ScopeSubject, false},
+		{ScopePrefix, ScopePrefix | ScopeSuffix, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.scope.has(tt.s); got != tt.want {
+			t.Errorf("Scope(%d).has(%d) = %v, want %v", tt.scope, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestWithFilterScope(t *testing.T) {
+	noX := func(s string) bool { return !strings.HasPrefix(s, "x") }
+
+	tests := []struct {
+		name  string
+		scope Scope
+		opts  []Option[Config]
+		want  []string
+	}{
+		{
+			name: "default",
+			want: []string{"p", "a", "s"},
+		},
+		{
+			name:  "all",
+			scope: ScopeAll,
+			want:  []string{"p", "a", "s"},
+		},
+		{
+			name:  "subject",
+			scope: ScopeSubject,
+			want:  []string{"xp", "p", "a", "xs", "s"},
+		},
+		{
+			name:  "prefix",
+			scope: ScopePrefix,
+			want:  []string{"p", "xa", "a", "xs", "s"},
+		},
+		{
+			name:  "prefix_suffix",
+			scope: ScopePrefix | ScopeSuffix,
+			want:  []string{"p", "xa", "a", "s"},
+		},
+		{
+			name:  "exclude",
+			scope: ScopeSuffix,
+			opts:  []Option[Config]{WithFilter(nil), WithExclude(FilterNot(noX))},
+			want:  []string{"xp", "p", "xa", "a", "s"},
+		},
+		{
+			name:  "batch",
+			scope: ScopeSubject,
+			opts: []Option[Config]{WithBatchFilter(func(items []string) []bool {
+				results := make([]bool, len(items))
+				for i, s := range items {
+					results[i] = noX(s)
+				}
+
+				return results
+			})},
+			want: []string{"xp", "p", "a", "xs", "s"},
+		},
+		{
+			name:  "parallel",
+			scope: ScopeSubject,
+			opts:  []Option[Config]{WithParallelFilter(4)},
+			want:  []string{"xp", "p", "a", "xs", "s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubject([]string{"xa:a"}),
+				WithPrefix([]string{"xp:p"}),
+				WithSuffix([]string{"xs:s"}),
+				WithDelim(":"),
+				WithFilter(noX),
+				WithFilterScope(tt.scope),
+			}, tt.opts...)
+
+			got := slices.Collect(Make(opts...).Filtered())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("Filtered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRemoveScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scope  Scope
+		dedupe Option[Config]
+		want   []string
+	}{
+		{
+			name: "default",
+			want: []string{"p", "a", "s"},
+		},
+		{
+			name:  "all",
+			scope: ScopeAll,
+			want:  []string{"p", "a", "s"},
+		},
+		{
+			name:  "subject",
+			scope: ScopeSubject,
+			want:  []string{"x", "p", "a", "s"},
+		},
+		{
+			name:  "prefix",
+			scope: ScopePrefix,
+			want:  []string{"p", "a", "s", "x"},
+		},
+		{
+			name:  "suffix",
+			scope: ScopeSuffix,
+			want:  []string{"x", "p", "a", "s"},
+		},
+		{
+			name:   "subject_allow_duplicates",
+			scope:  ScopeSubject,
+			dedupe: WithAllowDuplicates(),
+			want:   []string{"x", "p", "a", "s", "x"},
+		},
+		{
+			name:   "suffix_allow_duplicates",
+			scope:  ScopeSuffix,
+			dedupe: WithAllowDuplicates(),
+			want:   []string{"x", "p", "x", "a", "s"},
+		},
+		{
+			name:   "prefix_suffix_keep_last",
+			scope:  ScopePrefix | ScopeSuffix,
+			dedupe: WithDedupeKeepLast(),
+			want:   []string{"p", "x", "a", "s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option[Config]{
+				WithSubject([]string{"x:a"}),
+				WithPrefix([]string{"x:p"}),
+				WithSuffix([]string{"s:x"}),
+				WithDelim(":"),
+				WithRemove([]string{"x"}),
+				WithRemoveScope(tt.scope),
+			}
+			if tt.dedupe != nil {
+				opts = append(opts, tt.dedupe)
+			}
+
+			got := slices.Collect(Make(opts...).All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package mung
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// setenv and lookupEnv access the environment of the current process.
+// They are variables so that tests can observe writes.
+var (
+	setenv    = os.Setenv
+	lookupEnv = os.LookupEnv
+)
+
+// WithSkipUnchanged returns an option that makes [Config.Setenv] and
+// [Environ.Apply] skip setting a variable whose current value is already
+// equal to the munged string, e.g., to avoid needless writes to an
+// environment shared with other goroutines.
+func WithSkipUnchanged() Option[Config] {
+	return func(config Config) Config {
+		config.skipSame = true
+
+		return config
+	}
+}
+
+// Setenv sets the named environment variable of the current process to the
+// munged string, as if by [os.Setenv].
+//
+// If munging fails, as reported by [Config.StringErr], the variable is not
+// set and the error is returned. Under [WithSkipUnchanged], the variable is
+// not set if it is already defined with a value equal to the munged string.
+func (c Config) Setenv(name string) error {
+	value, err := c.StringErr()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if c.skipSame {
+		if old, ok := lookupEnv(name); ok && old == value {
+			return nil
+		}
+	}
+
+	return setenv(name, value)
+}
+
+// Apply sets each variable of the receiver in the environment of the current
+// process, in order, e.g., to munge the environment inherited by every child
+// process started afterward.
+//
+// Variables defined by a configuration are set as if by [Config.Setenv], so
+// a configuration that fails to munge leaves its variable unchanged.
+// Variables of the current process that are not defined by the receiver are
+// left unchanged. Apply sets every other variable before returning the
+// errors encountered joined with [errors.Join].
+func (e Environ) Apply() error {
+	var errs []error
+
+	v := e.get()
+
+	for _, name := range v.names {
+		var err error
+
+		if config, ok := v.config[name]; ok {
+			err = config.Setenv(name)
+		} else {
+			err = setenv(name, v.values[name])
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
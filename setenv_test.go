@@ -0,0 +1,117 @@
+package mung
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestConfigSetenv(t *testing.T) {
+	errFilter := errors.New("filter failed")
+
+	tests := []struct {
+		name    string
+		initial string
+		opts    []Option[Config]
+		want    string
+		writes  int
+		wantErr error
+	}{
+		{
+			name:    "changed",
+			initial: "/usr/bin:/bin:/usr/bin",
+			want:    "/usr/bin:/bin",
+			writes:  1,
+		},
+		{
+			name:    "unchanged",
+			initial: "/usr/bin:/bin",
+			want:    "/usr/bin:/bin",
+			writes:  1,
+		},
+		{
+			name:    "skip_unchanged",
+			initial: "/usr/bin:/bin",
+			opts:    []Option[Config]{WithSkipUnchanged()},
+			want:    "/usr/bin:/bin",
+		},
+		{
+			name:    "skip_changed",
+			initial: "/usr/bin:/bin",
+			opts: []Option[Config]{
+				WithSkipUnchanged(), WithPrefixItems("/opt/bin"),
+			},
+			want:   "/opt/bin:/usr/bin:/bin",
+			writes: 1,
+		},
+		{
+			name:    "error",
+			initial: "/usr/bin:/bin",
+			opts: []Option[Config]{
+				WithFilterErr(func(string) (bool, error) { return false, errFilter }),
+			},
+			want:    "/usr/bin:/bin",
+			wantErr: errFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const name = "MUNG_TEST_SETENV"
+
+			t.Setenv(name, tt.initial)
+
+			writes := 0
+			setenv = func(key, value string) error {
+				writes++
+
+				return os.Setenv(key, value)
+			}
+			t.Cleanup(func() { setenv = os.Setenv })
+
+			config := Make(append([]Option[Config]{
+				WithSubject([]string{os.Getenv(name)}), WithDelim(":"),
+			}, tt.opts...)...)
+
+			if err := config.Setenv(name); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Setenv() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := os.Getenv(name); got != tt.want {
+				t.Errorf("Getenv() = %q, want %q", got, tt.want)
+			}
+
+			if writes != tt.writes {
+				t.Errorf("Setenv() wrote %d times, want %d", writes, tt.writes)
+			}
+		})
+	}
+}
+
+func TestEnvironApply(t *testing.T) {
+	t.Setenv("MUNG_TEST_PATH", "/usr/bin:/bin:/usr/bin")
+	t.Setenv("MUNG_TEST_VALUE", "a::a")
+	t.Setenv("MUNG_TEST_OTHER", "x")
+
+	errFilter := errors.New("filter failed")
+
+	env := ParseEnviron(os.Environ())
+	env.Munge("MUNG_TEST_PATH", WithDelim(":"), WithPrefixItems("/opt/bin"))
+	env.SetValue("MUNG_TEST_VALUE", "b::b")
+	env.Munge("MUNG_TEST_OTHER",
+		WithFilterErr(func(string) (bool, error) { return false, errFilter }))
+
+	if err := env.Apply(); !errors.Is(err, errFilter) {
+		t.Errorf("Apply() error = %v, want %v", err, errFilter)
+	}
+
+	for name, want := range map[string]string{
+		"MUNG_TEST_PATH":  "/opt/bin:/usr/bin:/bin",
+		"MUNG_TEST_VALUE": "b::b",
+		"MUNG_TEST_OTHER": "x",
+	} {
+		if got := os.Getenv(name); got != want {
+			t.Errorf("Getenv(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
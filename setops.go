@@ -0,0 +1,68 @@
+package mung
+
+import "iter"
+
+// Union returns a sequence of the distinct items in a or b,
+// in the order they are first yielded by a followed by b.
+//
+// Items are compared exactly. The arguments are typically realized munged
+// sequences, e.g., from [Config.Filtered], which are already normalized.
+// A nil sequence yields no items.
+func Union(a, b iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		seen := Set[string]{}
+
+		for _, seq := range []iter.Seq[string]{a, b} {
+			if seq == nil {
+				continue
+			}
+
+			for s := range seq {
+				if !seen.Seen(s) && !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Intersect returns a sequence of the distinct items in both a and b,
+// in the order they are first yielded by a.
+//
+// Items are compared exactly, as in [Union].
+func Intersect(a, b iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if a == nil {
+			return
+		}
+
+		in := SetOf(b)
+		seen := Set[string]{}
+
+		for s := range a {
+			if in.Contains(s) && !seen.Seen(s) && !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Subtract returns a sequence of the distinct items in a but not in b,
+// in the order they are first yielded by a.
+//
+// Items are compared exactly, as in [Union].
+func Subtract(a, b iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if a == nil {
+			return
+		}
+
+		seen := SetOf(b)
+
+		for s := range a {
+			if !seen.Seen(s) && !yield(s) {
+				return
+			}
+		}
+	}
+}
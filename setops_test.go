@@ -0,0 +1,92 @@
+package mung
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetOperations(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      []string
+		union     []string
+		intersect []string
+		subtract  []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "a_only",
+			a:        []string{"a", "b"},
+			union:    []string{"a", "b"},
+			subtract: []string{"a", "b"},
+		},
+		{
+			name:  "b_only",
+			b:     []string{"a", "b"},
+			union: []string{"a", "b"},
+		},
+		{
+			name:      "overlap",
+			a:         []string{"c", "a", "b"},
+			b:         []string{"b", "d", "c"},
+			union:     []string{"c", "a", "b", "d"},
+			intersect: []string{"c", "b"},
+			subtract:  []string{"a"},
+		},
+		{
+			name:      "duplicates",
+			a:         []string{"a", "b", "a", "c", "b"},
+			b:         []string{"c", "c", "d", "d"},
+			union:     []string{"a", "b", "c", "d"},
+			intersect: []string{"c"},
+			subtract:  []string{"a", "b"},
+		},
+	}
+
+	seq := func(s []string) func(func(string) bool) {
+		if s == nil {
+			return nil
+		}
+
+		return slices.Values(s)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slices.Collect(Union(seq(tt.a), seq(tt.b))); !slicesEqual(got, tt.union) {
+				t.Errorf("Union() = %v, want %v", got, tt.union)
+			}
+
+			if got := slices.Collect(Intersect(seq(tt.a), seq(tt.b))); !slicesEqual(got, tt.intersect) {
+				t.Errorf("Intersect() = %v, want %v", got, tt.intersect)
+			}
+
+			if got := slices.Collect(Subtract(seq(tt.a), seq(tt.b))); !slicesEqual(got, tt.subtract) {
+				t.Errorf("Subtract() = %v, want %v", got, tt.subtract)
+			}
+		})
+	}
+}
+
+func TestSetOperationsEarlyReturn(t *testing.T) {
+	a, b := slices.Values([]string{"a", "b", "c"}), slices.Values([]string{"a", "b"})
+
+	for name, seq := range map[string]func(func(string) bool){
+		"Union":     Union(a, b),
+		"Intersect": Intersect(a, b),
+		"Subtract":  Subtract(b, slices.Values([]string{})),
+	} {
+		var got []string
+		for s := range seq {
+			got = append(got, s)
+
+			break
+		}
+
+		if want := []string{"a"}; !slicesEqual(got, want) {
+			t.Errorf("%s() early return = %v, want %v", name, got, want)
+		}
+	}
+}
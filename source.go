@@ -0,0 +1,313 @@
+package mung
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"iter"
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+// source returns strings produced lazily for a configuration being realized.
+type source func(c Config) iter.Seq[string]
+
+// sources holds the strings of a configuration that are produced lazily.
+// If a source is set, it produces all of the corresponding strings, and the
+// corresponding slice of the configuration is nil.
+type sources struct {
+	subject source
+	remove  source
+	prefix  source
+	suffix  source
+}
+
+// claims holds the claim on the backing array of each of the subject, remove,
+// prefix, and suffix strings of a configuration.
+type claims struct {
+	subject claim
+	remove  claim
+	prefix  claim
+	suffix  claim
+}
+
+// claim records how much of a backing array of strings is in use, so that
+// copies of a configuration sharing the array can append to it in place
+// only if no other copy has appended to it, without clipping the array and
+// so losing amortized growth each time.
+//
+// A claim is only valid for the array whose first element is at first, so
+// a slice with any other backing array is treated as shared.
+type claim struct {
+	first *string
+	used  *atomic.Int64
+}
+
+// append returns strs with items appended, writing into the backing array of
+// strs only if the receiver holds a claim on the array that no other copy has
+// extended beyond strs. Otherwise, items are appended to a new array claimed
+// by the receiver.
+func (c *claim) append(strs, items []string) []string {
+	if len(items) == 0 {
+		return strs
+	}
+
+	n := int64(len(strs))
+
+	if cap(strs)-len(strs) >= len(items) && c.used != nil &&
+		c.first == &strs[:cap(strs)][0] &&
+		c.used.CompareAndSwap(n, n+int64(len(items))) {
+		return append(strs, items...)
+	}
+
+	strs = append(slices.Clip(strs), items...)
+
+	c.first, c.used = &strs[:cap(strs)][0], new(atomic.Int64)
+	c.used.Store(int64(len(strs)))
+
+	return strs
+}
+
+// WithSubjectReader returns an option that sets the subject strings to be
+// read from r, e.g., to munge a large list piped from another program without
+// holding all of it in memory.
+//
+// Strings are read lazily as the munged sequence is realized, splitting the
+// input on newlines and, if the [Tokenizer] is a [DelimTokenizer] without a
+// delimiter pattern, escapes, or quotes, on its delimiters. Each string read
+// is then split into items like any other subject string.
+// Items are only processed one at a time if the configuration does not need
+// the subject more than once, e.g., [WithDedupeKeepLast], [WithBatchFilter],
+// and [PrependSkip] read all of r first.
+//
+// Since r can only be read once, the subject strings are only available to
+// the first realization of the munged sequence or call to [Config.Subject].
+// Reading stops at the first error other than [io.EOF], which is reported by
+// [Config.FilteredErr] and [Config.StringErr], paired with an empty item.
+func WithSubjectReader(r io.Reader) Option[Config] {
+	return func(config Config) Config {
+		config.subject = nil
+		config.sources.subject = func(c Config) iter.Seq[string] {
+			return c.readStrings(r)
+		}
+
+		return config
+	}
+}
+
+// WithSubjectSeq returns an option that sets the subject strings to be the
+// strings yielded by seq, e.g., to munge strings produced by another iterator
+// without first collecting them into a slice.
+//
+// The sequence is iterated each time the munged sequence is realized, and,
+// like [WithSubjectReader], its strings are processed one at a time unless
+// the configuration needs the subject more than once.
+// A nil seq yields no strings.
+func WithSubjectSeq(seq iter.Seq[string]) Option[Config] {
+	return func(config Config) Config {
+		config.subject, config.sources.subject = nil, seqSource(seq)
+
+		return config
+	}
+}
+
+// WithRemoveSeq is like [WithRemove] but sets the strings to remove to be the
+// strings yielded by seq. The sequence is collected each time the munged
+// sequence is realized. A nil seq yields no strings.
+func WithRemoveSeq(seq iter.Seq[string]) Option[Config] {
+	return func(config Config) Config {
+		config.remove, config.sources.remove = nil, seqSource(seq)
+
+		return config
+	}
+}
+
+// WithPrefixSeq is like [WithPrefix] but sets the prefix strings to be the
+// strings yielded by seq. The sequence is collected each time the munged
+// sequence is realized. A nil seq yields no strings.
+func WithPrefixSeq(seq iter.Seq[string]) Option[Config] {
+	return func(config Config) Config {
+		config.prefix, config.sources.prefix = nil, seqSource(seq)
+
+		return config
+	}
+}
+
+// WithSuffixSeq is like [WithSuffix] but sets the suffix strings to be the
+// strings yielded by seq. The sequence is collected each time the munged
+// sequence is realized. A nil seq yields no strings.
+func WithSuffixSeq(seq iter.Seq[string]) Option[Config] {
+	return func(config Config) Config {
+		config.suffix, config.sources.suffix = nil, seqSource(seq)
+
+		return config
+	}
+}
+
+// seqSource returns a source that produces the strings of seq.
+func seqSource(seq iter.Seq[string]) source {
+	return func(Config) iter.Seq[string] {
+		if seq == nil {
+			return func(func(string) bool) {}
+		}
+
+		return seq
+	}
+}
+
+// values returns a sequence of the strings produced by src for the receiver,
+// or of strs if src is nil.
+func (c Config) values(strs []string, src source) iter.Seq[string] {
+	if src != nil {
+		return src(c)
+	}
+
+	return slices.Values(strs)
+}
+
+// subjects returns a sequence of the receiver's subject strings.
+func (c Config) subjects() iter.Seq[string] {
+	return c.values(c.subject, c.sources.subject)
+}
+
+// materialize returns a copy of the receiver with the strings of each source
+// collected into the corresponding slice.
+func (c Config) materialize() Config {
+	c = c.materializeRules()
+	c.subject, c.sources.subject = c.collect(
+		c.subject, c.limitSource(c.sources.subject),
+	)
+
+	return c
+}
+
+// materializeRules is like [Config.materialize] but only collects the
+// strings to remove, prefix, and suffix, which are needed in full before any
+// item is processed.
+func (c Config) materializeRules() Config {
+	c.remove, c.sources.remove = c.collect(c.remove, c.sources.remove)
+	c.prefix, c.sources.prefix = c.collect(c.prefix, c.sources.prefix)
+	c.suffix, c.sources.suffix = c.collect(c.suffix, c.sources.suffix)
+
+	return c
+}
+
+// collect returns the strings produced by src for the receiver, or strs if
+// src is nil, and a nil source.
+func (c Config) collect(strs []string, src source) ([]string, source) {
+	if src == nil {
+		return strs, nil
+	}
+
+	return slices.Collect(src(c)), nil
+}
+
+// subjectStrings returns the receiver's subject strings and their source.
+func (c Config) subjectStrings() ([]string, source) {
+	return c.subject, c.sources.subject
+}
+
+// removeStrings returns the receiver's strings to remove and their source.
+func (c Config) removeStrings() ([]string, source) {
+	return c.remove, c.sources.remove
+}
+
+// prefixStrings returns the receiver's prefix strings and their source.
+func (c Config) prefixStrings() ([]string, source) {
+	return c.prefix, c.sources.prefix
+}
+
+// suffixStrings returns the receiver's suffix strings and their source.
+func (c Config) suffixStrings() ([]string, source) {
+	return c.suffix, c.sources.suffix
+}
+
+// streams reports whether the receiver's subject strings can be processed
+// one at a time, without first collecting all of them.
+//
+// Batch and parallel predicates are evaluated for every candidate item at
+// once, so the strings produced lazily must be collected first.
+func (c Config) streams() bool {
+	return c.prepend != PrependSkip && c.dedupe != dedupeKeepLast &&
+		c.batch == nil && c.workers < 2
+}
+
+// appendSource returns a source that produces the strings of src followed by
+// strs.
+func appendSource(src source, strs []string) source {
+	return func(c Config) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for s := range src(c) {
+				if !yield(s) {
+					return
+				}
+			}
+
+			for _, s := range strs {
+				if !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readStrings returns a sequence of the strings read from r, split on
+// newlines and, if possible, on the receiver's delimiters.
+func (c Config) readStrings(r io.Reader) iter.Seq[string] {
+	var delims [][]byte
+
+	if d, ok := c.Tokenizer().(DelimTokenizer); ok &&
+		d.Pattern == nil && d.syntax() == 0 {
+		for _, delim := range d.delims().delims {
+			if delim != "" {
+				delims = append(delims, []byte(delim))
+			}
+		}
+	}
+
+	return func(yield func(string) bool) {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(nil, math.MaxInt)
+		sc.Split(splitStrings(delims))
+
+		for sc.Scan() {
+			if !yield(sc.Text()) {
+				return
+			}
+		}
+
+		if err := sc.Err(); err != nil && c.onErr != nil {
+			c.onErr("", err)
+		}
+	}
+}
+
+// splitStrings returns a [bufio.SplitFunc] that splits its input on newlines,
+// optionally preceded by a carriage return, or any of the given delimiters.
+func splitStrings(delims [][]byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		end, next := bytes.IndexByte(data, '\n'), 1
+
+		for _, delim := range delims {
+			if i := bytes.Index(data, delim); i >= 0 && (end < 0 || i < end) {
+				end, next = i, len(delim)
+			}
+		}
+
+		switch {
+		case end >= 0:
+			token := data[:end]
+			if data[end] == '\n' {
+				token = bytes.TrimSuffix(token, []byte("\r"))
+			}
+
+			return end + next, token, nil
+		case atEOF && len(data) > 0:
+			return len(data), data, nil
+		default:
+			return 0, nil, nil // request more data
+		}
+	}
+}
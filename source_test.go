@@ -0,0 +1,303 @@
+package mung
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWithSubjectReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option[Config]
+		want  string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "delims_and_newlines",
+			input: "a:b\nc\r\nb:d\n",
+			want:  "a:b:c:d",
+		},
+		{
+			name:  "multibyte_delim",
+			input: "a::b::c\nd",
+			opts:  []Option[Config]{WithDelim("::")},
+			want:  "a::b::c::d",
+		},
+		{
+			name:  "alt_delims",
+			input: "a;b:c",
+			opts:  []Option[Config]{WithDelims(":", ";")},
+			want:  "a:b:c",
+		},
+		{
+			name:  "pattern",
+			input: "a1b\nc22d",
+			opts: []Option[Config]{
+				WithDelimPattern(regexp.MustCompile(`[0-9]+`)),
+				WithOutputDelim(":"),
+			},
+			want: "a:b:c:d",
+		},
+		{
+			name:  "rules",
+			input: "a:b:c:d",
+			opts: []Option[Config]{
+				WithPrefixItems("c"),
+				WithSuffixItems("a"),
+				WithRemoveItems("b"),
+			},
+			want: "c:d:a",
+		},
+		{
+			name:  "keep_last",
+			input: "a:b:a",
+			opts:  []Option[Config]{WithDedupeKeepLast()},
+			want:  "b:a",
+		},
+		{
+			name:  "prepend_skip",
+			input: "a:b",
+			opts: []Option[Config]{
+				WithPrefixItems("b", "p"),
+				WithPrependPolicy(PrependSkip),
+			},
+			want: "p:a:b",
+		},
+		{
+			name:  "batch",
+			input: "a:b:c",
+			opts: []Option[Config]{
+				WithBatchFilter(func(items []string) []bool {
+					keep := make([]bool, len(items))
+					for i, s := range items {
+						keep[i] = s != "b"
+					}
+
+					return keep
+				}),
+			},
+			want: "a:c",
+		},
+		{
+			name:  "subject_items",
+			input: "a:b",
+			opts:  []Option[Config]{WithSubjectItems("c:a", "d")},
+			want:  "a:b:c:d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubjectReader(iotest.OneByteReader(strings.NewReader(tt.input))),
+				WithDelim(":"),
+			}, tt.opts...)
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// endlessReader is an [io.Reader] that repeats "a:" endlessly.
+type endlessReader struct{ n int }
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = "a:"[(r.n+i)%2]
+	}
+
+	r.n += len(p)
+
+	return len(p), nil
+}
+
+func TestWithSubjectReaderLazy(t *testing.T) {
+	r := &endlessReader{}
+	config := Make(WithSubjectReader(r), WithDelim(":"))
+
+	if got, ok := config.First(); got != "a" || !ok {
+		t.Errorf("First() = %q, %v, want %q, true", got, ok, "a")
+	}
+
+	if r.n > 1<<16 {
+		t.Errorf("First() read %d bytes, want at most %d", r.n, 1<<16)
+	}
+}
+
+func TestWithSubjectReaderOnce(t *testing.T) {
+	config := Make(
+		WithSubjectReader(strings.NewReader("a:b")),
+		WithDelim(":"),
+		WithPrefixItems("p"),
+	)
+
+	if got, want := config.Subject(), []string{"a", "b"}; !slicesEqual(got, want) {
+		t.Errorf("Subject() = %q, want %q", got, want)
+	}
+
+	if got, want := config.String(), "p"; got != want {
+		t.Errorf("String() after Subject() = %q, want %q", got, want)
+	}
+
+	config = WithSubject([]string{"c"})(config)
+	if got, want := config.String(), "p:c"; got != want {
+		t.Errorf("String() after WithSubject() = %q, want %q", got, want)
+	}
+}
+
+func TestWithSubjectReaderMerge(t *testing.T) {
+	base := Make(WithSubjectItems("a"), WithDelim(":"))
+	layer := Make(WithSubjectReader(strings.NewReader("b:a:c")))
+
+	merged := base.Merge(layer)
+	if got, want := merged.String(), "a:b:c"; got != want {
+		t.Errorf("Merge() String() = %q, want %q", got, want)
+	}
+
+	if !base.Equal(Make(WithSubjectItems("a"), WithDelim(":"))) {
+		t.Error("Merge() modified receiver")
+	}
+
+	if merged.Equal(Make(WithDelim(":"))) {
+		t.Error("Equal() = true for configurations with and without a reader")
+	}
+}
+
+func TestWithSubjectReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("a:b\n"), iotest.ErrReader(errRead))
+
+	got, err := Make(WithSubjectReader(r), WithDelim(":")).StringErr()
+	if got != "a:b" || !errors.Is(err, errRead) {
+		t.Errorf("StringErr() = %q, %v, want %q, %v", got, err, "a:b", errRead)
+	}
+}
+
+func TestWithSeq(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+	}{
+		{
+			name: "nil",
+			opts: []Option[Config]{
+				WithSubjectSeq(nil), WithRemoveSeq(nil),
+				WithPrefixSeq(nil), WithSuffixSeq(nil),
+			},
+		},
+		{
+			name: "subject",
+			opts: []Option[Config]{
+				WithSubjectSeq(slices.Values([]string{"a:b", "c"})),
+				WithSubjectItems("b:d"),
+			},
+			want: "a:b:c:d",
+		},
+		{
+			name: "remove",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:c:d"}),
+				WithRemoveSeq(slices.Values([]string{"b"})),
+				WithRemoveItems("d"),
+			},
+			want: "a:c",
+		},
+		{
+			name: "prefix_suffix",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:c"}),
+				WithPrefixSeq(slices.Values([]string{"c", "p"})),
+				WithPrefixItems("q"),
+				WithSuffixSeq(slices.Values([]string{"a"})),
+				WithSuffixItems("s"),
+			},
+			want: "q:p:c:b:a:s",
+		},
+		{
+			name: "replaced_by_slice",
+			opts: []Option[Config]{
+				WithSubjectSeq(slices.Values([]string{"x"})),
+				WithSubject([]string{"a"}),
+				WithRemoveSeq(slices.Values([]string{"a"})),
+				WithRemove(nil),
+			},
+			want: "a",
+		},
+		{
+			name: "keep_last",
+			opts: []Option[Config]{
+				WithSubjectSeq(slices.Values([]string{"a:b", "a"})),
+				WithDedupeKeepLast(),
+			},
+			want: "b:a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Make(append([]Option[Config]{WithDelim(":")}, tt.opts...)...)
+
+			for range 2 {
+				if got := config.String(); got != tt.want {
+					t.Errorf("String() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithSeqAccessors(t *testing.T) {
+	var n int
+
+	removes := func(yield func(string) bool) {
+		n++
+		_ = yield("r") && yield("s")
+	}
+
+	config := Make(
+		WithRemoveSeq(removes),
+		WithPrefixSeq(slices.Values([]string{"p"})),
+		WithSuffixSeq(slices.Values([]string{"s"})),
+	)
+
+	if got, want := config.Remove(), []string{"r", "s"}; !slicesEqual(got, want) {
+		t.Errorf("Remove() = %q, want %q", got, want)
+	}
+
+	if n != 1 {
+		t.Errorf("Remove() iterated sequence %d times, want 1", n)
+	}
+
+	if got, want := config.Prefix(), []string{"p"}; !slicesEqual(got, want) {
+		t.Errorf("Prefix() = %q, want %q", got, want)
+	}
+
+	if got, want := config.Suffix(), []string{"s"}; !slicesEqual(got, want) {
+		t.Errorf("Suffix() = %q, want %q", got, want)
+	}
+
+	text, err := config.MarshalText()
+	if got, want := string(text), "^p $s -r -s"; got != want || err != nil {
+		t.Errorf("MarshalText() = %q, %v, want %q, nil", got, err, want)
+	}
+
+	merged := config.Merge(Make(WithRemoveItems("t")))
+	if got, want := merged.Remove(), []string{"r", "s", "t"}; !slicesEqual(got, want) {
+		t.Errorf("Merge() Remove() = %q, want %q", got, want)
+	}
+
+	if config.Equal(Make(WithRemove([]string{"r", "s"}))) {
+		t.Error("Equal() = true for configurations with and without a sequence")
+	}
+}
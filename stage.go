@@ -0,0 +1,131 @@
+package mung
+
+import (
+	"iter"
+	"slices"
+)
+
+// Stage is a transformation of a sequence of items.
+//
+// Stages receive the munged sequence in order and may yield any number of
+// items, e.g., to rewrite, insert, reorder, or discard items.
+type Stage func(items iter.Seq[string]) iter.Seq[string]
+
+// WithStages returns an option that appends stages to the pipeline that
+// produces the munged sequence.
+//
+// Stages are applied in order to the munged sequence after elimination of
+// duplicates, replacement, and ordering by [WithPriority] and [WithSort], and
+// before joining items into a string. Use [WithStageAfter] to insert stages
+// at other points of the pipeline.
+// Each Stage is applied once per realization of the munged sequence.
+func WithStages(stages ...Stage) Option[Config] {
+	return func(config Config) Config {
+		config.stages = append(slices.Clip(config.stages), stages...)
+
+		return config
+	}
+}
+
+// WithStageAfter returns an option that inserts stages into the pipeline that
+// produces the munged sequence immediately after the named built-in stage,
+// e.g., to sample, count, or rewrite items at a point not reachable with
+// [WithStages]:
+//
+//	var distinct int
+//	count := func(items iter.Seq[string]) iter.Seq[string] {
+//		return func(yield func(string) bool) {
+//			for s := range items {
+//				if distinct++; !yield(s) {
+//					return
+//				}
+//			}
+//		}
+//	}
+//	config := mung.Make(mung.WithStageAfter(mung.StageDedup, count))
+//
+// The built-in stages are applied to the munged sequence in order:
+// [StageFilter], [StageRemove], [StageDedup], [StageReplace], [StageSort], and
+// then [StagePin]. Items are filtered, removed, and deduplicated together as
+// they are split, so stages inserted after [StageFilter] or [StageRemove] are
+// applied after [StageDedup]. Stages inserted after [StageSort] are appended
+// as if by [WithStages]. Stages inserted at the same point are applied in the
+// order they were given, and unknown stage names are ignored.
+func WithStageAfter(name StageName, stages ...Stage) Option[Config] {
+	return func(config Config) Config {
+		at := name
+
+		switch name {
+		case StageSort:
+			return WithStages(stages...)(config)
+
+		case StageFilter, StageRemove:
+			at = StageDedup
+
+		case StageDedup, StageReplace, StagePin:
+
+		default:
+			return config
+		}
+
+		for _, stage := range stages {
+			config.after = append(slices.Clip(config.after),
+				stagePoint{name: at, stage: stage})
+		}
+
+		return config
+	}
+}
+
+// stagePoint is a [Stage] inserted after the named built-in stage.
+type stagePoint struct {
+	name  StageName
+	stage Stage
+}
+
+// stagesAfter returns the stages inserted after the named built-in stage.
+func (c Config) stagesAfter(name StageName) []Stage {
+	var stages []Stage
+
+	for _, p := range c.after {
+		if p.name == name {
+			stages = append(stages, p.stage)
+		}
+	}
+
+	return stages
+}
+
+// Stages returns a copy of the stages appended to the munged sequence.
+func (c Config) Stages() []Stage {
+	return append([]Stage(nil), c.stages...)
+}
+
+// pipe returns the sequence produced by applying each stage in order to items.
+func pipe(items iter.Seq[string], stages ...Stage) iter.Seq[string] {
+	for _, stage := range stages {
+		if stage != nil {
+			items = stage(items)
+		}
+	}
+
+	return items
+}
+
+// replaceStage returns a [Stage] that replaces each item matching a rule of
+// the given replacement function.
+func replaceStage(replace func(s string) (string, bool)) Stage {
+	return func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for s := range items {
+				if r, ok := replace(s); ok {
+					s = r
+				}
+
+				if !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package mung
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// upperStage is a [Stage] that upper-cases every item.
+func upperStage(items iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s := range items {
+			if !yield(strings.ToUpper(s)) {
+				return
+			}
+		}
+	}
+}
+
+// sortStage is a [Stage] that sorts all items.
+func sortStage(items iter.Seq[string]) iter.Seq[string] {
+	return slices.Values(slices.Sorted(items))
+}
+
+// dropStage returns a [Stage] that discards the given item.
+func dropStage(item string) Stage {
+	return func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for s := range items {
+				if s != item && !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestWithStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		stages  []Stage
+		want    []string
+	}{
+		{
+			name:    "no_stages",
+			initial: Config{subject: []string{"c:a:b"}},
+			want:    []string{"c", "a", "b"},
+		},
+		{
+			name:    "single_stage",
+			initial: Config{subject: []string{"c:a:b"}},
+			stages:  []Stage{sortStage},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "ordered_stages",
+			initial: Config{subject: []string{"c:a:b"}},
+			stages:  []Stage{dropStage("a"), upperStage, dropStage("b")},
+			want:    []string{"C", "B"},
+		},
+		{
+			name: "after_replace_and_dedupe",
+			initial: Config{
+				subject: []string{"x:y:x"},
+				replace: map[string]string{"y": "x"},
+			},
+			stages: []Stage{upperStage},
+			want:   []string{"X", "X"},
+		},
+		{
+			name:    "nil_stage",
+			initial: Config{subject: []string{"a"}},
+			stages:  []Stage{nil, upperStage},
+			want:    []string{"A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(":"), WithStages(tt.stages...))
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+			if got := config.String(); got != strings.Join(tt.want, ":") {
+				t.Errorf("String() = %q, want %q", got, strings.Join(tt.want, ":"))
+			}
+		})
+	}
+
+	t.Run("appends", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("b:a"),
+			WithDelim(":"),
+			WithStages(sortStage),
+			WithStages(upperStage),
+		)
+		if got := len(config.Stages()); got != 2 {
+			t.Errorf("len(Stages()) = %d, want 2", got)
+		}
+		got := slices.Collect(config.All())
+		if want := []string{"A", "B"}; !slicesEqual(got, want) {
+			t.Errorf("All() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("early_termination", func(t *testing.T) {
+		config := Make(WithSubjectItems("a:b:c"), WithDelim(":"), WithStages(upperStage))
+		collected := []string{}
+		for s := range config.All() {
+			collected = append(collected, s)
+			break
+		}
+		if !slicesEqual(collected, []string{"A"}) {
+			t.Errorf("All() early termination = %v, want [A]", collected)
+		}
+	})
+}
+
+func TestWithStageAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want string
+	}{
+		{
+			name: "dedup",
+			opts: []Option[Config]{WithStageAfter(StageDedup, upperStage)},
+			want: "z:B",
+		},
+		{
+			name: "filter",
+			opts: []Option[Config]{WithStageAfter(StageFilter, upperStage)},
+			want: "z:B",
+		},
+		{
+			name: "replace",
+			opts: []Option[Config]{WithStageAfter(StageReplace, upperStage)},
+			want: "Y:B",
+		},
+		{
+			name: "sort",
+			opts: []Option[Config]{
+				WithStageAfter(StageSort, upperStage), WithSort(strings.Compare),
+			},
+			want: "B:Y",
+		},
+		{
+			name: "pin",
+			opts: []Option[Config]{
+				WithStageAfter(StagePin, upperStage), WithPin("p", 0),
+			},
+			want: "P:Y:B",
+		},
+		{
+			name: "before_pin",
+			opts: []Option[Config]{WithStages(upperStage), WithPin("p", 0)},
+			want: "p:Y:B",
+		},
+		{
+			name: "in_order",
+			opts: []Option[Config]{
+				WithStageAfter(StageReplace, upperStage, dropStage("Y")),
+			},
+			want: "B",
+		},
+		{
+			name: "unknown",
+			opts: []Option[Config]{WithStageAfter(StageName(0), upperStage)},
+			want: "y:b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{
+				WithSubjectItems("a:b:a"), WithDelim(":"),
+				WithReplaceItems(map[string]string{"a": "y", "A": "z"}),
+			}, tt.opts...)
+
+			if got := Make(opts...).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("sort_appends", func(t *testing.T) {
+		config := Make(WithStageAfter(StageSort, sortStage, upperStage))
+		if got := len(config.Stages()); got != 2 {
+			t.Errorf("len(Stages()) = %d, want 2", got)
+		}
+
+		if config.Equal(Make(WithStageAfter(StageDedup, sortStage, upperStage))) {
+			t.Error("Equal() = true for stages inserted at different points")
+		}
+	})
+}
+
+// suffixStage returns a [Stage] that appends suffix to every item.
+func suffixStage(suffix string) Stage {
+	return func(items iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for s := range items {
+				if !yield(s + suffix) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestWithStageAfterSiblings(t *testing.T) {
+	base := Make(
+		WithSubjectItems("a"),
+		WithStageAfter(StageDedup, suffixStage("1")),
+		WithStageAfter(StageDedup, suffixStage("2")),
+		WithStageAfter(StageDedup, suffixStage("3")),
+	)
+
+	x := Wrap(base, WithStageAfter(StageDedup, suffixStage("X")))
+	y := Wrap(base, WithStageAfter(StageDedup, suffixStage("Y")))
+
+	for _, tt := range []struct {
+		config Config
+		want   string
+	}{
+		{base, "a123"},
+		{x, "a123X"},
+		{y, "a123Y"},
+	} {
+		if got := tt.config.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestWithStagesSiblings(t *testing.T) {
+	base := Make(
+		WithSubjectItems("a"),
+		WithStages(suffixStage("1")),
+		WithStages(suffixStage("2")),
+		WithStages(suffixStage("3")),
+	)
+
+	x := Wrap(base, WithStages(suffixStage("X")))
+	y := Wrap(base, WithStages(suffixStage("Y")))
+
+	for _, tt := range []struct {
+		config Config
+		want   string
+	}{
+		{base, "a123"},
+		{x, "a123X"},
+		{y, "a123Y"},
+	} {
+		if got := tt.config.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
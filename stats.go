@@ -0,0 +1,63 @@
+package mung
+
+// Stats counts the items evaluated during a realization of the munged
+// sequence.
+type Stats struct {
+	// In is the number of items split from the prefix, subject, and suffix
+	// strings.
+	In int
+	// Out is the number of munged strings.
+	Out int
+	// Duplicates is the number of items dropped because they are yielded in
+	// another position.
+	Duplicates int
+	// Removed is the number of items dropped because they match a remove rule.
+	Removed int
+	// Replaced is the number of items yielded as their replacement.
+	Replaced int
+	// Filtered is the number of items rejected by the predicate function or
+	// any built-in filters.
+	Filtered int
+}
+
+// Run is like [Config.String] but also returns [Stats] counting the items
+// evaluated, e.g., for monitoring or verbose output.
+//
+// Stages set with [WithStages] are applied to the result, and Out counts
+// the strings they yield, but the other counters do not reflect them.
+func (c Config) Run() (string, Stats) {
+	var stats Stats
+
+	c.observe = func(ev event) {
+		switch ev.op {
+		case OpKeep, OpInsert:
+			stats.In++
+		case OpDuplicate:
+			stats.In++
+			stats.Duplicates++
+		case OpRemove:
+			stats.In++
+			stats.Removed++
+		case OpFilter:
+			stats.In++
+			stats.Filtered++
+		case opSuperseded:
+			stats.Duplicates++
+		case OpReplace:
+			stats.Replaced++
+		case opOutput, opHeld, opReleased:
+		}
+	}
+
+	s := c.Tokenizer().Join(c.quoted(func(yield func(string) bool) {
+		for s := range c.Filtered() {
+			stats.Out++
+
+			if !yield(s) {
+				return
+			}
+		}
+	}))
+
+	return s, stats
+}
@@ -0,0 +1,65 @@
+package mung
+
+import "testing"
+
+func TestConfigRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option[Config]
+		want  string
+		stats Stats
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "counters",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:z:c:b:x"}),
+				WithPrefix([]string{"p"}),
+				WithSuffix([]string{"c"}),
+				WithRemove([]string{"x"}),
+				WithReplace(map[string]string{"b": "B"}),
+				WithFilter(func(s string) bool { return s != "z" }),
+			},
+			want: "p:a:B:c",
+			stats: Stats{
+				In: 8, Out: 4, Duplicates: 2, Removed: 1, Replaced: 1, Filtered: 1,
+			},
+		},
+		{
+			name: "keep_last",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:b:a"}),
+				WithPrefix([]string{"b"}),
+				WithDedupeKeepLast(),
+			},
+			want:  "b:a",
+			stats: Stats{In: 4, Out: 2, Duplicates: 2},
+		},
+		{
+			name: "allow_duplicates",
+			opts: []Option[Config]{
+				WithSubject([]string{"a:a"}),
+				WithAllowDuplicates(),
+			},
+			want:  "a:a",
+			stats: Stats{In: 2, Out: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":")}, tt.opts...)
+
+			got, stats := Make(opts...).Run()
+			if got != tt.want {
+				t.Errorf("Run() = %q, want %q", got, tt.want)
+			}
+
+			if stats != tt.stats {
+				t.Errorf("Run() stats = %+v, want %+v", stats, tt.stats)
+			}
+		})
+	}
+}
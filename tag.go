@@ -0,0 +1,105 @@
+package mung
+
+import "slices"
+
+// tag labels the items split from a string added to a scope.
+type tag struct {
+	scope Scope
+	label string
+	str   string
+}
+
+// WithPrefixTagged is like [WithPrefixItems] but also labels the items split
+// from the given strings with tag, e.g., to attribute each item to the
+// configuration fragment that added it.
+//
+// The tag of each item is reported by [Config.Explain] as [Origin.Tag].
+// If an item is tagged more than once, the last tag wins. Tags are cleared
+// along with the prefix strings, e.g., by [WithPrefix] or [WithoutPrefix].
+func WithPrefixTagged(tag string, prefixes ...string) Option[Config] {
+	return func(config Config) Config {
+		config = WithPrefixItems(prefixes...)(config)
+
+		return config.tagged(ScopePrefix, tag, prefixes)
+	}
+}
+
+// WithSuffixTagged is like [WithPrefixTagged] but adds strings to append
+// as with [WithSuffixItems].
+func WithSuffixTagged(tag string, suffixes ...string) Option[Config] {
+	return func(config Config) Config {
+		config = WithSuffixItems(suffixes...)(config)
+
+		return config.tagged(ScopeSuffix, tag, suffixes)
+	}
+}
+
+// tagged returns a copy of the receiver labeling the items split from strs in
+// the given scope with label.
+func (c Config) tagged(scope Scope, label string, strs []string) Config {
+	c.tags = slices.Clip(c.tags)
+	for _, s := range strs {
+		c.tags = append(c.tags, tag{scope: scope, label: label, str: s})
+	}
+
+	return c
+}
+
+// untagged returns a copy of the receiver without the labels of the given
+// scope.
+func (c Config) untagged(scope Scope) Config {
+	c.tags = slices.DeleteFunc(slices.Clone(c.tags),
+		func(t tag) bool { return t.scope == scope })
+
+	return c
+}
+
+// labeler returns a function that reports the label of item s yielded from
+// source, or the empty string if it has none.
+func (c Config) labeler() func(s string, source Scope) string {
+	if len(c.tags) == 0 {
+		return func(string, Scope) string { return "" }
+	}
+
+	c = c.prepare()
+
+	type labeled struct {
+		scope Scope
+		item  string
+		label string
+	}
+
+	var items []labeled
+
+	for _, t := range c.tags {
+		for s := range c.split([]string{t.str}) {
+			items = append(items, labeled{scope: t.scope, item: s, label: t.label})
+		}
+	}
+
+	if c.linear() {
+		return func(s string, source Scope) string {
+			for _, l := range slices.Backward(items) {
+				if l.scope == source && c.equalKeys(l.item, s) {
+					return l.label
+				}
+			}
+
+			return ""
+		}
+	}
+
+	labels := make(map[Scope]map[string]string)
+
+	for _, l := range items {
+		if labels[l.scope] == nil {
+			labels[l.scope] = make(map[string]string)
+		}
+
+		labels[l.scope][c.key(l.item)] = l.label
+	}
+
+	return func(s string, source Scope) string {
+		return labels[source][c.key(s)]
+	}
+}
@@ -0,0 +1,82 @@
+package mung
+
+import (
+	"strings"
+	"testing"
+)
+
+// tags returns the tag of each item of config, as reported by Explain.
+func tags(config Config) map[string]string {
+	m := map[string]string{}
+	for s, origin := range config.Explain() {
+		m[s] = origin.Tag
+	}
+
+	return m
+}
+
+func TestWithTagged(t *testing.T) {
+	base := Make(
+		WithDelim(":"),
+		WithPrefixTagged("p", "a"),
+		WithSuffixTagged("s", "z"),
+	)
+
+	tests := []struct {
+		name   string
+		config Config
+		want   map[string]string
+	}{
+		{
+			name:   "tagged",
+			config: base,
+			want:   map[string]string{"a": "p", "z": "s"},
+		},
+		{
+			name:   "prefix_replaced",
+			config: Wrap(base, WithPrefix([]string{"a"})),
+			want:   map[string]string{"a": "", "z": "s"},
+		},
+		{
+			name:   "suffix_cleared",
+			config: Wrap(base, WithoutSuffix(), WithSuffixItems("z")),
+			want:   map[string]string{"a": "p", "z": ""},
+		},
+		{
+			name:   "untagged_duplicate",
+			config: Wrap(base, WithSuffixItems("a"), WithPrependPolicy(PrependSkip)),
+			want:   map[string]string{"a": "p", "z": "s"},
+		},
+		{
+			name:   "merged",
+			config: base.Merge(Make(WithPrefixTagged("q", "a"))),
+			want:   map[string]string{"a": "q", "z": "s"},
+		},
+		{
+			name: "linear",
+			config: Wrap(base, WithEqual(func(a, b string) bool {
+				return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+			}), WithPrefixTagged("q", "b/"), WithSubjectItems("b")),
+			want: map[string]string{"a": "p", "b/": "q", "z": "s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tags(tt.config)
+			if len(got) != len(tt.want) {
+				t.Fatalf("tags = %v, want %v", got, tt.want)
+			}
+
+			for s, tag := range tt.want {
+				if got[s] != tag {
+					t.Errorf("tag of %q = %q, want %q", s, got[s], tag)
+				}
+			}
+		})
+	}
+
+	if base.Equal(Make(WithDelim(":"), WithPrefixItems("a"), WithSuffixItems("z"))) {
+		t.Error("Equal() = true for configurations with and without tags")
+	}
+}
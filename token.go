@@ -0,0 +1,588 @@
+package mung
+
+import (
+	"fmt"
+	"iter"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Tokenizer splits strings into items and joins items into strings.
+//
+// The default Tokenizer of a [Config] is a [DelimTokenizer] configured by
+// [WithDelim], [WithDelims], [WithDelimPattern], [WithEscape], and
+// [WithQuotes].
+// Use [WithTokenizer] to select a different Tokenizer.
+type Tokenizer interface {
+	// Split returns a sequence of the items contained in s.
+	Split(s string) iter.Seq[string]
+	// Join returns a string containing each item in the given sequence,
+	// such that Split would return the same items.
+	Join(items iter.Seq[string]) string
+}
+
+// DelimTokenizer is a [Tokenizer] that splits strings on a delimiter.
+// Empty items are ignored when splitting.
+//
+// Strings are also split on each of the alternate delimiters Alt, if any,
+// but items are always joined with Delim.
+// If Pattern is non-nil, strings are split on each match of Pattern instead of
+// Delim and Alt.
+// If Output is non-empty, items are joined with Output instead of Delim.
+//
+// If Escape or Quotes is set, items may contain the delimiters as described
+// by [WithEscape] and [WithQuotes], respectively.
+// If EscapeOutput is set, items are escaped when joining as if Escape were
+// set, but not unescaped when splitting, as described by [WithEscapeOutput].
+// When joining with Output, only Output is escaped or quoted.
+type DelimTokenizer struct {
+	Delim        string
+	Alt          []string
+	Pattern      *regexp.Regexp
+	Output       string
+	Escape       bool
+	Quotes       bool
+	EscapeOutput bool
+}
+
+// Split returns a sequence of the non-empty items in s separated by
+// the delimiters.
+func (d DelimTokenizer) Split(s string) iter.Seq[string] {
+	syn := d.syntax()
+
+	switch {
+	case d.Pattern != nil && syn == 0:
+		return splitPattern(d.Pattern, []string{s})
+	case syn != 0 || d.Pattern != nil || len(d.Alt) > 0:
+		return splitSyntax(d.delims(), syn, []string{s})
+	default:
+		return split(d.Delim, []string{s})
+	}
+}
+
+// Join returns the given items separated by the delimiter.
+//
+// The items are written into a buffer reused across calls and then copied
+// into a string of their exact total length, so that joining allocates little
+// more than the result.
+func (d DelimTokenizer) Join(items iter.Seq[string]) string {
+	buf, _ := joinPool.Get().(*[]byte)
+	b := (*buf)[:0]
+
+	d.join(func(s string) { b = append(b, s...) }, items)
+
+	s := string(b)
+
+	// Very large buffers are not reused, so that a single long join does not
+	// hold on to their memory indefinitely.
+	if cap(b) <= maxPooledJoin {
+		*buf = b[:0]
+		joinPool.Put(buf)
+	}
+
+	return s
+}
+
+// maxPooledJoin is the capacity of the largest buffer kept in joinPool.
+const maxPooledJoin = 1 << 20
+
+// joinPool holds the buffers reused by [DelimTokenizer.Join].
+var joinPool = sync.Pool{New: func() any { return new([]byte) }}
+
+// join passes the given items separated by the delimiter to write.
+func (d DelimTokenizer) join(write func(string), items iter.Seq[string]) {
+	syn, delim, delims := d.joinSyntax(), d.Delim, d.delims()
+	if d.Output != "" {
+		delim, delims = d.Output, delimSet{delims: []string{d.Output}}
+	}
+
+	n := 0 // length written
+
+	for s := range items {
+		if n > 0 {
+			write(delim)
+			n += len(delim)
+		}
+
+		s = encodeItem(delims, syn, s)
+		write(s)
+		n += len(s)
+	}
+}
+
+// delims returns the set of delimiters recognized when splitting strings.
+func (d DelimTokenizer) delims() delimSet {
+	if d.Pattern != nil {
+		return delimSet{pattern: d.Pattern}
+	}
+
+	delims := make([]string, 1, 1+len(d.Alt))
+	delims[0] = d.Delim
+
+	for _, alt := range d.Alt {
+		if alt != "" {
+			delims = append(delims, alt)
+		}
+	}
+
+	return delimSet{delims: delims}
+}
+
+func (d DelimTokenizer) syntax() syntax {
+	var syn syntax
+	if d.Escape {
+		syn |= syntaxEscape
+	}
+
+	if d.Quotes {
+		syn |= syntaxQuote
+	}
+
+	return syn
+}
+
+// joinSyntax returns the syntax of the special characters encoded when
+// joining items.
+func (d DelimTokenizer) joinSyntax() syntax {
+	if d.EscapeOutput {
+		return d.syntax() | syntaxEscape
+	}
+
+	return d.syntax()
+}
+
+// WithTokenizer returns an option that sets the [Tokenizer] used to split
+// strings into items and join items into strings.
+//
+// The Tokenizer replaces the default [DelimTokenizer], so options [WithDelim],
+// [WithDelims], [WithDelimPattern], [WithEscape], and [WithQuotes] have no
+// effect on splitting or joining.
+// A nil Tokenizer restores the default.
+func WithTokenizer(tok Tokenizer) Option[Config] {
+	return func(config Config) Config {
+		config.tok = tok
+
+		return config
+	}
+}
+
+// Tokenizer returns the [Tokenizer] used to split and join strings.
+func (c Config) Tokenizer() Tokenizer {
+	if c.tok == nil {
+		return DelimTokenizer{
+			Delim:        c.delim,
+			Alt:          c.alt,
+			Pattern:      c.pattern,
+			Output:       c.output,
+			Escape:       c.syntax&syntaxEscape != 0,
+			Quotes:       c.syntax&syntaxQuote != 0,
+			EscapeOutput: c.syntax&syntaxEscapeOutput != 0,
+		}
+	}
+
+	return c.tok
+}
+
+// syntax identifies the special characters recognized when splitting and
+// joining items.
+type syntax int
+
+const (
+	// syntaxEscape recognizes backslash-escaped delimiters.
+	syntaxEscape syntax = 1 << iota
+	// syntaxQuote recognizes single- and double-quoted items.
+	syntaxQuote
+	// syntaxEscapeOutput escapes delimiters when joining items only.
+	syntaxEscapeOutput
+)
+
+const (
+	// escapeChar is the character used to escape delimiters within items.
+	escapeChar = '\\'
+	// quoteChars are the characters used to quote items.
+	quoteChars = `"'`
+)
+
+// WithDelims returns an option that sets multiple string tokenizing
+// delimiters. Strings are split on any of the given delimiters,
+// and items are joined with the first.
+//
+// For example, WithDelims(":", ";") normalizes values copied between
+// Windows and Unix-like systems to use ":".
+// Where delimiters overlap, the longest matching delimiter is recognized.
+func WithDelims(delim string, alt ...string) Option[Config] {
+	return func(config Config) Config {
+		config.delim = delim
+		config.alt = alt
+
+		return config
+	}
+}
+
+// Delims returns the delimiter used for joining strings followed by any
+// alternate delimiters used for splitting strings.
+func (c Config) Delims() []string {
+	return append([]string{c.delim}, c.alt...)
+}
+
+// WithOutputDelim returns an option that sets the delimiter used to join items
+// into strings, such that strings may be split on one delimiter and joined
+// with another.
+//
+// For example, WithDelim(":") and WithOutputDelim("\n") parse a PATH-like
+// string and emit one item per line.
+// An empty delimiter restores joining with the delimiter set by [WithDelim].
+func WithOutputDelim(delim string) Option[Config] {
+	return func(config Config) Config {
+		config.output = delim
+
+		return config
+	}
+}
+
+// OutputDelim returns the delimiter used for joining strings.
+func (c Config) OutputDelim() string {
+	if c.output != "" {
+		return c.output
+	}
+
+	return c.delim
+}
+
+// WithDelimPattern returns an option that splits strings on each match of
+// the given regular expression instead of the delimiters set by [WithDelim]
+// or [WithDelims].
+// Items are still joined with the delimiter set by [WithDelim].
+//
+// For example, WithDelimPattern(regexp.MustCompile(`[,;]\s*`)) splits lists
+// separated by commas or semicolons and any amount of trailing whitespace.
+// Empty matches are ignored. A nil pattern restores splitting on delimiters.
+func WithDelimPattern(pattern *regexp.Regexp) Option[Config] {
+	return func(config Config) Config {
+		config.pattern = pattern
+
+		return config
+	}
+}
+
+// WithDelimRegexp is like [WithDelimPattern] but compiles the given regular
+// expression, failing if expr cannot be parsed. See [regexp.Compile].
+// An empty expr restores splitting on delimiters.
+func WithDelimRegexp(expr string) OptionE[Config] {
+	return func(config Config) (Config, error) {
+		if expr == "" {
+			return WithDelimPattern(nil)(config), nil
+		}
+
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return config, fmt.Errorf("delimiter pattern: %w", err)
+		}
+
+		return WithDelimPattern(pattern)(config), nil
+	}
+}
+
+// WithEscape returns an option that allows items to contain the delimiter by
+// escaping it with a backslash.
+//
+// When splitting, a backslash followed by the delimiter is replaced with the
+// delimiter, and a pair of backslashes is replaced with a single backslash.
+// Any other backslash is retained literally, so paths containing backslashes
+// (e.g., on Windows) are mostly unaffected.
+//
+// [Config.String] escapes items the same way when joining, so that the result
+// splits into the same items.
+func WithEscape() Option[Config] {
+	return func(config Config) Config {
+		config.syntax |= syntaxEscape
+
+		return config
+	}
+}
+
+// WithEscapeOutput returns an option that escapes the delimiter with a
+// backslash where it occurs within an item when joining items, as with
+// [WithEscape], but does not recognize escapes when splitting strings.
+//
+// Items normally cannot contain the delimiter, but may, e.g., if introduced
+// by a replacement rule or a mapping function. Without escaping, the
+// string returned by [Config.String] then splits into more items than are
+// yielded by [Config.All]. With WithEscapeOutput, it splits into the same
+// items under [WithEscape], e.g., by a consumer that recognizes escapes,
+// while backslashes in the input are still retained literally.
+func WithEscapeOutput() Option[Config] {
+	return func(config Config) Config {
+		config.syntax |= syntaxEscapeOutput
+
+		return config
+	}
+}
+
+// WithQuotes returns an option that allows items to contain the delimiter by
+// enclosing it in single or double quotes.
+//
+// When splitting, the delimiter is not recognized between matching quotes,
+// and the quotes themselves are removed. For example, with delimiter ":",
+// the string `"a:b":c` splits into items "a:b" and "c".
+// A quoted empty string (e.g., `""`) is an empty item.
+// An unterminated quote extends to the end of the string.
+//
+// If [WithEscape] is also applied, a backslash may also escape a quote, and
+// within double quotes, a backslash escapes only a double quote or backslash.
+// Single quotes never contain escapes.
+//
+// [Config.String] quotes each item that contains the delimiter, a quote,
+// or an escape character (with [WithEscape]), or that is empty, so that the
+// result splits into the same items. Double quotes are preferred unless the
+// item contains a double quote or backslash.
+func WithQuotes() Option[Config] {
+	return func(config Config) Config {
+		config.syntax |= syntaxQuote
+
+		return config
+	}
+}
+
+// splitSyntax is like [split] but splits on any of the given delimiters and
+// recognizes the special characters of the given syntax.
+// See [WithEscape] and [WithQuotes] for the rules of each syntax.
+//
+// If the delimiter set is empty, splitSyntax is equivalent to [split] with
+// an empty delimiter.
+func splitSyntax(
+	delims delimSet, syn syntax, slices ...[]string,
+) iter.Seq[string] {
+	if delims.empty() {
+		return split("", slices...)
+	}
+
+	escape, quotes := syn&syntaxEscape != 0, syn&syntaxQuote != 0
+
+	return func(yield func(string) bool) {
+		var sb strings.Builder
+
+		for _, slice := range slices {
+			for _, str := range slice {
+				var (
+					quote  byte // the open quote, or 0 if not quoted
+					quoted bool // whether a quote appeared in the current item
+				)
+
+				for i := 0; i <= len(str); {
+					if quote != 0 {
+						switch {
+						case i == len(str) || str[i] == quote:
+							quote = 0
+							i = min(i+1, len(str))
+
+						case escape && quote == '"' && str[i] == escapeChar &&
+							i+1 < len(str) && strings.IndexByte(`"\`, str[i+1]) >= 0:
+							sb.WriteByte(str[i+1])
+
+							i += 2
+
+						default:
+							sb.WriteByte(str[i])
+
+							i++
+						}
+
+						continue
+					}
+
+					if n := delims.match(str[i:]); n > 0 || i == len(str) {
+						if sb.Len() > 0 || quoted { // skip empty elements
+							if !yield(sb.String()) {
+								return
+							}
+
+							sb.Reset()
+
+							quoted = false
+						}
+
+						i += max(n, 1)
+
+						continue
+					}
+
+					switch {
+					case quotes && strings.IndexByte(quoteChars, str[i]) >= 0:
+						quote, quoted = str[i], true
+
+						i++
+
+					case escape && str[i] == escapeChar &&
+						delims.match(str[i+1:]) > 0:
+						n := delims.match(str[i+1:])
+						sb.WriteString(str[i+1 : i+1+n])
+
+						i += 1 + n
+
+					case escape && str[i] == escapeChar && i+1 < len(str) &&
+						(str[i+1] == escapeChar ||
+							quotes && strings.IndexByte(quoteChars, str[i+1]) >= 0):
+						sb.WriteByte(str[i+1])
+
+						i += 2
+
+					default:
+						sb.WriteByte(str[i])
+
+						i++
+					}
+				}
+			}
+		}
+	}
+}
+
+// splitPattern is like [split] but splits on each non-empty match of pattern.
+func splitPattern(pattern *regexp.Regexp, slices ...[]string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, slice := range slices {
+			for _, str := range slice {
+				pos := 0
+
+				for _, loc := range pattern.FindAllStringIndex(str, -1) {
+					if loc[0] == loc[1] {
+						continue // ignore empty matches
+					}
+
+					if loc[0] > pos && !yield(str[pos:loc[0]]) {
+						return
+					}
+
+					pos = loc[1]
+				}
+
+				if pos < len(str) && !yield(str[pos:]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// delimSet is the set of delimiters recognized when splitting strings:
+// either a list of delimiter strings or a regular expression.
+type delimSet struct {
+	delims  []string
+	pattern *regexp.Regexp
+}
+
+// empty reports whether the set contains no non-empty delimiter.
+func (d delimSet) empty() bool {
+	return d.pattern == nil && strings.Join(d.delims, "") == ""
+}
+
+// match returns the length of the longest non-empty delimiter that
+// prefixes s, or 0 if no delimiter prefixes s.
+func (d delimSet) match(s string) int {
+	if d.pattern != nil {
+		if loc := d.pattern.FindStringIndex(s); loc != nil && loc[0] == 0 {
+			return loc[1]
+		}
+
+		return 0
+	}
+
+	n := 0
+
+	for _, delim := range d.delims {
+		if len(delim) > n && strings.HasPrefix(s, delim) {
+			n = len(delim)
+		}
+	}
+
+	return n
+}
+
+// in reports whether s contains any non-empty delimiter.
+func (d delimSet) in(s string) bool {
+	if d.pattern != nil {
+		return slices.ContainsFunc(
+			d.pattern.FindAllStringIndex(s, -1),
+			func(loc []int) bool { return loc[0] < loc[1] },
+		)
+	}
+
+	return slices.ContainsFunc(d.delims, func(delim string) bool {
+		return delim != "" && strings.Contains(s, delim)
+	})
+}
+
+// encodeItem returns item s encoded with the special characters of the given
+// syntax, such that [splitSyntax] splits the result back into s.
+func encodeItem(delims delimSet, syn syntax, s string) string {
+	switch {
+	case syn&syntaxQuote != 0:
+		return quoteItem(delims, syn, s)
+	case syn&syntaxEscape != 0:
+		return escapeDelim(delims, s)
+	default:
+		return s
+	}
+}
+
+// escapeDelim returns item s with each delimiter and any backslash that would
+// otherwise be interpreted as an escape character escaped with a backslash.
+//
+// The result is split back into s by [splitSyntax] with [syntaxEscape].
+func escapeDelim(delims delimSet, s string) string {
+	if !strings.ContainsRune(s, escapeChar) && !delims.in(s) {
+		return s
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(s) + len(s)/2)
+
+	for i := 0; i < len(s); {
+		if n := delims.match(s[i:]); n > 0 {
+			sb.WriteByte(escapeChar)
+			sb.WriteString(s[i : i+n])
+
+			i += n
+
+			continue
+		}
+
+		if s[i] == escapeChar &&
+			(i+1 == len(s) || s[i+1] == escapeChar ||
+				delims.match(s[i+1:]) > 0) {
+			sb.WriteByte(escapeChar)
+		}
+
+		sb.WriteByte(s[i])
+
+		i++
+	}
+
+	return sb.String()
+}
+
+// quoteItem returns item s enclosed in quotes if it contains a delimiter or
+// any other special character of the given syntax, or if it is empty.
+//
+// The result is split back into s by [splitSyntax] with [syntaxQuote].
+func quoteItem(delims delimSet, syn syntax, s string) string {
+	special := quoteChars
+	if syn&syntaxEscape != 0 {
+		special += string(escapeChar)
+	}
+
+	if s != "" && !strings.ContainsAny(s, special) && !delims.in(s) {
+		return s
+	}
+
+	if !strings.ContainsAny(s, `"\`) {
+		return `"` + s + `"`
+	}
+
+	// Close the single quote, insert a double-quoted single quote, and reopen.
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,846 @@
+package mung
+
+import (
+	"cmp"
+	"iter"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithEscape(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		want    []string
+		str     string
+	}{
+		{
+			name:    "escaped_delimiter",
+			initial: Config{subject: []string{`a\:b:c`}},
+			want:    []string{"a:b", "c"},
+			str:     `a\:b:c`,
+		},
+		{
+			name:    "escaped_backslash",
+			initial: Config{subject: []string{`a\\:b`}},
+			want:    []string{`a\`, "b"},
+			str:     `a\\:b`,
+		},
+		{
+			name:    "literal_backslash",
+			initial: Config{subject: []string{`a\b:c\d`}},
+			want:    []string{`a\b`, `c\d`},
+			str:     `a\b:c\d`,
+		},
+		{
+			name: "replacement_escaped_on_join",
+			initial: Config{
+				subject: []string{"a:b"},
+				replace: map[string]string{"b": "x:y"},
+			},
+			want: []string{"a", "x:y"},
+			str:  `a:x\:y`,
+		},
+		{
+			name: "remove_escaped_item",
+			initial: Config{
+				subject: []string{`a\:b:a:b`},
+				remove:  []string{`a\:b`},
+			},
+			want: []string{"a", "b"},
+			str:  "a:b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, WithDelim(":"), WithEscape())
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+			str := config.String()
+			if str != tt.str {
+				t.Errorf("String() = %q, want %q", str, tt.str)
+			}
+			// The joined result must split into the same items.
+			again := slices.Collect(Make(WithSubjectItems(str), WithDelim(":"), WithEscape()).All())
+			if !slicesEqual(again, tt.want) {
+				t.Errorf("String() round trip = %v, want %v", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithEscapeOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+		str     string
+	}{
+		{
+			name:    "escape_not_recognized",
+			initial: Config{subject: []string{`a\:b`}},
+			want:    []string{`a\`, "b"},
+			str:     `a\\:b`,
+		},
+		{
+			name:    "literal_backslash",
+			initial: Config{subject: []string{`a\b:c`}},
+			want:    []string{`a\b`, "c"},
+			str:     `a\b:c`,
+		},
+		{
+			name: "replacement",
+			initial: Config{
+				subject: []string{"a:b"},
+				replace: map[string]string{"b": "x:y"},
+			},
+			want: []string{"a", "x:y"},
+			str:  `a:x\:y`,
+		},
+		{
+			name:    "mapping",
+			initial: Config{subject: []string{"a/b:c"}},
+			opts: []Option[Config]{WithMap(func(s string) string {
+				return strings.ReplaceAll(s, "/", ":")
+			})},
+			want: []string{"a:b", "c"},
+			str:  `a\:b:c`,
+		},
+		{
+			name:    "output_delim",
+			initial: Config{subject: []string{"a b:c"}},
+			opts:    []Option[Config]{WithOutputDelim(" ")},
+			want:    []string{"a b", "c"},
+			str:     `a\ b c`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":"), WithEscapeOutput()}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+			str := config.String()
+			if str != tt.str {
+				t.Errorf("String() = %q, want %q", str, tt.str)
+			}
+			// The joined result must split into the same items with escapes.
+			delim := config.OutputDelim()
+			again := slices.Collect(Make(WithSubjectItems(str), WithDelim(delim), WithEscape()).All())
+			if !slicesEqual(again, tt.want) {
+				t.Errorf("String() round trip = %v, want %v", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSyntaxEscape(t *testing.T) {
+	tests := []struct {
+		name  string
+		delim string
+		input []string
+		want  []string
+	}{
+		{"empty_input", ":", []string{}, []string{}},
+		{"no_escapes", ":", []string{"a:b", "c"}, []string{"a", "b", "c"}},
+		{"empty_elements", ":", []string{"::a:::b:"}, []string{"a", "b"}},
+		{"escaped_delim", ":", []string{`a\:b`}, []string{"a:b"}},
+		{"escaped_only", ":", []string{`\:`}, []string{":"}},
+		{"escaped_backslash", ":", []string{`a\\:b`}, []string{`a\`, "b"}},
+		{"trailing_backslash", ":", []string{`a\`}, []string{`a\`}},
+		{"literal_backslash", ":", []string{`a\b`}, []string{`a\b`}},
+		{"multi_char_delim", "::", []string{`a\::b::c`}, []string{"a::b", "c"}},
+		{"empty_delim", "", []string{"ab"}, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(splitSyntax(delimSet{delims: []string{tt.delim}}, syntaxEscape, tt.input))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("splitSyntax(%q, %q) = %q, want %q", tt.delim, tt.input, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("early_termination", func(t *testing.T) {
+		collected := []string{}
+		splitSyntax(delimSet{delims: []string{":"}}, syntaxEscape, []string{"a:b:c"})(func(s string) bool {
+			collected = append(collected, s)
+			return false
+		})
+		if !slicesEqual(collected, []string{"a"}) {
+			t.Errorf("splitSyntax() early termination = %v, want [a]", collected)
+		}
+	})
+}
+
+func TestEscapeDelim(t *testing.T) {
+	tests := []struct {
+		name  string
+		delim string
+		in    string
+		want  string
+	}{
+		{"plain", ":", "abc", "abc"},
+		{"delim", ":", "a:b", `a\:b`},
+		{"literal_backslash", ":", `a\b`, `a\b`},
+		{"backslash_before_delim", ":", `a\:b`, `a\\\:b`},
+		{"double_backslash", ":", `a\\b`, `a\\\b`},
+		{"trailing_backslash", ":", `a\`, `a\\`},
+		{"empty_delim", "", "a:b", "a:b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := escapeDelim(delimSet{delims: []string{tt.delim}}, tt.in)
+			if got != tt.want {
+				t.Errorf("escapeDelim(%q, %q) = %q, want %q", tt.delim, tt.in, got, tt.want)
+			}
+			if tt.delim == "" {
+				return
+			}
+			back := slices.Collect(splitSyntax(delimSet{delims: []string{tt.delim}}, syntaxEscape, []string{got}))
+			if !slicesEqual(back, []string{tt.in}) {
+				t.Errorf("splitSyntax(escapeDelim(%q)) = %q, want [%q]", tt.in, back, tt.in)
+			}
+		})
+	}
+}
+
+func TestWithQuotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+		str     string
+	}{
+		{
+			name:    "double_quoted_delimiter",
+			initial: Config{subject: []string{`"a b" c`}},
+			want:    []string{"a b", "c"},
+			str:     `"a b" c`,
+		},
+		{
+			name:    "single_quoted_delimiter",
+			initial: Config{subject: []string{`-DNAME='x y' -O2`}},
+			want:    []string{"-DNAME=x y", "-O2"},
+			str:     `"-DNAME=x y" -O2`,
+		},
+		{
+			name:    "quotes_within_item",
+			initial: Config{subject: []string{`say "it's" fine`}},
+			want:    []string{"say", "it's", "fine"},
+			str:     `say "it's" fine`,
+		},
+		{
+			name:    "empty_quoted_item",
+			initial: Config{subject: []string{`a "" b`}},
+			want:    []string{"a", "", "b"},
+			str:     `a "" b`,
+		},
+		{
+			name:    "double_quote_in_item",
+			initial: Config{subject: []string{`'say "hi"'`}},
+			want:    []string{`say "hi"`},
+			str:     `'say "hi"'`,
+		},
+		{
+			name:    "both_quotes_in_item",
+			initial: Config{subject: []string{`'a"b'"'"c`}},
+			want:    []string{`a"b'c`},
+			str:     `'a"b'"'"'c'`,
+		},
+		{
+			name: "remove_quoted_item",
+			initial: Config{
+				subject: []string{`"x y" x y`},
+				remove:  []string{`'x y'`},
+			},
+			want: []string{"x", "y"},
+			str:  "x y",
+		},
+		{
+			name:    "with_escape",
+			initial: Config{subject: []string{`\"a "b\" c\\" d\ e`}},
+			opts:    []Option[Config]{WithEscape()},
+			want:    []string{`"a`, `b" c\`, "d e"},
+			str:     `'"a' 'b" c\' "d e"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(" "), WithQuotes()}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+			str := config.String()
+			if str != tt.str {
+				t.Errorf("String() = %q, want %q", str, tt.str)
+			}
+			// The joined result must split into the same items.
+			again := slices.Collect(Wrap(Config{subject: []string{str}}, opts...).All())
+			if !slicesEqual(again, tt.want) {
+				t.Errorf("String() round trip = %q, want %q", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSyntaxQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		delim string
+		input []string
+		want  []string
+	}{
+		{"no_quotes", ":", []string{"a:b"}, []string{"a", "b"}},
+		{"quoted_delim", ":", []string{`"a:b":c`}, []string{"a:b", "c"}},
+		{"adjacent_quotes", ":", []string{`"a:"'b:'c`}, []string{"a:b:c"}},
+		{"unterminated", ":", []string{`a:"b:c`}, []string{"a", "b:c"}},
+		{"empty_quotes", ":", []string{`a:'':b`}, []string{"a", "", "b"}},
+		{"backslash_literal", ":", []string{`"a\":b`}, []string{`a\`, "b"}},
+		{"separate_strings", ":", []string{`"a`, `b"`}, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(splitSyntax(delimSet{delims: []string{tt.delim}}, syntaxQuote, tt.input))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("splitSyntax(%q, %q) = %q, want %q", tt.delim, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteItem(t *testing.T) {
+	tests := []struct {
+		name string
+		syn  syntax
+		in   string
+		want string
+	}{
+		{"plain", syntaxQuote, "abc", "abc"},
+		{"empty", syntaxQuote, "", `""`},
+		{"delim", syntaxQuote, "a:b", `"a:b"`},
+		{"single_quote", syntaxQuote, "a'b", `"a'b"`},
+		{"double_quote", syntaxQuote, `a"b`, `'a"b'`},
+		{"backslash_plain", syntaxQuote, `a\b`, `a\b`},
+		{"backslash_escape", syntaxQuote | syntaxEscape, `a\b`, `'a\b'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := quoteItem(delimSet{delims: []string{":"}}, tt.syn, tt.in)
+			if got != tt.want {
+				t.Errorf("quoteItem(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			back := slices.Collect(splitSyntax(delimSet{delims: []string{":"}}, tt.syn, []string{got}))
+			if !slicesEqual(back, []string{tt.in}) {
+				t.Errorf("splitSyntax(quoteItem(%q)) = %q, want [%q]", tt.in, back, tt.in)
+			}
+		})
+	}
+}
+
+func TestWithDelims(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+		str     string
+	}{
+		{
+			name:    "mixed_delimiters",
+			initial: Config{subject: []string{"a:b;c", "d;a"}},
+			want:    []string{"a", "b", "c", "d"},
+			str:     "a:b:c:d",
+		},
+		{
+			name: "remove_and_prefix",
+			initial: Config{
+				subject: []string{"a;b:c"},
+				prefix:  []string{"x;c"},
+				remove:  []string{"b"},
+			},
+			want: []string{"x", "c", "a"},
+			str:  "x:c:a",
+		},
+		{
+			name:    "escape",
+			initial: Config{subject: []string{`a\;b;c:d`}},
+			opts:    []Option[Config]{WithEscape()},
+			want:    []string{"a;b", "c", "d"},
+			str:     `a\;b:c:d`,
+		},
+		{
+			name:    "quotes",
+			initial: Config{subject: []string{`"a;b";c`}},
+			opts:    []Option[Config]{WithQuotes()},
+			want:    []string{"a;b", "c"},
+			str:     `"a;b":c`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelims(":", ";")}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+			str := config.String()
+			if str != tt.str {
+				t.Errorf("String() = %q, want %q", str, tt.str)
+			}
+			// The joined result must split into the same items.
+			again := slices.Collect(Wrap(Config{subject: []string{str}}, opts...).All())
+			if !slicesEqual(again, tt.want) {
+				t.Errorf("String() round trip = %q, want %q", again, tt.want)
+			}
+		})
+	}
+
+	t.Run("delims", func(t *testing.T) {
+		config := Make(WithDelims(":", ";", ","))
+		if got, want := config.Delims(), []string{":", ";", ","}; !slicesEqual(got, want) {
+			t.Errorf("Delims() = %q, want %q", got, want)
+		}
+		config.Delims()[0] = "x"
+		if got := config.Delims()[0]; got != ":" {
+			t.Errorf("Delims()[0] = %q after mutation, want %q", got, ":")
+		}
+	})
+
+	t.Run("with_delim_clears_alternates", func(t *testing.T) {
+		config := Make(WithDelims(":", ";"), WithDelim(","))
+		if got, want := config.Delims(), []string{","}; !slicesEqual(got, want) {
+			t.Errorf("Delims() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestSplitSyntaxDelims(t *testing.T) {
+	tests := []struct {
+		name   string
+		delims []string
+		syn    syntax
+		input  []string
+		want   []string
+	}{
+		{"plain", []string{":", ";"}, 0, []string{"a:b;;c"}, []string{"a", "b", "c"}},
+		{"longest_match", []string{":", "::"}, syntaxEscape, []string{`a\::b::c`}, []string{"a::b", "c"}},
+		{"empty_alternate", []string{":", ""}, 0, []string{"ab:c"}, []string{"ab", "c"}},
+		{"all_empty", []string{"", ""}, 0, []string{"ab"}, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(splitSyntax(delimSet{delims: tt.delims}, tt.syn, tt.input))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("splitSyntax(%q, %q) = %q, want %q", tt.delims, tt.input, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("escape_round_trip", func(t *testing.T) {
+		delims := delimSet{delims: []string{":", ";"}}
+		for _, in := range []string{"a;b", `a\;b`, "a:b;c", `a\`} {
+			got := escapeDelim(delims, in)
+			back := slices.Collect(splitSyntax(delims, syntaxEscape, []string{got}))
+			if !slicesEqual(back, []string{in}) {
+				t.Errorf("splitSyntax(escapeDelim(%q)) = %q, want [%q]", in, back, in)
+			}
+		}
+	})
+}
+
+func TestWithOutputDelim(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    string
+	}{
+		{
+			name:    "newline",
+			initial: Config{subject: []string{"/a:/b:/a"}},
+			opts:    []Option[Config]{WithOutputDelim("\n")},
+			want:    "/a\n/b",
+		},
+		{
+			name:    "empty_restores_delim",
+			initial: Config{subject: []string{"/a:/b"}},
+			opts:    []Option[Config]{WithOutputDelim(";"), WithOutputDelim("")},
+			want:    "/a:/b",
+		},
+		{
+			name:    "escape_output_delim",
+			initial: Config{subject: []string{`a;b:c\:d`}},
+			opts:    []Option[Config]{WithOutputDelim(";"), WithEscape()},
+			want:    `a\;b;c:d`,
+		},
+		{
+			name:    "quote_output_delim",
+			initial: Config{subject: []string{`"a b":c:'d;e'`}},
+			opts:    []Option[Config]{WithOutputDelim(";"), WithQuotes()},
+			want:    `a b;c;"d;e"`,
+		},
+		{
+			name:    "delims",
+			initial: Config{subject: []string{"a;b:c"}},
+			opts:    []Option[Config]{WithDelims(":", ";"), WithOutputDelim(",")},
+			want:    "a,b,c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(":")}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			if got := config.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("output_delim", func(t *testing.T) {
+		config := Make(WithDelim(":"))
+		if got := config.OutputDelim(); got != ":" {
+			t.Errorf("OutputDelim() = %q, want %q", got, ":")
+		}
+		config = Wrap(config, WithOutputDelim("\n"))
+		if got := config.OutputDelim(); got != "\n" {
+			t.Errorf("OutputDelim() = %q, want %q", got, "\n")
+		}
+		if got := config.Delim(); got != ":" {
+			t.Errorf("Delim() = %q, want %q", got, ":")
+		}
+	})
+}
+
+func TestWithDelimPattern(t *testing.T) {
+	pattern := regexp.MustCompile(`[,;]\s*`)
+
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+		str     string
+	}{
+		{
+			name:    "mixed_separators",
+			initial: Config{subject: []string{"a, b;c;  d,,e"}},
+			want:    []string{"a", "b", "c", "d", "e"},
+			str:     "a,b,c,d,e",
+		},
+		{
+			name: "remove_and_dedupe",
+			initial: Config{
+				subject: []string{"a; b, a"},
+				remove:  []string{"x, b"},
+			},
+			want: []string{"a"},
+			str:  "a",
+		},
+		{
+			name:    "escape",
+			initial: Config{subject: []string{`a\, b; c`}},
+			opts:    []Option[Config]{WithEscape()},
+			want:    []string{"a, b", "c"},
+			str:     `a\, b,c`,
+		},
+		{
+			name:    "quotes",
+			initial: Config{subject: []string{`"a; b"; c`}},
+			opts:    []Option[Config]{WithQuotes()},
+			want:    []string{"a; b", "c"},
+			str:     `"a; b",c`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(","), WithDelimPattern(pattern)}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+			str := config.String()
+			if str != tt.str {
+				t.Errorf("String() = %q, want %q", str, tt.str)
+			}
+			// The joined result must split into the same items.
+			again := slices.Collect(Wrap(Config{subject: []string{str}}, opts...).All())
+			if !slicesEqual(again, tt.want) {
+				t.Errorf("String() round trip = %q, want %q", again, tt.want)
+			}
+		})
+	}
+
+	t.Run("nil_restores_delim", func(t *testing.T) {
+		config := Make(
+			WithSubjectItems("a;b:c"),
+			WithDelim(":"),
+			WithDelimPattern(pattern),
+			WithDelimPattern(nil),
+		)
+		if got, want := slices.Collect(config.All()), []string{"a;b", "c"}; !slicesEqual(got, want) {
+			t.Errorf("All() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestWithDelimRegexp(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{name: "pattern", expr: `[,;]\s*`, want: `[,;]\s*`},
+		{name: "empty", expr: "", want: ""},
+		{name: "invalid", expr: `[,;`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			init := Config{pattern: regexp.MustCompile(`x`)}
+
+			got, err := WithDelimRegexp(tt.expr)(init)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithDelimRegexp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if got.pattern != init.pattern {
+					t.Errorf("WithDelimRegexp() modified config on error")
+				}
+
+				return
+			}
+
+			var expr string
+			if got.pattern != nil {
+				expr = got.pattern.String()
+			}
+
+			if expr != tt.want {
+				t.Errorf("WithDelimRegexp() pattern = %q, want %q", expr, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		input   []string
+		want    []string
+	}{
+		{"simple", `,`, []string{"a,b"}, []string{"a", "b"}},
+		{"empty_items", `,`, []string{",a,,b,"}, []string{"a", "b"}},
+		{"empty_matches", `\s*`, []string{"a  b"}, []string{"a", "b"}},
+		{"no_match", `,`, []string{"ab"}, []string{"ab"}},
+		{"multiple_strings", `;`, []string{"a;b", "c"}, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := regexp.MustCompile(tt.pattern)
+			got := slices.Collect(splitPattern(pattern, tt.input))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("splitPattern(%q, %q) = %q, want %q", tt.pattern, tt.input, got, tt.want)
+			}
+			// Splitting with syntax must agree when no syntax is in use.
+			got = slices.Collect(splitSyntax(delimSet{pattern: pattern}, 0, tt.input))
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("splitSyntax(%q, %q) = %q, want %q", tt.pattern, tt.input, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("early_termination", func(t *testing.T) {
+		collected := []string{}
+		splitPattern(regexp.MustCompile(`,`), []string{"a,b,c"})(func(s string) bool {
+			collected = append(collected, s)
+			return false
+		})
+		if !slicesEqual(collected, []string{"a"}) {
+			t.Errorf("splitPattern() early termination = %v, want [a]", collected)
+		}
+	})
+}
+
+// fieldsTokenizer splits strings on runs of whitespace and joins with a
+// single space.
+type fieldsTokenizer struct{}
+
+func (fieldsTokenizer) Split(s string) iter.Seq[string] {
+	return slices.Values(strings.Fields(s))
+}
+
+func (fieldsTokenizer) Join(items iter.Seq[string]) string {
+	return strings.Join(slices.Collect(items), " ")
+}
+
+func TestWithTokenizer(t *testing.T) {
+	config := Make(
+		WithSubjectItems("-O2  -Wall\t-g", "-Wall"),
+		WithPrefixItems("-g"),
+		WithRemoveItems("-O2 -O3"),
+		WithDelim(":"), // ignored
+		WithTokenizer(fieldsTokenizer{}),
+	)
+
+	got := slices.Collect(config.All())
+	if want := []string{"-g", "-Wall"}; !slicesEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if got, want := config.String(), "-g -Wall"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if _, ok := config.Tokenizer().(fieldsTokenizer); !ok {
+		t.Errorf("Tokenizer() = %T, want fieldsTokenizer", config.Tokenizer())
+	}
+
+	t.Run("nil_restores_default", func(t *testing.T) {
+		config := Wrap(config, WithTokenizer(nil))
+		want := DelimTokenizer{Delim: ":"}
+		if got := config.Tokenizer(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenizer() = %#v, want %#v", got, want)
+		}
+	})
+}
+
+func TestDelimTokenizer(t *testing.T) {
+	tests := []struct {
+		name  string
+		tok   DelimTokenizer
+		in    string
+		items []string
+		out   string
+	}{
+		{"plain", DelimTokenizer{Delim: ":"}, "a::b:", []string{"a", "b"}, "a:b"},
+		{"escape", DelimTokenizer{Delim: ":", Escape: true}, `a\:b:c`, []string{"a:b", "c"}, `a\:b:c`},
+		{"quotes", DelimTokenizer{Delim: ":", Quotes: true}, `'a:b':c`, []string{"a:b", "c"}, `"a:b":c`},
+		{"alternates", DelimTokenizer{Delim: ":", Alt: []string{";"}}, "a;b:c", []string{"a", "b", "c"}, "a:b:c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := slices.Collect(tt.tok.Split(tt.in))
+			if !slicesEqual(items, tt.items) {
+				t.Errorf("Split(%q) = %q, want %q", tt.in, items, tt.items)
+			}
+			if out := tt.tok.Join(slices.Values(items)); out != tt.out {
+				t.Errorf("Join(%q) = %q, want %q", items, out, tt.out)
+			}
+		})
+	}
+
+	t.Run("config_default", func(t *testing.T) {
+		config := Make(WithDelim(";"), WithEscape(), WithQuotes())
+		want := DelimTokenizer{Delim: ";", Escape: true, Quotes: true}
+		if got := config.Tokenizer(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenizer() = %#v, want %#v", got, want)
+		}
+	})
+}
+
+func TestDelimTokenizerJoinLarge(t *testing.T) {
+	items := make([]string, 2000)
+	for i := range items {
+		items[i] = "/opt/pkg" + strings.Repeat("x", i%7) + "/" + strconv.Itoa(i)
+	}
+
+	for _, tok := range []DelimTokenizer{
+		{Delim: ":"},
+		{Delim: ":", Output: "\n"},
+	} {
+		want := strings.Join(items, cmp.Or(tok.Output, tok.Delim))
+		if got := tok.Join(slices.Values(items)); got != want {
+			t.Errorf("Join() with %#v = %d bytes, want %d", tok, len(got), len(want))
+		}
+
+		config := Make(WithTokenizer(tok), WithSubject(items))
+		if got := config.String(); got != want {
+			t.Errorf("String() with %#v = %d bytes, want %d", tok, len(got), len(want))
+		}
+	}
+}
+
+func TestDelimTokenizerJoinReuse(t *testing.T) {
+	tok := DelimTokenizer{Delim: ":"}
+
+	var wg sync.WaitGroup
+
+	for i := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for n := range 50 {
+				items := make([]string, (i*n)%(maxPooledJoin/32))
+				for j := range items {
+					items[j] = strconv.Itoa(j)
+				}
+
+				if got, want := tok.Join(slices.Values(items)), strings.Join(items, ":"); got != want {
+					t.Errorf("Join() of %d items = %d bytes, want %d", len(items), len(got), len(want))
+
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// benchItems returns n items resembling the directories of a long PATH.
+func benchItems(n int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = "/opt/pkg" + strings.Repeat("x", i%7) + "/" + strconv.Itoa(i)
+	}
+
+	return items
+}
+
+func BenchmarkJoin(b *testing.B) {
+	tok := DelimTokenizer{Delim: ":"}
+
+	for _, n := range []int{10, 1000, 10000} {
+		items := benchItems(n)
+
+		b.Run("items="+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				tok.Join(slices.Values(items))
+			}
+		})
+	}
+}
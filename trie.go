@@ -0,0 +1,111 @@
+package mung
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// prefixTree is a radix tree of strings, used to find whether any of its
+// strings is a prefix of an item in time proportional to the length of the
+// item, regardless of how many strings it holds.
+type prefixTree struct {
+	root radixNode
+}
+
+// radixNode is a node of a [prefixTree]. A leaf node terminates a string
+// of the tree, which is the concatenation of the labels of the edges leading
+// to the node.
+type radixNode struct {
+	edges []radixEdge // sorted by the first byte of each label
+	leaf  bool
+}
+
+// radixEdge is a labeled edge of a [prefixTree]. No two edges of a node have
+// labels beginning with the same byte.
+type radixEdge struct {
+	label string
+	node  *radixNode
+}
+
+// newPrefixTree returns a new prefixTree holding each of the given strings.
+func newPrefixTree(strs ...string) *prefixTree {
+	t := &prefixTree{}
+	for _, s := range strs {
+		t.insert(s)
+	}
+
+	return t
+}
+
+// insert adds s to the receiver.
+func (t *prefixTree) insert(s string) {
+	n := &t.root
+
+	for s != "" {
+		i, found := n.find(s[0])
+		if !found {
+			n.edges = slices.Insert(n.edges, i,
+				radixEdge{label: s, node: &radixNode{leaf: true}})
+
+			return
+		}
+
+		e := &n.edges[i]
+
+		common := 1
+		for common < len(e.label) && common < len(s) &&
+			e.label[common] == s[common] {
+			common++
+		}
+
+		if common < len(e.label) {
+			// Split the edge at the end of the common prefix.
+			e.node = &radixNode{
+				edges: []radixEdge{{label: e.label[common:], node: e.node}},
+			}
+			e.label = e.label[:common]
+		}
+
+		n, s = e.node, s[common:]
+	}
+
+	n.leaf = true
+}
+
+// hasPrefixOf returns true if and only if any string of the receiver is a
+// prefix of item.
+func (t *prefixTree) hasPrefixOf(item string) bool {
+	_, ok := t.prefixOf(item)
+
+	return ok
+}
+
+// prefixOf returns the shortest string of the receiver that is a prefix of
+// item and true, or false if there is no such string.
+func (t *prefixTree) prefixOf(item string) (string, bool) {
+	n, rest := &t.root, item
+
+	for !n.leaf {
+		if rest == "" {
+			return "", false
+		}
+
+		i, found := n.find(rest[0])
+		if !found || !strings.HasPrefix(rest, n.edges[i].label) {
+			return "", false
+		}
+
+		n, rest = n.edges[i].node, rest[len(n.edges[i].label):]
+	}
+
+	return item[:len(item)-len(rest)], true
+}
+
+// find returns the index of the edge whose label begins with b and true, or
+// the index at which such an edge would be inserted and false.
+func (n *radixNode) find(b byte) (int, bool) {
+	return slices.BinarySearchFunc(n.edges, b, func(e radixEdge, b byte) int {
+		return cmp.Compare(e.label[0], b)
+	})
+}
@@ -0,0 +1,89 @@
+package mung
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPrefixTree(t *testing.T) {
+	tree := newPrefixTree("/opt/old/", "/opt/older", "/usr/local/", "/o", "/usr/lib")
+
+	tests := []struct {
+		item string
+		want bool
+	}{
+		{"", false},
+		{"/", false},
+		{"/o", true},
+		{"/opt/new", true},
+		{"/usr/local", false},
+		{"/usr/local/bin", true},
+		{"/usr/lib", true},
+		{"/usr/lib64", true},
+		{"/usr/li", false},
+		{"/usr/bin", false},
+		{"/bin", false},
+	}
+
+	for _, tt := range tests {
+		if got := tree.hasPrefixOf(tt.item); got != tt.want {
+			t.Errorf("hasPrefixOf(%q) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixTreeSplit(t *testing.T) {
+	// Inserting a prefix of an existing label splits its edge.
+	tree := newPrefixTree("/usr/local/bin", "/usr/lo", "/usr/lx")
+
+	tests := []struct {
+		item string
+		want bool
+	}{
+		{"/usr/l", false},
+		{"/usr/lo", true},
+		{"/usr/local", true},
+		{"/usr/lx/bin", true},
+		{"/usr/ly", false},
+	}
+
+	for _, tt := range tests {
+		if got := tree.hasPrefixOf(tt.item); got != tt.want {
+			t.Errorf("hasPrefixOf(%q) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestWithRulesManyPrefixes(t *testing.T) {
+	rules := make([]Rule, 0, 5000)
+	for i := range cap(rules) {
+		rules = append(rules, Rule{
+			Action: ActionRemove, Match: MatchPrefix,
+			Value: "/repo/gen/" + strconv.Itoa(i) + "/",
+		})
+	}
+
+	config, err := TryMake(
+		Try(
+			WithSubject([]string{
+				"/repo/gen/12/bin:/repo/gen/123:/repo/gen/4999/x:/usr/bin",
+			}),
+			WithDelim(":"),
+			WithExclude(func(s string) bool { return s == "/usr/bin" }),
+		),
+		WithRules(rules...),
+		WithRules(Rule{Action: ActionRemove, Match: MatchPrefix, Value: "/repo/gen/123"}),
+	)
+	if err != nil {
+		t.Fatalf("TryMake() error = %v", err)
+	}
+
+	if got, want := config.String(), ""; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	config = WithSubject([]string{"/repo/gen/1234:/repo/gen/5000/x"})(config)
+	if got, want := config.String(), "/repo/gen/5000/x"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+package mung
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"unicode/utf8"
+)
+
+// Errors returned by [Config.Validate].
+var (
+	// ErrReplaceCycle indicates replacement rules that form a cycle,
+	// e.g., "a" to "b" and "b" to "a", which swap items instead of
+	// replacing them with a common value.
+	ErrReplaceCycle = errors.New("replacement cycle")
+	// ErrReplaceDelim indicates a replacement value containing a delimiter,
+	// which is therefore split into multiple items when munged again.
+	ErrReplaceDelim = errors.New("replacement contains delimiter")
+	// ErrEmptyDelim indicates a multi-character rule used with an empty
+	// delimiter, which splits every string into single characters that the
+	// rule can never match.
+	ErrEmptyDelim = errors.New("multi-character rule with empty delimiter")
+	// ErrRemoveConflict indicates an item that is both removed and prepended,
+	// appended, or ensured, which is therefore dropped or yielded depending
+	// only on the order in which the options are applied.
+	ErrRemoveConflict = errors.New("item both removed and added")
+)
+
+// Validate returns an error if the receiver configuration is likely to
+// produce unintended results, or nil otherwise.
+// Each problem found is reported as an error wrapping one of
+// [ErrReplaceCycle], [ErrRemoveConflict], [ErrReplaceDelim], or
+// [ErrEmptyDelim], joined with [errors.Join].
+//
+// Prefix and suffix items conflict with removed items only if they are
+// removed from the prefix and suffix, respectively, according to
+// [WithRemoveScope]. Items ensured with [WithRules] always conflict.
+//
+// Validate does not realize the munged sequence.
+// Delimiters are only checked if the [Tokenizer] is a [DelimTokenizer].
+func (c Config) Validate() error {
+	var errs []error
+
+	c = c.materializeRules()
+
+	rules := slices.Sorted(maps.Keys(c.replace))
+
+	for _, cycle := range c.replaceCycles(rules) {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrReplaceCycle, cycle))
+	}
+
+	errs = append(errs, c.removeConflicts()...)
+
+	tok, ok := c.Tokenizer().(DelimTokenizer)
+	if !ok {
+		return errors.Join(errs...)
+	}
+
+	if tok.joinSyntax() == 0 {
+		// Delimiters in replacement values would be encoded by the syntax.
+		delims, output := tok.delims(), delimSet{delims: []string{tok.Output}}
+
+		for _, from := range rules {
+			if to := c.expandEnv(c.replace[from]); delims.in(to) || output.in(to) {
+				errs = append(errs,
+					fmt.Errorf("%w: %q to %q", ErrReplaceDelim, from, c.replace[from]))
+			}
+		}
+	}
+
+	if tok.delims().empty() {
+		for _, s := range append(slices.Clone(c.remove), rules...) {
+			if utf8.RuneCountInString(s) > 1 {
+				errs = append(errs, fmt.Errorf("%w: %q", ErrEmptyDelim, s))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeConflicts returns an error wrapping [ErrRemoveConflict] for each
+// distinct item that is both removed and prepended, appended, or ensured.
+func (c Config) removeConflicts() []error {
+	if len(c.remove) == 0 && len(c.removals) == 0 {
+		return nil
+	}
+
+	remove := c.memoize(c.remove)
+	if len(c.removals) > 0 {
+		remove = removalSet{set: remove, match: c.removalOf}
+	}
+
+	var added [][]string
+
+	if c.removeScope.has(ScopePrefix) {
+		added = append(added, c.prefix)
+	}
+
+	if c.removeScope.has(ScopeSuffix) {
+		added = append(added, c.suffix)
+	}
+
+	added = append(added, c.ensure)
+
+	var errs []error
+
+	seen := c.newSet()
+	for s := range c.split(added...) {
+		if remove.Contains(s) && !seen.Seen(s) {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrRemoveConflict, s))
+		}
+	}
+
+	return errs
+}
+
+// replaceCycles returns each cycle formed by the given replacement rules,
+// as the rules in the order they are followed, starting from the first rule
+// of the cycle in the given order.
+func (c Config) replaceCycles(rules []string) [][]string {
+	key := func(s string) string { return c.key(c.normalize(c.expandEnv(s))) }
+
+	// The first rule with each key wins, as in [Config.replacer].
+	next := make(map[string]string, len(rules))
+	from := make(map[string]string, len(rules))
+
+	for _, r := range rules {
+		if k := key(r); !hasKey(next, k) {
+			next[k], from[k] = key(c.replace[r]), r
+		}
+	}
+
+	var cycles [][]string
+
+	reported := Set[string]{}
+
+	for _, r := range rules {
+		start := key(r)
+		if from[start] != r || reported.Contains(start) {
+			continue
+		}
+
+		cycle := []string{r}
+		for k := next[start]; k != start; k = next[k] {
+			if !hasKey(next, k) || len(cycle) > len(next) {
+				cycle = nil // chain ends or leads into another cycle
+
+				break
+			}
+
+			cycle = append(cycle, from[k])
+		}
+
+		if len(cycle) > 1 {
+			for _, s := range cycle {
+				reported.Add(key(s))
+			}
+
+			cycles = append(cycles, cycle)
+		}
+	}
+
+	return cycles
+}
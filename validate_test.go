@@ -0,0 +1,186 @@
+package mung
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option[Config]
+		want []error
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "valid",
+			opts: []Option[Config]{
+				WithDelim(":"),
+				WithRemove([]string{"ab"}),
+				WithReplace(map[string]string{"a": "b", "b": "c", "d": "d"}),
+			},
+		},
+		{
+			name: "cycle",
+			opts: []Option[Config]{
+				WithReplace(map[string]string{"a": "b", "b": "a"}),
+			},
+			want: []error{ErrReplaceCycle},
+		},
+		{
+			name: "cycle_long",
+			opts: []Option[Config]{
+				WithReplace(map[string]string{"a": "b", "b": "c", "c": "a", "x": "a"}),
+			},
+			want: []error{ErrReplaceCycle},
+		},
+		{
+			name: "cycle_case_fold",
+			opts: []Option[Config]{
+				WithCaseFold(),
+				WithReplace(map[string]string{"a": "B", "b": "A"}),
+			},
+			want: []error{ErrReplaceCycle},
+		},
+		{
+			name: "cycles",
+			opts: []Option[Config]{
+				WithReplace(map[string]string{"a": "b", "b": "a", "c": "d", "d": "c"}),
+			},
+			want: []error{ErrReplaceCycle, ErrReplaceCycle},
+		},
+		{
+			name: "replace_delim",
+			opts: []Option[Config]{
+				WithDelim(":"),
+				WithReplace(map[string]string{"a": "b:c"}),
+			},
+			want: []error{ErrReplaceDelim},
+		},
+		{
+			name: "replace_alt_delim",
+			opts: []Option[Config]{
+				WithDelims(":", ";"),
+				WithReplace(map[string]string{"a": "b;c"}),
+			},
+			want: []error{ErrReplaceDelim},
+		},
+		{
+			name: "replace_output_delim",
+			opts: []Option[Config]{
+				WithDelim(":"),
+				WithOutputDelim(","),
+				WithReplace(map[string]string{"a": "b,c"}),
+			},
+			want: []error{ErrReplaceDelim},
+		},
+		{
+			name: "replace_delim_escaped",
+			opts: []Option[Config]{
+				WithDelim(":"),
+				WithEscape(),
+				WithReplace(map[string]string{"a": "b:c"}),
+			},
+		},
+		{
+			name: "replace_delim_escaped_output",
+			opts: []Option[Config]{
+				WithDelim(":"),
+				WithEscapeOutput(),
+				WithReplace(map[string]string{"a": "b:c"}),
+			},
+		},
+		{
+			name: "empty_delim",
+			opts: []Option[Config]{
+				WithDelim(""),
+				WithRemove([]string{"a", "bc"}),
+				WithReplace(map[string]string{"de": "f", "g": "hi"}),
+			},
+			want: []error{ErrEmptyDelim, ErrEmptyDelim},
+		},
+		{
+			name: "remove_prefix_suffix",
+			opts: []Option[Config]{
+				WithDelim(":"),
+				WithRemoveItems("a:b:c"),
+				WithPrefixItems("a:x"),
+				WithSuffixItems("b", "y", "B"),
+				WithCaseFold(),
+			},
+			want: []error{ErrRemoveConflict, ErrRemoveConflict},
+		},
+		{
+			name: "remove_scope",
+			opts: []Option[Config]{
+				WithRemoveItems("a"),
+				WithPrefixItems("a"),
+				WithRemoveScope(ScopeSubject),
+			},
+		},
+		{
+			name: "empty_delim_multibyte",
+			opts: []Option[Config]{
+				WithDelim(""),
+				WithRemove([]string{"é"}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Make(tt.opts...).Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var joined interface{ Unwrap() []error }
+			if !errors.As(err, &joined) {
+				t.Fatalf("Validate() = %v, want joined errors %v", err, tt.want)
+			}
+
+			errs := joined.Unwrap()
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want %v", err, tt.want)
+			}
+
+			for i, want := range tt.want {
+				if !errors.Is(errs[i], want) {
+					t.Errorf("Validate() error %d = %v, want %v", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	config, err := TryMake(
+		Try(WithDelim(":"), WithRemoveItems("/a"), WithSuffixItems("/tmp/s")),
+		WithRules(
+			Rule{Action: ActionEnsure, Value: "/a/"},
+			Rule{Action: ActionEnsure, Value: "/b"},
+			Rule{Action: ActionRemove, Match: MatchPrefix, Value: "/tmp/"},
+		),
+		Try(WithCleanPaths(), WithRemoveScope(ScopeSubject)),
+	)
+	if err != nil {
+		t.Fatalf("TryMake() error = %v", err)
+	}
+
+	err = config.Validate()
+	if !errors.Is(err, ErrRemoveConflict) {
+		t.Fatalf("Validate() = %v, want %v", err, ErrRemoveConflict)
+	}
+
+	if got := err.Error(); !strings.Contains(got, `"/a"`) ||
+		strings.Contains(got, "/b") || strings.Contains(got, "/tmp/s") {
+		t.Errorf("Validate() = %v, want conflict for %q only", err, "/a")
+	}
+}
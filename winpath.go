@@ -0,0 +1,249 @@
+package mung
+
+import (
+	"path"
+	"strings"
+)
+
+// WithWindowsPaths returns an option that rewrites every item to a canonical
+// Windows path name before comparison and output.
+//
+// Forward slashes are converted to backslashes, drive letters are
+// upper-cased, and trailing separators are removed, such that "c:/Windows/"
+// and `C:\Windows` are considered duplicates.
+// Items of removal and replacement rules are rewritten the same way.
+//
+// UNC paths (`\\server\share\...`) keep their leading double separator.
+// Extended-length paths (`\\?\C:\...`) are not parsed by Windows, so only
+// their drive letter and trailing separators are rewritten.
+// If [WithCleanPaths] is also applied, items are cleaned according to Windows
+// path rules instead of those of the running operating system.
+//
+// Items are rewritten the same way regardless of the running operating system.
+// Combine with [WithCaseFold] to also compare items case-insensitively.
+func WithWindowsPaths() Option[Config] {
+	return func(config Config) Config {
+		config.windows = true
+
+		return config
+	}
+}
+
+// WithIgnoreExtendedPrefix returns an option that compares Windows path names
+// without their extended-length prefix, such that `\\?\C:\x` and `C:\x`,
+// and `\\?\UNC\server\share` and `\\server\share`, are considered duplicates.
+//
+// The original spelling of each yielded item is preserved.
+func WithIgnoreExtendedPrefix() Option[Config] {
+	return func(config Config) Config {
+		config.extended = true
+
+		return config
+	}
+}
+
+const (
+	// extendedPrefix is the prefix of extended-length Windows path names.
+	extendedPrefix = `\\?\`
+	// extendedUNCPrefix is the prefix of extended-length UNC path names.
+	extendedUNCPrefix = extendedPrefix + `UNC\`
+)
+
+// windowsPath returns the canonical Windows form of path name s.
+// If clean is true, "." and ".." elements and repeated separators are also
+// removed. See [WithWindowsPaths] for the rules applied.
+func windowsPath(s string, clean bool) string {
+	literal := strings.HasPrefix(s, extendedPrefix)
+	if !literal {
+		s = strings.ReplaceAll(s, "/", `\`)
+	}
+
+	vol := windowsVolume(s)
+	rest := s[len(vol):]
+
+	if n := len(vol); n >= 2 && vol[n-1] == ':' { // drive letter
+		vol = vol[:n-2] + strings.ToUpper(vol[n-2:])
+	}
+
+	if clean && !literal && rest != "" {
+		rest = strings.ReplaceAll(
+			path.Clean(strings.ReplaceAll(rest, `\`, "/")), "/", `\`,
+		)
+	}
+
+	// Remove trailing separators, but keep the root of a drive or the
+	// current drive.
+	switch trimmed := strings.TrimRight(rest, `\`); {
+	case trimmed != "" || rest == "":
+		return vol + trimmed
+	case strings.HasPrefix(vol, `\\`) && !strings.HasSuffix(vol, ":"):
+		return vol // UNC share or device
+	default:
+		return vol + `\`
+	}
+}
+
+// windowsVolume returns the volume prefix of Windows path name s, which must
+// use backslash separators unless it is an extended-length path name.
+//
+// The volume is one of the following forms, or the empty string:
+//
+//	C:
+//	\\server\share
+//	\\?\C:
+//	\\?\UNC\server\share
+//	\\?\Volume{...}
+//	\\.\device
+func windowsVolume(s string) string {
+	if len(s) >= 2 && s[1] == ':' && isASCIILetter(s[0]) {
+		return s[:2]
+	}
+
+	if !strings.HasPrefix(s, `\\`) {
+		return ""
+	}
+
+	if len(s) >= 4 && (s[2] == '?' || s[2] == '.') && s[3] == '\\' {
+		if len(s) >= 8 && strings.EqualFold(s[4:8], `UNC\`) {
+			return s[:8] + uncShare(s[8:])
+		}
+
+		if len(s) >= 6 && s[5] == ':' && isASCIILetter(s[4]) {
+			return s[:6]
+		}
+
+		return s[:4] + firstElem(s[4:])
+	}
+
+	return `\\` + uncShare(s[2:])
+}
+
+// uncShare returns the leading "server\share" elements of s.
+func uncShare(s string) string {
+	server := firstElem(s)
+	if len(server) == len(s) {
+		return s
+	}
+
+	return server + `\` + firstElem(s[len(server)+1:])
+}
+
+// firstElem returns the leading element of s up to the first backslash.
+func firstElem(s string) string {
+	if i := strings.IndexByte(s, '\\'); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// trimExtendedPrefix returns Windows path name s without its extended-length
+// prefix, if s names a drive or UNC path.
+func trimExtendedPrefix(s string) string {
+	switch {
+	case len(s) >= len(extendedUNCPrefix) &&
+		strings.EqualFold(s[:len(extendedUNCPrefix)], extendedUNCPrefix):
+		return `\\` + s[len(extendedUNCPrefix):]
+	case strings.HasPrefix(s, extendedPrefix) &&
+		windowsVolume(s[len(extendedPrefix):]) != "":
+		return s[len(extendedPrefix):]
+	default:
+		return s
+	}
+}
+
+// wslConversion identifies how items are converted between Windows path names
+// and the corresponding path names within the Windows Subsystem for Linux.
+type wslConversion int
+
+const (
+	// wslNone does not convert items (default).
+	wslNone wslConversion = iota
+	// wslFromWindows converts Windows path names to WSL path names.
+	wslFromWindows
+	// wslToWindows converts WSL path names to Windows path names.
+	wslToWindows
+)
+
+// wslMountRoot is the directory at which WSL mounts Windows drives by default.
+const wslMountRoot = "/mnt/"
+
+// WithWindowsToWSL returns an option that converts every item from a Windows
+// path name to the corresponding WSL path name before comparison and output.
+// See [WindowsToWSL] for the conversion rules.
+//
+// Items of removal and replacement rules are converted the same way.
+func WithWindowsToWSL() Option[Config] {
+	return func(config Config) Config {
+		config.wsl = wslFromWindows
+
+		return config
+	}
+}
+
+// WithWSLToWindows returns an option that converts every item from a WSL path
+// name to the corresponding Windows path name before comparison and output.
+// See [WSLToWindows] for the conversion rules.
+//
+// Items of removal and replacement rules are converted the same way.
+func WithWSLToWindows() Option[Config] {
+	return func(config Config) Config {
+		config.wsl = wslToWindows
+
+		return config
+	}
+}
+
+// WindowsToWSL returns the path name within the Windows Subsystem for Linux
+// that refers to Windows path name s, like "wslpath -u".
+//
+// Absolute drive paths are converted to paths under the default mount root,
+// e.g., `C:\Users\me` to "/mnt/c/Users/me", and relative paths have their
+// separators converted, e.g., `a\b` to "a/b".
+// Path names that cannot be converted without further knowledge of the
+// system, such as UNC paths, are returned unmodified.
+func WindowsToWSL(s string) string {
+	s = trimExtendedPrefix(s)
+
+	t := strings.ReplaceAll(s, "/", `\`)
+	vol := windowsVolume(t)
+	rest := t[len(vol):]
+
+	switch {
+	case len(vol) == 2 && strings.HasPrefix(rest, `\`):
+		return wslMountRoot + strings.ToLower(vol[:1]) +
+			strings.ReplaceAll(rest, `\`, "/")
+	case vol == "" && !strings.HasPrefix(rest, `\`):
+		return strings.ReplaceAll(rest, `\`, "/")
+	default:
+		return s // rooted on an unknown drive, drive-relative, or UNC path
+	}
+}
+
+// WSLToWindows returns the Windows path name that refers to path name s
+// within the Windows Subsystem for Linux, like "wslpath -w".
+//
+// Paths under the default mount root are converted to absolute drive paths,
+// e.g., "/mnt/c/Users/me" to `C:\Users\me`, and relative paths have their
+// separators converted, e.g., "a/b" to `a\b`.
+// Other absolute path names, which refer to the WSL file system itself,
+// are returned unmodified.
+func WSLToWindows(s string) string {
+	if !strings.HasPrefix(s, "/") {
+		return strings.ReplaceAll(s, "/", `\`)
+	}
+
+	rest, ok := strings.CutPrefix(s, wslMountRoot)
+	if !ok || rest == "" || !isASCIILetter(rest[0]) ||
+		len(rest) > 1 && rest[1] != '/' {
+		return s
+	}
+
+	return strings.ToUpper(rest[:1]) + `:\` +
+		strings.ReplaceAll(strings.TrimLeft(rest[1:], "/"), "/", `\`)
+}
+
+// isASCIILetter reports whether b is an ASCII letter.
+func isASCIILetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
@@ -0,0 +1,286 @@
+package mung
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWithWindowsPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+	}{
+		{
+			name:    "collapse_duplicates",
+			initial: Config{subject: []string{`c:/Windows/;C:\Windows;C:\Windows\\`}},
+			want:    []string{`C:\Windows`},
+		},
+		{
+			name: "remove_and_replace",
+			initial: Config{
+				subject: []string{`C:\Windows;C:\Temp;d:\bin`},
+				remove:  []string{"c:/Temp/"},
+				replace: map[string]string{"D:/bin": "E:/bin/"},
+			},
+			want: []string{`C:\Windows`, `E:/bin/`},
+		},
+		{
+			name:    "case_sensitive",
+			initial: Config{subject: []string{`C:\Windows;C:\WINDOWS`}},
+			want:    []string{`C:\Windows`, `C:\WINDOWS`},
+		},
+		{
+			name:    "case_fold",
+			initial: Config{subject: []string{`c:/windows;C:\WINDOWS\`}},
+			opts:    []Option[Config]{WithCaseFold()},
+			want:    []string{`C:\windows`},
+		},
+		{
+			name:    "unc_clean",
+			initial: Config{subject: []string{`//server/share/a/../b;\\server\share\b\`}},
+			opts:    []Option[Config]{WithCleanPaths()},
+			want:    []string{`\\server\share\b`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(";"), WithWindowsPaths()}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsPath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{`C:\Windows`, `C:\Windows`},
+		{"c:/Windows/", `C:\Windows`},
+		{`c:\`, `C:\`},
+		{"c:/", `C:\`},
+		{"c:", "C:"},
+		{"c:foo/", `C:foo`},
+		{`\`, `\`},
+		{"/", `\`},
+		{"//", `\\`},
+		{`foo\bar\\`, `foo\bar`},
+		{"1:/x", `1:\x`},
+		{`\\server\share\`, `\\server\share`},
+		{"//server/share/dir/", `\\server\share\dir`},
+		{`\\?\c:\`, `\\?\C:\`},
+		{`\\?\c:\a/b\`, `\\?\C:\a/b`},
+		{`\\?\UNC\server\share\`, `\\?\UNC\server\share`},
+		{`\\.\COM1`, `\\.\COM1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := windowsPath(tt.in, false); got != tt.want {
+				t.Errorf("windowsPath(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsPathClean(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{`C:\a\..\b\.\c\\`, `C:\b\c`},
+		{`C:\..\..`, `C:\`},
+		{"c:a/../../b", `C:..\b`},
+		{"C:", "C:"},
+		{`a\..`, "."},
+		{`\\server\share\..\x`, `\\server\share\x`},
+		{"//server/share/./a//b", `\\server\share\a\b`},
+		{`\\?\C:\a\..\b`, `\\?\C:\a\..\b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := windowsPath(tt.in, true); got != tt.want {
+				t.Errorf("windowsPath(%q, true) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsVolume(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{`C:\x`, "C:"},
+		{`\x`, ""},
+		{`\\server`, `\\server`},
+		{`\\server\share\x`, `\\server\share`},
+		{`\\?\C:\x`, `\\?\C:`},
+		{`\\?\UNC\server\share\x`, `\\?\UNC\server\share`},
+		{`\\?\unc\server`, `\\?\unc\server`},
+		{`\\?\Volume{1}\x`, `\\?\Volume{1}`},
+		{`\\.\PhysicalDrive0`, `\\.\PhysicalDrive0`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := windowsVolume(tt.in); got != tt.want {
+				t.Errorf("windowsVolume(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithIgnoreExtendedPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+	}{
+		{
+			name:    "drive",
+			initial: Config{subject: []string{`\\?\C:\x;C:\x`}},
+			want:    []string{`\\?\C:\x`},
+		},
+		{
+			name:    "unc",
+			initial: Config{subject: []string{`\\server\share;\\?\UNC\server\share`}},
+			want:    []string{`\\server\share`},
+		},
+		{
+			name: "remove",
+			initial: Config{
+				subject: []string{`\\?\C:\x;C:\y`},
+				remove:  []string{`C:\x`},
+			},
+			want: []string{`C:\y`},
+		},
+		{
+			name:    "volume_guid_unchanged",
+			initial: Config{subject: []string{`\\?\Volume{1}\x;Volume{1}\x`}},
+			want:    []string{`\\?\Volume{1}\x`, `Volume{1}\x`},
+		},
+		{
+			name:    "with_windows_paths",
+			initial: Config{subject: []string{`\\?\c:\x\;c:/x`}},
+			opts:    []Option[Config]{WithWindowsPaths()},
+			want:    []string{`\\?\C:\x`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option[Config]{WithDelim(";"), WithIgnoreExtendedPrefix()}, tt.opts...)
+			config := Wrap(tt.initial, opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsToWSL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{`C:\Users\me`, "/mnt/c/Users/me"},
+		{"c:/Users/me/", "/mnt/c/Users/me/"},
+		{`D:\`, "/mnt/d/"},
+		{`\\?\C:\x`, "/mnt/c/x"},
+		{`a\b`, "a/b"},
+		{"/usr/bin", "/usr/bin"},
+		{`\Windows`, `\Windows`},
+		{`C:x`, `C:x`},
+		{`\\server\share\x`, `\\server\share\x`},
+		{`\\?\Volume{1}\x`, `\\?\Volume{1}\x`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := WindowsToWSL(tt.in); got != tt.want {
+				t.Errorf("WindowsToWSL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWSLToWindows(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"/mnt/c/Users/me", `C:\Users\me`},
+		{"/mnt/d", `D:\`},
+		{"/mnt/d/", `D:\`},
+		{"a/b", `a\b`},
+		{"/usr/bin", "/usr/bin"},
+		{"/mnt/", "/mnt/"},
+		{"/mnt/cd/x", "/mnt/cd/x"},
+		{"/mnt/1/x", "/mnt/1/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := WSLToWindows(tt.in); got != tt.want {
+				t.Errorf("WSLToWindows(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithWSLConversion(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial Config
+		opts    []Option[Config]
+		want    []string
+	}{
+		{
+			name:    "windows_to_wsl",
+			initial: Config{subject: []string{`C:\Windows;/usr/bin;/mnt/c/Windows`}},
+			opts:    []Option[Config]{WithDelim(";"), WithWindowsToWSL()},
+			want:    []string{"/mnt/c/Windows", "/usr/bin"},
+		},
+		{
+			name: "wsl_to_windows",
+			initial: Config{
+				subject: []string{"/mnt/c/Windows:/mnt/c/Temp"},
+				remove:  []string{"/mnt/c/Temp"},
+			},
+			opts: []Option[Config]{WithDelim(":"), WithWSLToWindows()},
+			want: []string{`C:\Windows`},
+		},
+		{
+			name:    "wsl_to_windows_paths",
+			initial: Config{subject: []string{`/mnt/c/Windows/;c:\Windows`}},
+			opts:    []Option[Config]{WithDelim(";"), WithWSLToWindows(), WithWindowsPaths()},
+			want:    []string{`C:\Windows`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Wrap(tt.initial, tt.opts...)
+			got := slices.Collect(config.All())
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("All() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}